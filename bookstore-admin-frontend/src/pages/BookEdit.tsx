@@ -193,7 +193,7 @@ const BookEdit: React.FC = () => {
           layout="vertical"
           onFinish={handleSubmit}
           initialValues={{
-            discount: 100,
+            discount: 0,
             stock: 0,
             language: '中文',
             format: '平装',
@@ -260,10 +260,12 @@ const BookEdit: React.FC = () => {
                 label="销售量"
                 name="sale"
                 rules={[{ required: true, message: '请输入销售量' }]}
+                extra={isEdit ? '销售量由订单自动维护，编辑时不可修改' : undefined}
               >
                 <InputNumber
                   placeholder="请输入销售量"
                   min={0}
+                  disabled={isEdit}
                   style={{ width: '100%', borderRadius: 6 }}
                 />
               </Form.Item>
@@ -276,6 +278,7 @@ const BookEdit: React.FC = () => {
                 label="折扣 (%)"
                 name="discount"
                 rules={[{ required: true, message: '请输入折扣' }]}
+                extra="减免的百分比，0表示无折扣，如10表示打九折"
               >
                 <InputNumber
                   placeholder="请输入折扣"
@@ -170,13 +170,14 @@ const BookList: React.FC = () => {
       width: 100,
       render: (price: number, record: Book) => {
         const originalPrice = price; // 已经是元单位
-        const discountedPrice = Math.floor(originalPrice * (100 - record.discount) / 100); // 计算折扣价
+        const hasDiscount = record.discount > 0 && record.discount < 100; // 折扣为减免的百分比，0表示无折扣
+        const discountedPrice = hasDiscount ? Math.floor(originalPrice * (100 - record.discount) / 100) : originalPrice; // 计算折扣价
         return (
           <div>
             <div style={{ color: '#ff4d4f', fontWeight: 500 }}>
               ¥{discountedPrice}
             </div>
-            {record.discount > 0 && (
+            {hasDiscount && (
               <div style={{ fontSize: 12, color: '#999', textDecoration: 'line-through' }}>
                 ¥{originalPrice}
               </div>
@@ -14,11 +14,11 @@ const BookCard = ({ book }) => {
   const [favoriteLoading, setFavoriteLoading] = useState(false);
 
   // 计算折扣价格（价格以元为单位，折扣以百分比为单位）
-  // 折扣是基于原价的折扣，例如：100元打10%折扣 = 90元
-  const discountedPrice = book.discount > 0 
+  // 折扣是基于原价的折扣，例如：100元打10%折扣 = 90元，0或100及以上视为无折扣，与服务端计算方式一致
+  const hasDiscount = book.discount > 0 && book.discount < 100;
+  const discountedPrice = hasDiscount
     ? Math.floor(book.price * (100 - book.discount) / 100)
     : book.price;
-  const hasDiscount = book.discount > 0;
   
   // 检查是否缺货
   const outOfStock = book.stock <= 0;
@@ -63,6 +63,18 @@ const cartReducer = (state, action) => {
       };
       break;
     
+    case 'UPDATE_PRICES':
+      // payload为以图书ID为键的最新单价
+      newState = {
+        ...state,
+        items: state.items.map(item =>
+          action.payload[item.id] !== undefined
+            ? { ...item, currentPrice: action.payload[item.id] }
+            : item
+        )
+      };
+      break;
+    
     case 'CLEAR_CART':
       newState = {
         ...state,
@@ -94,6 +106,10 @@ export const CartProvider = ({ children }) => {
     dispatch({ type: 'UPDATE_QUANTITY', payload: { id: bookId, quantity } });
   };
 
+  const updatePrices = (prices) => {
+    dispatch({ type: 'UPDATE_PRICES', payload: prices });
+  };
+
   const clearCart = () => {
     dispatch({ type: 'CLEAR_CART' });
   };
@@ -111,6 +127,7 @@ export const CartProvider = ({ children }) => {
     addToCart,
     removeFromCart,
     updateQuantity,
+    updatePrices,
     clearCart,
     getTotalItems,
     getTotalPrice
@@ -116,8 +116,11 @@ const BookDetailPage = () => {
     );
   }
 
-  const discountedPrice = Math.round(book.price * book.discount / 100);
-  const hasDiscount = book.discount < 100;
+  // 折扣为减免的百分比，与服务端计算方式一致：0或100及以上视为无折扣，折后价向下取整
+  const hasDiscount = book.discount > 0 && book.discount < 100;
+  const discountedPrice = hasDiscount
+    ? Math.floor(book.price * (100 - book.discount) / 100)
+    : book.price;
   const outOfStock = book.stock <= 0;
 
   return (
@@ -148,7 +151,7 @@ const BookDetailPage = () => {
                 <p className="placeholder-text">暂无封面</p>
               </div>
               {outOfStock && <div className="out-of-stock-badge">缺货</div>}
-              {hasDiscount && <div className="discount-badge">{100 - book.discount}% OFF</div>}
+              {hasDiscount && <div className="discount-badge">{book.discount}% OFF</div>}
             </div>
           </div>
 
@@ -73,7 +73,12 @@ const OrderHistoryPage = () => {
     const statusMap = {
       0: { text: '待支付', color: '#FF6B6B' },
       1: { text: '已支付', color: '#4ECDC4' },
-      2: { text: '已取消', color: '#95A5A6' }
+      2: { text: '已取消', color: '#95A5A6' },
+      3: { text: '已发货', color: '#5DADE2' },
+      4: { text: '已完成', color: '#58D68D' },
+      5: { text: '退款中', color: '#F5B041' },
+      6: { text: '已退款', color: '#AAB7B8' },
+      7: { text: '部分退款', color: '#F8C471' }
     };
     return statusMap[status] || { text: '未知状态', color: '#95A5A6' };
   };
@@ -9,13 +9,27 @@ const PaymentPage = () => {
   const navigate = useNavigate();
   const location = useLocation();
   const { user } = useUser();
-  const { clearCart, items } = useCart();
+  const { clearCart, updatePrices, items } = useCart();
   
   const [order, setOrder] = useState(null);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState(null);
   const [paymentMethod, setPaymentMethod] = useState('alipay');
   const [showSuccessModal, setShowSuccessModal] = useState(false);
+  const [addresses, setAddresses] = useState([]);
+  const [selectedAddressId, setSelectedAddressId] = useState(null);
+  const [showAddressForm, setShowAddressForm] = useState(false);
+  const [addressForm, setAddressForm] = useState({
+    receiver_name: '',
+    phone: '',
+    province: '',
+    city: '',
+    district: '',
+    detail: ''
+  });
+  const [addressError, setAddressError] = useState(null);
+  const [submitting, setSubmitting] = useState(false);
+  const [priceChanges, setPriceChanges] = useState([]);
 
   useEffect(() => {
     if (!user) {
@@ -27,8 +41,8 @@ const PaymentPage = () => {
     if (orderId) {
       fetchOrderDetails();
     } else {
-      // 从购物车创建订单
-      createOrderFromCart();
+      // 从购物车下单，先选择收货地址
+      fetchAddresses();
     }
   }, [orderId, user]);
 
@@ -54,14 +68,96 @@ const PaymentPage = () => {
     }
   };
 
+  const fetchAddresses = async () => {
+    if (!items || items.length === 0) {
+      setError('购物车为空');
+      setLoading(false);
+      return;
+    }
+
+    try {
+      const token = localStorage.getItem('token');
+      const response = await fetch('http://localhost:8080/api/v1/user/addresses', {
+        headers: {
+          'Authorization': `Bearer ${token}`
+        }
+      });
+
+      const data = await response.json();
+      if (data.code === 0) {
+        const list = data.data || [];
+        setAddresses(list);
+        // 默认选中默认地址，没有默认地址时选中第一个
+        const defaultAddress = list.find(address => address.is_default) || list[0];
+        setSelectedAddressId(defaultAddress ? defaultAddress.id : null);
+        setShowAddressForm(list.length === 0);
+      } else {
+        setError(data.message);
+      }
+    } catch (err) {
+      setError('获取收货地址失败');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleAddressFormChange = (e) => {
+    const { name, value } = e.target;
+    setAddressForm(prev => ({ ...prev, [name]: value }));
+  };
+
+  const handleCreateAddress = async (e) => {
+    e.preventDefault();
+    setAddressError(null);
+
+    try {
+      const token = localStorage.getItem('token');
+      const response = await fetch('http://localhost:8080/api/v1/user/addresses', {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+          'Authorization': `Bearer ${token}`
+        },
+        body: JSON.stringify({
+          ...addressForm,
+          is_default: addresses.length === 0
+        })
+      });
+
+      const data = await response.json();
+      if (data.code === 0) {
+        setAddresses(prev => [...prev, data.data]);
+        setSelectedAddressId(data.data.id);
+        setShowAddressForm(false);
+        setAddressForm({
+          receiver_name: '',
+          phone: '',
+          province: '',
+          city: '',
+          district: '',
+          detail: ''
+        });
+      } else {
+        setAddressError(data.error || data.message);
+      }
+    } catch (err) {
+      setAddressError('添加收货地址失败');
+    }
+  };
+
   const createOrderFromCart = async () => {
+    if (!selectedAddressId) {
+      setAddressError('请选择收货地址');
+      return;
+    }
+
+    setSubmitting(true);
     try {
       // 从购物车上下文获取商品，而不是从location.state
       const cartItems = items || [];
       
       if (cartItems.length === 0) {
         setError('购物车为空');
-        setLoading(false);
         return;
       }
 
@@ -79,6 +175,7 @@ const PaymentPage = () => {
           'Authorization': `Bearer ${token}`
         },
         body: JSON.stringify({
+          address_id: selectedAddressId,
           items: orderItems
         })
       });
@@ -86,18 +183,27 @@ const PaymentPage = () => {
       const data = await response.json();
       if (data.code === 0) {
         setOrder(data.data);
+        setPriceChanges([]);
         // 清空购物车
         clearCart();
+      } else if (response.status === 409 && data.data?.changed_items) {
+        // 商品价格已变动：按服务端最新价格更新购物车，由用户确认后重新提交
+        const changedItems = data.data.changed_items;
+        updatePrices(Object.fromEntries(changedItems.map(item => [item.book_id, item.current_price])));
+        setPriceChanges(changedItems);
       } else {
         setError(data.message);
       }
     } catch (err) {
       setError('创建订单失败');
     } finally {
-      setLoading(false);
+      setSubmitting(false);
     }
   };
 
+  const formatAddress = (address) =>
+    `${address.province}${address.city}${address.district || ''}${address.detail}`;
+
   const handlePayment = async () => {
     if (!order) return;
 
@@ -111,14 +217,27 @@ const PaymentPage = () => {
       });
 
       const data = await response.json();
-      if (data.code === 0) {
+      if (data.code !== 0) {
+        setError(data.message);
+        return;
+      }
+
+      // 前往支付渠道完成支付（模拟渠道直接确认付款），订单在渠道回调后标记为已支付
+      const payResponse = await fetch(`http://localhost:8080${data.data.pay_url}`, {
+        method: 'POST',
+        headers: {
+          'Authorization': `Bearer ${token}`
+        }
+      });
+      const payData = await payResponse.json();
+      if (payData.code === 0) {
         setShowSuccessModal(true);
         // 3秒后跳转到订单列表
         setTimeout(() => {
           navigate('/orders');
         }, 3000);
       } else {
-        setError(data.message);
+        setError(payData.error || payData.message);
       }
     } catch (err) {
       setError('支付失败');
@@ -149,6 +268,120 @@ const PaymentPage = () => {
     );
   }
 
+  // 从购物车下单时，订单创建前先确认商品和收货地址
+  if (!order && !orderId) {
+    const cartTotal = items.reduce((total, item) => total + item.currentPrice * item.quantity, 0);
+
+    return (
+      <div className="payment-page">
+        <div className="payment-container">
+          <div className="payment-header">
+            <h2>确认订单</h2>
+          </div>
+
+          <div className="payment-content">
+            <div className="address-section">
+              <h3>收货地址</h3>
+              <div className="address-options">
+                {addresses.map(address => (
+                  <label key={address.id} className="address-option">
+                    <input
+                      type="radio"
+                      name="address"
+                      value={address.id}
+                      checked={selectedAddressId === address.id}
+                      onChange={() => setSelectedAddressId(address.id)}
+                    />
+                    <div className="address-info">
+                      <p className="address-receiver">
+                        {address.receiver_name} {address.phone}
+                        {address.is_default && <span className="address-default">默认</span>}
+                      </p>
+                      <p className="address-detail">{formatAddress(address)}</p>
+                    </div>
+                  </label>
+                ))}
+              </div>
+
+              {showAddressForm ? (
+                <form className="address-form" onSubmit={handleCreateAddress}>
+                  <input name="receiver_name" placeholder="收货人" value={addressForm.receiver_name} onChange={handleAddressFormChange} required />
+                  <input name="phone" placeholder="手机号" value={addressForm.phone} onChange={handleAddressFormChange} required />
+                  <input name="province" placeholder="省份" value={addressForm.province} onChange={handleAddressFormChange} required />
+                  <input name="city" placeholder="城市" value={addressForm.city} onChange={handleAddressFormChange} required />
+                  <input name="district" placeholder="区县" value={addressForm.district} onChange={handleAddressFormChange} />
+                  <input name="detail" placeholder="详细地址" value={addressForm.detail} onChange={handleAddressFormChange} required />
+                  <div className="address-form-actions">
+                    {addresses.length > 0 && (
+                      <button type="button" onClick={() => setShowAddressForm(false)} className="cancel-btn">
+                        取消
+                      </button>
+                    )}
+                    <button type="submit" className="pay-btn">保存地址</button>
+                  </div>
+                </form>
+              ) : (
+                <button onClick={() => setShowAddressForm(true)} className="add-address-btn">
+                  + 新增收货地址
+                </button>
+              )}
+              {addressError && <p className="error-message">{addressError}</p>}
+            </div>
+
+            {priceChanges.length > 0 && (
+              <div className="price-changed-notice">
+                <p>以下商品价格已变动，已按最新价格更新，请确认后重新提交订单：</p>
+                <ul>
+                  {priceChanges.map(item => (
+                    <li key={item.book_id}>
+                      {item.title}：¥{item.client_price} → ¥{item.current_price}
+                    </li>
+                  ))}
+                </ul>
+              </div>
+            )}
+
+            <div className="order-summary">
+              <h3>商品清单</h3>
+              <div className="order-items">
+                {items.map(item => (
+                  <div key={item.id} className="order-item">
+                    <div className="item-info">
+                      <h4>{item.title}</h4>
+                      <p>{item.author}</p>
+                    </div>
+                    <div className="item-details">
+                      <span className="item-quantity">x{item.quantity}</span>
+                      <span className="item-price">¥{item.currentPrice}</span>
+                      <span className="item-subtotal">¥{item.currentPrice * item.quantity}</span>
+                    </div>
+                  </div>
+                ))}
+              </div>
+              <div className="order-total">
+                <span>总计：</span>
+                <span className="total-amount">¥{cartTotal}</span>
+              </div>
+            </div>
+
+            <div className="payment-actions">
+              <button onClick={() => navigate('/cart')} className="cancel-btn">
+                返回购物车
+              </button>
+              <button
+                onClick={createOrderFromCart}
+                className="pay-btn"
+                disabled={submitting || !selectedAddressId}
+              >
+                {submitting ? '正在提交...' : priceChanges.length > 0 ? '确认新价格并提交' : '提交订单'}
+              </button>
+            </div>
+          </div>
+        </div>
+      </div>
+    );
+  }
+
   if (!order) {
     return (
       <div className="payment-page">
@@ -194,6 +427,16 @@ const PaymentPage = () => {
             </div>
           </div>
 
+          {order.shipping_address && (
+            <div className="address-section">
+              <h3>收货地址</h3>
+              <p className="address-receiver">
+                {order.shipping_address.receiver_name} {order.shipping_address.phone}
+              </p>
+              <p className="address-detail">{formatAddress(order.shipping_address)}</p>
+            </div>
+          )}
+
           <div className="payment-methods">
             <h3>选择支付方式</h3>
             <div className="method-options">
@@ -13,6 +13,7 @@ const SearchPage = () => {
   const [error, setError] = useState(null);
   const [totalPages, setTotalPages] = useState(1);
   const [currentPage, setCurrentPage] = useState(1);
+  const [truncated, setTruncated] = useState(false);
 
   useEffect(() => {
     if (query) {
@@ -35,6 +36,7 @@ const SearchPage = () => {
       if (data.code === 0) {
         setBooks(data.data.books || []);
         setTotalPages(data.data.total_page || 1);
+        setTruncated(!!data.data.truncated);
       } else {
         setError(data.message || '搜索失败');
       }
@@ -86,6 +88,11 @@ const SearchPage = () => {
                 {totalPages > 1 && `，共 ${totalPages} 页`}
               </p>
             )}
+            {!loading && truncated && (
+              <p className="search-truncated">
+                相关结果过多，仅显示最相关的部分，请尝试更具体的关键词
+              </p>
+            )}
           </div>
         </div>
 
@@ -13,6 +13,7 @@ import (
 
 	"bookstore/config"
 	"bookstore/global"
+	"bookstore/service"
 	"bookstore/web/router"
 )
 
@@ -39,6 +40,26 @@ func main() {
 	// 初始化Redis连接
 	global.InitRedis()
 
+	// 加载图书并建立搜索索引，失败时搜索结果为空，不影响其他功能
+	if err := service.InitSearchIndex(); err != nil {
+		log.Printf("建立搜索索引失败: %v", err)
+	}
+	if err := service.InitSuggestIndex(); err != nil {
+		log.Printf("建立搜索建议索引失败: %v", err)
+	}
+
+	// 启动超时订单处理器，自动取消超时未支付的订单
+	orderExpireWorker := service.NewOrderExpireWorker(cfg.Order.PayTimeout, cfg.Order.ExpireInterval)
+	orderExpireWorker.Start()
+
+	// 启动自动确认收货处理器，自动完成发货后超过确认期限的订单
+	orderCompleteWorker := service.NewOrderCompleteWorker(time.Duration(cfg.Order.AutoCompleteDays)*24*time.Hour, cfg.Order.CompleteInterval)
+	orderCompleteWorker.Start()
+
+	// 启动搜索建议重建处理器，定期刷新按销量的排序
+	suggestRefreshWorker := service.NewSuggestRefreshWorker(cfg.Suggest.RefreshInterval)
+	suggestRefreshWorker.Start()
+
 	// 创建等待组，用于等待所有服务器关闭
 	var wg sync.WaitGroup
 
@@ -130,6 +151,14 @@ func main() {
 	// 等待所有服务器关闭完成
 	wg.Wait()
 
+	// 停止后台任务，须在数据库连接关闭前完成
+	log.Println("正在停止超时订单处理器...")
+	orderExpireWorker.Stop()
+	log.Println("正在停止自动确认收货处理器...")
+	orderCompleteWorker.Stop()
+	log.Println("正在停止搜索建议重建处理器...")
+	suggestRefreshWorker.Stop()
+
 	// 清理应用程序资源（数据库连接、Redis连接等）
 	log.Println("正在清理资源...")
 	cleanupResources()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -68,12 +69,173 @@ func (rc *RedisConfig) Validate() error {
 	return nil
 }
 
+// OrderConfig 定义订单相关配置
+// 包含未支付订单自动过期的时间窗口、发货后自动确认收货的天数及对应的扫描间隔，以及订单号生成方式
+type OrderConfig struct {
+	PayTimeout       time.Duration `yaml:"pay_timeout"`        // 未支付订单的过期时间，如30m，默认30分钟
+	ExpireInterval   time.Duration `yaml:"expire_interval"`    // 过期订单扫描间隔，如1m，默认1分钟
+	AutoCompleteDays int           `yaml:"auto_complete_days"` // 最后一次发货后自动确认收货的天数，默认7天
+	CompleteInterval time.Duration `yaml:"complete_interval"`  // 自动确认收货扫描间隔，如10m，默认10分钟
+	NoGenerator      string        `yaml:"no_generator"`       // 订单号、支付单号和售后单号生成器，redis为按日递增序号，snowflake为雪花算法，默认redis
+	NodeID           int           `yaml:"node_id"`            // 雪花算法的节点ID（0-1023），多实例部署时每个实例必须不同
+}
+
+// Validate 验证订单配置，并为未配置的字段填充默认值
+// 返回:
+//
+//	error - 如果任何字段无效则返回错误
+func (oc *OrderConfig) Validate() error {
+	if oc.PayTimeout < 0 {
+		return fmt.Errorf("order pay_timeout must not be negative")
+	}
+	if oc.ExpireInterval < 0 {
+		return fmt.Errorf("order expire_interval must not be negative")
+	}
+	if oc.AutoCompleteDays < 0 {
+		return fmt.Errorf("order auto_complete_days must not be negative")
+	}
+	if oc.CompleteInterval < 0 {
+		return fmt.Errorf("order complete_interval must not be negative")
+	}
+	if oc.NodeID < 0 || oc.NodeID > 1023 {
+		return fmt.Errorf("order node_id must be between 0 and 1023")
+	}
+	switch oc.NoGenerator {
+	case "":
+		oc.NoGenerator = "redis"
+	case "redis", "snowflake":
+	default:
+		return fmt.Errorf("order no_generator must be redis or snowflake")
+	}
+	if oc.PayTimeout == 0 {
+		oc.PayTimeout = 30 * time.Minute
+	}
+	if oc.ExpireInterval == 0 {
+		oc.ExpireInterval = time.Minute
+	}
+	if oc.AutoCompleteDays == 0 {
+		oc.AutoCompleteDays = 7
+	}
+	if oc.CompleteInterval == 0 {
+		oc.CompleteInterval = 10 * time.Minute
+	}
+	return nil
+}
+
+// PaymentConfig 定义支付相关配置
+// 包含当前使用的支付渠道及各渠道的签名密钥
+type PaymentConfig struct {
+	Provider   string `yaml:"provider"`    // 支付渠道名称，默认mock
+	MockSecret string `yaml:"mock_secret"` // 模拟支付渠道的回调签名密钥
+}
+
+// Validate 验证支付配置，并为未配置的字段填充默认值
+// 返回:
+//
+//	error - 如果任何必填字段为空则返回错误
+func (pc *PaymentConfig) Validate() error {
+	if pc.Provider == "" {
+		pc.Provider = "mock"
+	}
+	if pc.Provider == "mock" && pc.MockSecret == "" {
+		return fmt.Errorf("payment mock_secret is required when provider is mock")
+	}
+	return nil
+}
+
+// CartConfig 定义购物车相关配置
+// 包含未登录访客购物车的保留时间
+type CartConfig struct {
+	GuestTTL time.Duration `yaml:"guest_ttl"` // 访客购物车最后一次修改后的保留时间，如168h，默认7天
+}
+
+// Validate 验证购物车配置，并为未配置的字段填充默认值
+// 返回:
+//
+//	error - 如果任何字段无效则返回错误
+func (cc *CartConfig) Validate() error {
+	if cc.GuestTTL < 0 {
+		return fmt.Errorf("cart guest_ttl must not be negative")
+	}
+	if cc.GuestTTL == 0 {
+		cc.GuestTTL = 7 * 24 * time.Hour
+	}
+	return nil
+}
+
+// IdempotencyConfig 定义幂等键相关配置
+// 包含携带Idempotency-Key请求的首次响应保留时间
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl"` // 首次响应的保留时间，保留期内的重试直接重放该响应，如24h，默认24小时
+}
+
+// Validate 验证幂等键配置，并为未配置的字段填充默认值
+// 返回:
+//
+//	error - 如果任何字段无效则返回错误
+func (ic *IdempotencyConfig) Validate() error {
+	if ic.TTL < 0 {
+		return fmt.Errorf("idempotency ttl must not be negative")
+	}
+	if ic.TTL == 0 {
+		ic.TTL = 24 * time.Hour
+	}
+	return nil
+}
+
+// SearchConfig 定义图书搜索相关配置
+// 包含全文搜索使用的引擎
+type SearchConfig struct {
+	Engine string `yaml:"engine"` // 搜索引擎，memory为进程内倒排索引（适合单实例），mysql为ngram全文索引（适合多实例），默认memory
+}
+
+// Validate 验证搜索配置，并为未配置的字段填充默认值
+// 返回:
+//
+//	error - 如果任何字段无效则返回错误
+func (sc *SearchConfig) Validate() error {
+	switch sc.Engine {
+	case "":
+		sc.Engine = "memory"
+	case "memory", "mysql":
+	default:
+		return fmt.Errorf("search engine must be memory or mysql")
+	}
+	return nil
+}
+
+// SuggestConfig 定义搜索建议相关配置
+// 包含搜索建议索引的重建间隔
+type SuggestConfig struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval"` // 从数据库重建搜索建议索引的间隔，用于刷新销量排序，如10m，默认10分钟
+}
+
+// Validate 验证搜索建议配置，并为未配置的字段填充默认值
+// 返回:
+//
+//	error - 如果任何字段无效则返回错误
+func (sc *SuggestConfig) Validate() error {
+	if sc.RefreshInterval < 0 {
+		return fmt.Errorf("suggest refresh_interval must not be negative")
+	}
+	if sc.RefreshInterval == 0 {
+		sc.RefreshInterval = 10 * time.Minute
+	}
+	return nil
+}
+
 // Config 应用程序主配置结构
 // 包含所有子系统的配置信息
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`   // HTTP服务器配置
-	Database DatabaseConfig `yaml:"database"` // 数据库配置
-	Redis    RedisConfig    `yaml:"redis"`    // Redis缓存配置
+	Server      ServerConfig      `yaml:"server"`      // HTTP服务器配置
+	Database    DatabaseConfig    `yaml:"database"`    // 数据库配置
+	Redis       RedisConfig       `yaml:"redis"`       // Redis缓存配置
+	Order       OrderConfig       `yaml:"order"`       // 订单配置
+	Payment     PaymentConfig     `yaml:"payment"`     // 支付配置
+	Cart        CartConfig        `yaml:"cart"`        // 购物车配置
+	Idempotency IdempotencyConfig `yaml:"idempotency"` // 幂等键配置
+	Search      SearchConfig      `yaml:"search"`      // 搜索配置
+	Suggest     SuggestConfig     `yaml:"suggest"`     // 搜索建议配置
 }
 
 // Validate 验证整个应用程序配置
@@ -88,6 +250,24 @@ func (c *Config) Validate() error {
 	if err := c.Redis.Validate(); err != nil {
 		return fmt.Errorf("redis config validation failed: %w", err)
 	}
+	if err := c.Order.Validate(); err != nil {
+		return fmt.Errorf("order config validation failed: %w", err)
+	}
+	if err := c.Payment.Validate(); err != nil {
+		return fmt.Errorf("payment config validation failed: %w", err)
+	}
+	if err := c.Cart.Validate(); err != nil {
+		return fmt.Errorf("cart config validation failed: %w", err)
+	}
+	if err := c.Idempotency.Validate(); err != nil {
+		return fmt.Errorf("idempotency config validation failed: %w", err)
+	}
+	if err := c.Search.Validate(); err != nil {
+		return fmt.Errorf("search config validation failed: %w", err)
+	}
+	if err := c.Suggest.Validate(); err != nil {
+		return fmt.Errorf("suggest config validation failed: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// AddressInfo 收货地址信息
+// 地址簿和订单共用，订单中保存下单时的快照，之后修改地址簿不影响历史订单
+type AddressInfo struct {
+	ReceiverName string `json:"receiver_name" gorm:"not null"` // 收货人姓名
+	Phone        string `json:"phone" gorm:"not null"`         // 收货人手机号
+	Province     string `json:"province" gorm:"not null"`      // 省份
+	City         string `json:"city" gorm:"not null"`          // 城市
+	District     string `json:"district"`                      // 区县
+	Detail       string `json:"detail" gorm:"not null"`        // 详细地址
+	PostalCode   string `json:"postal_code"`                   // 邮政编码
+}
+
+// Address 用户收货地址模型
+type Address struct {
+	ID        int       `json:"id" gorm:"primaryKey"`            // 地址ID
+	UserID    int       `json:"user_id" gorm:"not null"`         // 用户ID
+	IsDefault bool      `json:"is_default" gorm:"default:false"` // 是否为默认地址
+	CreatedAt time.Time `json:"created_at"`                      // 创建时间
+	UpdatedAt time.Time `json:"updated_at"`                      // 更新时间
+
+	// 收货地址信息，字段直接展开到addresses表和JSON中
+	AddressInfo
+}
+
+// TableName 指定Address模型对应的数据库表名
+func (a *Address) TableName() string {
+	return "addresses"
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// 作者在图书中的署名角色
+const (
+	ContributorRoleAuthor     = "author"     // 著者
+	ContributorRoleTranslator = "translator" // 译者
+	ContributorRoleEditor     = "editor"     // 编者
+)
+
+// Author 作者模型
+// 著者、译者和编者统一保存为作者，在图书中的角色由BookAuthor.Role区分
+type Author struct {
+	ID        int       `json:"id" gorm:"primaryKey"`        // 作者ID
+	Name      string    `json:"name" gorm:"not null;unique"` // 作者姓名（唯一）
+	Bio       string    `json:"bio"`                         // 作者简介
+	PhotoURL  string    `json:"photo_url"`                   // 作者照片URL
+	CreatedAt time.Time `json:"created_at"`                  // 创建时间
+	UpdatedAt time.Time `json:"updated_at"`                  // 更新时间
+}
+
+// TableName 指定Author模型对应的数据库表名
+func (a *Author) TableName() string {
+	return "authors"
+}
+
+// AuthorAlias 作者别名
+// 合并重复作者时保留被合并作者的姓名，之后按该姓名关联图书时直接使用合并后的作者
+type AuthorAlias struct {
+	Name     string `json:"name" gorm:"primaryKey"` // 别名
+	AuthorID int    `json:"author_id"`              // 对应的作者ID
+}
+
+// TableName 指定AuthorAlias模型对应的数据库表名
+func (a *AuthorAlias) TableName() string {
+	return "author_aliases"
+}
+
+// BookAuthor 图书与作者的关联
+// 同一作者可以在同一本书中担任多个角色（如既是著者又是编者）
+type BookAuthor struct {
+	BookID   int    `json:"book_id" gorm:"primaryKey"`   // 图书ID
+	AuthorID int    `json:"author_id" gorm:"primaryKey"` // 作者ID
+	Role     string `json:"role" gorm:"primaryKey"`      // 署名角色：author、translator、editor
+	Sort     int    `json:"sort"`                        // 署名顺序，从0开始
+}
+
+// TableName 指定BookAuthor模型对应的数据库表名
+func (b *BookAuthor) TableName() string {
+	return "book_authors"
+}
+
+// BookContributor 图书的署名信息，用于图书详情中链接到作者页
+type BookContributor struct {
+	AuthorID int    `json:"author_id"` // 作者ID
+	Name     string `json:"name"`      // 作者姓名
+	Role     string `json:"role"`      // 署名角色：author、translator、editor
+}
+
+// MergeRequest 合并重复作者或出版社请求
+type MergeRequest struct {
+	TargetID  int   `json:"target_id" binding:"required"`        // 保留的作者或出版社ID
+	SourceIDs []int `json:"source_ids" binding:"required,min=1"` // 被合并并删除的作者或出版社ID
+}
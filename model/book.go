@@ -6,22 +6,29 @@ import "time"
 type Book struct {
 	ID          int       `json:"id" gorm:"primaryKey"`  // 图书ID
 	Title       string    `json:"title" gorm:"not null"` // 图书标题
-	Author      string    `json:"author"`                // 作者
+	Author      string    `json:"author"`                // 作者署名，多人以顿号等分隔，可带“译”“编”等后缀
 	Price       int       `json:"price"`                 // 价格（元）
-	Discount    int       `json:"discount"`              // 折扣（百分比，100表示无折扣）
+	Discount    int       `json:"discount"`              // 折扣（减免的百分比，0表示无折扣）
 	Type        string    `json:"type"`                  // 图书类型
 	Stock       int       `json:"stock"`                 // 库存数量
+	Reserved    int       `json:"reserved"`              // 已被待支付订单预留的数量
 	Status      int       `json:"status"`                // 图书状态：0-下架，1-上架
 	Description string    `json:"description"`           // 图书描述
 	CoverURL    string    `json:"cover_url"`             // 封面图片URL
 	ISBN        string    `json:"isbn"`                  // ISBN号
 	Publisher   string    `json:"publisher"`             // 出版社
+	PublisherID *int      `json:"publisher_id"`          // 出版社ID，由出版社名称关联，nil表示尚未关联
 	PublishDate string    `json:"publish_date"`          // 出版日期
 	Pages       int       `json:"pages"`                 // 页数
 	Language    string    `json:"language"`              // 语言
 	Format      string    `json:"format"`                // 装帧格式
 	CategoryID  uint      `json:"category_id"`           // 分类ID
+	WorkID      *int      `json:"work_id"`               // 所属作品ID，同一作品的不同版本共用，nil表示未归组
+	Edition     string    `json:"edition"`               // 版本说明，如“第2版”“典藏版”
+	SeriesID    *int      `json:"series_id"`             // 所属丛书ID，nil表示不属于丛书
+	Volume      int       `json:"volume"`                // 在丛书中的卷号，0表示未编号
 	Sale        int       `json:"sale"`                  // 销售量
+	Rating      float64   `json:"rating"`                // 评分（0-10分），0表示暂无评分
 	CreatedAt   time.Time `json:"created_at"`            // 创建时间
 	UpdatedAt   time.Time `json:"updated_at"`            // 更新时间
 }
@@ -31,46 +38,77 @@ func (b *Book) TableName() string {
 	return "books"
 }
 
+// AvailableStock 计算图书可售库存
+// 返回:
+//
+//	int - 库存数量减去已预留数量
+func (b *Book) AvailableStock() int {
+	return b.Stock - b.Reserved
+}
+
+// FinalPrice 计算图书折后单价（元）
+// Discount 表示减免的百分比，0或100及以上视为无折扣，结果向下取整，与前台展示保持一致
+// 返回:
+//
+//	int - 折后单价
+func (b *Book) FinalPrice() int {
+	if b.Discount <= 0 || b.Discount >= 100 {
+		return b.Price
+	}
+	return b.Price * (100 - b.Discount) / 100
+}
+
 // BookCreateRequest 创建图书请求
 type BookCreateRequest struct {
-	Title       string `json:"title" binding:"required"`                  // 图书标题
-	Author      string `json:"author" binding:"required"`                 // 作者
-	Price       int    `json:"price" binding:"required,min=0"`            // 价格（元），不能小于0
-	Discount    int    `json:"discount" binding:"required,min=0,max=100"` // 折扣（百分比），0-100之间
-	Type        string `json:"type" binding:"required,min=1"`             // 图书类型
-	Stock       int    `json:"stock" binding:"required,min=0"`            // 库存数量，不能小于0
-	Status      int    `json:"status" binding:"min=0,max=1"`              // 图书状态：0-下架，1-上架
-	CoverURL    string `json:"cover_url"`                                 // 封面图片URL
-	Description string `json:"description"`                               // 图书描述
-	ISBN        string `json:"isbn"`                                      // ISBN号
-	Publisher   string `json:"publisher"`                                 // 出版社
-	PublishDate string `json:"publish_date"`                              // 出版日期
-	Pages       int    `json:"pages"`                                     // 页数
-	Language    string `json:"language"`                                  // 语言
-	Format      string `json:"format"`                                    // 装帧格式
-	CategoryID  uint   `json:"category_id"`                               // 分类ID
-	Sale        int    `json:"sale" binding:"min=0"`                      // 销售量，不能小于0
+	Title       string  `json:"title" binding:"required"`                  // 图书标题
+	Author      string  `json:"author" binding:"required"`                 // 作者
+	Price       int     `json:"price" binding:"required,min=0"`            // 价格（元），不能小于0
+	Discount    int     `json:"discount" binding:"required,min=0,max=100"` // 折扣（百分比），0-100之间
+	Type        string  `json:"type" binding:"required,min=1"`             // 图书类型
+	Stock       int     `json:"stock" binding:"required,min=0"`            // 库存数量，不能小于0
+	Status      int     `json:"status" binding:"min=0,max=1"`              // 图书状态：0-下架，1-上架
+	CoverURL    string  `json:"cover_url"`                                 // 封面图片URL
+	Description string  `json:"description"`                               // 图书描述
+	ISBN        string  `json:"isbn"`                                      // ISBN号
+	Publisher   string  `json:"publisher"`                                 // 出版社
+	PublishDate string  `json:"publish_date"`                              // 出版日期
+	Pages       int     `json:"pages"`                                     // 页数
+	Language    string  `json:"language"`                                  // 语言
+	Format      string  `json:"format"`                                    // 装帧格式
+	CategoryID  uint    `json:"category_id"`                               // 分类ID
+	Sale        int     `json:"sale" binding:"min=0"`                      // 销售量，不能小于0
+	Rating      float64 `json:"rating" binding:"min=0,max=10"`             // 评分（0-10分）
 }
 
 // BookUpdateRequest 更新图书请求
 type BookUpdateRequest struct {
-	Title       string `json:"title"`                            // 图书标题
-	Author      string `json:"author"`                           // 作者
-	Price       int    `json:"price" binding:"min=0"`            // 价格（元），不能小于0
-	Discount    int    `json:"discount" binding:"min=0,max=100"` // 折扣（百分比），0-100之间
-	Type        string `json:"type"`                             // 图书类型
-	Stock       int    `json:"stock" binding:"min=0"`            // 库存数量，不能小于0
-	CoverURL    string `json:"cover_url"`                        // 封面图片URL
-	Description string `json:"description"`                      // 图书描述
-	ISBN        string `json:"isbn"`                             // ISBN号
-	Publisher   string `json:"publisher"`                        // 出版社
-	PublishDate string `json:"publish_date"`                     // 出版日期
-	Pages       int    `json:"pages"`                            // 页数
-	Language    string `json:"language"`                         // 语言
-	Format      string `json:"format"`                           // 装帧格式
-	Status      int    `json:"status" binding:"min=0,max=1"`     // 图书状态：0-下架，1-上架
-	CategoryID  uint   `json:"category_id"`                      // 分类ID
-	Sale        int    `json:"sale" binding:"min=0"`             // 销售量，不能小于0
+	Title       string  `json:"title"`                            // 图书标题
+	Author      string  `json:"author"`                           // 作者
+	Price       int     `json:"price" binding:"min=0"`            // 价格（元），不能小于0
+	Discount    int     `json:"discount" binding:"min=0,max=100"` // 折扣（百分比），0-100之间
+	Type        string  `json:"type"`                             // 图书类型
+	Stock       int     `json:"stock" binding:"min=0"`            // 库存数量，不能小于0
+	CoverURL    string  `json:"cover_url"`                        // 封面图片URL
+	Description string  `json:"description"`                      // 图书描述
+	ISBN        string  `json:"isbn"`                             // ISBN号
+	Publisher   string  `json:"publisher"`                        // 出版社
+	PublishDate string  `json:"publish_date"`                     // 出版日期
+	Pages       int     `json:"pages"`                            // 页数
+	Language    string  `json:"language"`                         // 语言
+	Format      string  `json:"format"`                           // 装帧格式
+	Status      int     `json:"status" binding:"min=0,max=1"`     // 图书状态：0-下架，1-上架
+	CategoryID  uint    `json:"category_id"`                      // 分类ID
+	Sale        int     `json:"sale" binding:"min=0"`             // 销售量，编辑时忽略，由订单流程维护
+	Rating      float64 `json:"rating" binding:"min=0,max=10"`    // 评分（0-10分）
+}
+
+// BookGroupingRequest 设置图书所属作品和丛书请求
+// 各字段整体覆盖图书当前的设置，WorkID或SeriesID为空表示移出作品或丛书
+type BookGroupingRequest struct {
+	WorkID   *int   `json:"work_id"`                  // 所属作品ID
+	Edition  string `json:"edition" binding:"max=50"` // 版本说明
+	SeriesID *int   `json:"series_id"`                // 所属丛书ID
+	Volume   int    `json:"volume" binding:"min=0"`   // 在丛书中的卷号，0表示未编号
 }
 
 // BookListRequest 图书列表请求
@@ -82,6 +120,7 @@ type BookListRequest struct {
 	Type       string `form:"type"`                              // 按类型搜索
 	Status     *int   `form:"status"`                            // 按状态搜索，nil表示不过滤状态
 	CategoryID uint   `form:"category_id"`                       // 按分类ID搜索
+	TagID      int    `form:"tag_id"`                            // 按标签ID搜索
 }
 
 // BookListResponse 图书列表响应
@@ -91,3 +130,58 @@ type BookListResponse struct {
 	TotalPage   int    `json:"total_page"`   // 总页数
 	CurrentPage int    `json:"current_page"` // 当前页码
 }
+
+// 图书列表和搜索结果的排序方式
+const (
+	BookSortRelevance = "relevance"  // 按相关度，仅搜索时有效，搜索的默认排序
+	BookSortPriceAsc  = "price_asc"  // 按折后价从低到高
+	BookSortPriceDesc = "price_desc" // 按折后价从高到低
+	BookSortSale      = "sale"       // 按销量从高到低
+	BookSortNewest    = "newest"     // 按上架时间从新到旧
+	BookSortRating    = "rating"     // 按评分从高到低
+)
+
+// BookQueryRequest 前台图书列表和搜索请求
+type BookQueryRequest struct {
+	Page        int      `form:"page,default=1" binding:"min=1"`                                                   // 页码，从1开始
+	PageSize    int      `form:"page_size,default=12" binding:"min=1,max=100"`                                     // 每页数量，1-100之间
+	Keyword     string   `form:"q"`                                                                                // 搜索关键词，仅搜索时使用
+	CategoryID  uint     `form:"category_id"`                                                                      // 按分类ID筛选
+	AuthorID    int      `form:"author_id"`                                                                        // 按作者ID筛选
+	PublisherID int      `form:"publisher_id"`                                                                     // 按出版社ID筛选
+	Tags        []string `form:"tag"`                                                                              // 按标签别名筛选，可重复传入，需同时带有全部标签
+	Type        string   `form:"type"`                                                                             // 按类型筛选
+	Language    string   `form:"language"`                                                                         // 按语言筛选
+	Format      string   `form:"format"`                                                                           // 按装帧格式筛选
+	Publisher   string   `form:"publisher"`                                                                        // 按出版社筛选
+	MinPrice    *int     `form:"min_price" binding:"omitempty,min=0"`                                              // 最低折后价（元，含）
+	MaxPrice    *int     `form:"max_price" binding:"omitempty,min=0"`                                              // 最高折后价（元，不含）
+	InStock     bool     `form:"in_stock"`                                                                         // 只看有货
+	Sort        string   `form:"sort" binding:"omitempty,oneof=relevance price_asc price_desc sale newest rating"` // 排序方式
+}
+
+// FacetCount 筛选项及其结果数量
+type FacetCount struct {
+	Value string `json:"value"`           // 筛选参数的取值
+	Label string `json:"label,omitempty"` // 展示名称，与取值不同时返回（如分类名称）
+	Count int64  `json:"count"`           // 结果数量
+}
+
+// PriceRangeFacet 价格区间及其结果数量
+type PriceRangeFacet struct {
+	Min   int   `json:"min"`           // 最低折后价（元，含），对应min_price参数
+	Max   *int  `json:"max,omitempty"` // 最高折后价（元，不含），对应max_price参数，最高区间为空
+	Count int64 `json:"count"`         // 结果数量
+}
+
+// BookFacets 图书筛选项统计
+// 每个维度的数量按除该维度外的其他筛选条件统计，便于在侧边栏中切换同一维度的取值
+type BookFacets struct {
+	Categories  []FacetCount      `json:"categories"`   // 按分类统计，取值为分类ID
+	Types       []FacetCount      `json:"types"`        // 按类型统计
+	Languages   []FacetCount      `json:"languages"`    // 按语言统计
+	Formats     []FacetCount      `json:"formats"`      // 按装帧格式统计
+	Publishers  []FacetCount      `json:"publishers"`   // 按出版社统计，只返回数量最多的部分
+	PriceRanges []PriceRangeFacet `json:"price_ranges"` // 按折后价区间统计
+	InStock     int64             `json:"in_stock"`     // 有货的图书数量
+}
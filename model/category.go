@@ -4,18 +4,25 @@ import "time"
 
 // Category 图书分类模型
 // 用于管理图书分类信息，包含分类的基本属性和展示特性
+// 分类按父子关系组成树，Path保存由根分类到自身的ID路径（如"/1/5/12/"），用于按前缀查询整棵子树
 type Category struct {
-	ID          int       `json:"id" gorm:"primaryKey"`          // 分类ID，主键
-	Name        string    `json:"name" gorm:"not null;unique"`   // 分类名称(非空且唯一)
-	Description string    `json:"description"`                   // 分类描述信息
-	Icon        string    `json:"icon"`                          // 分类图标URL或图标标识
-	Color       string    `json:"color"`                         // 分类主色(十六进制颜色码)
-	Gradient    string    `json:"gradient"`                      // 渐变色配置(用于UI展示)
-	Sort        int       `json:"sort" gorm:"default:0"`         // 排序权重(数字越大排序越靠前)
-	IsActive    bool      `json:"is_active" gorm:"default:true"` // 是否启用该分类(默认true)
-	BookCount   int       `json:"book_count" gorm:"default:0"`   // 关联图书数量(统计值)
-	CreatedAt   time.Time `json:"created_at"`                    // 创建时间(GORM自动维护)
-	UpdatedAt   time.Time `json:"updated_at"`                    // 更新时间(GORM自动维护)
+	ID                int       `json:"id" gorm:"primaryKey"`                  // 分类ID，主键
+	Name              string    `json:"name" gorm:"not null;unique"`           // 分类名称(非空且唯一)
+	ParentID          *int      `json:"parent_id" gorm:"index"`                // 父分类ID，nil表示顶级分类
+	Path              string    `json:"path" gorm:"index"`                     // 物化路径，由服务层维护
+	Depth             int       `json:"depth" gorm:"default:0"`                // 层级深度，顶级分类为0
+	Description       string    `json:"description"`                           // 分类描述信息
+	Icon              string    `json:"icon"`                                  // 分类图标URL或图标标识
+	Color             string    `json:"color"`                                 // 分类主色(十六进制颜色码)
+	Gradient          string    `json:"gradient"`                              // 渐变色配置(用于UI展示)
+	Sort              int       `json:"sort" gorm:"default:0"`                 // 排序权重(数字越大排序越靠前)
+	IsActive          bool      `json:"is_active" gorm:"default:true"`         // 是否启用该分类(默认true)
+	BookCount         int       `json:"book_count" gorm:"default:0"`           // 直属该分类的上架图书数量(统计值，由书籍服务维护)
+	OffShelfBookCount int       `json:"off_shelf_book_count" gorm:"default:0"` // 直属该分类的下架图书数量(统计值，由书籍服务维护)
+	CreatedAt         time.Time `json:"created_at"`                            // 创建时间(GORM自动维护)
+	UpdatedAt         time.Time `json:"updated_at"`                            // 更新时间(GORM自动维护)
+
+	Children []*Category `json:"children,omitempty" gorm:"-"` // 子分类，仅分类树接口返回
 }
 
 // TableName 指定GORM使用的表名
@@ -24,3 +31,8 @@ type Category struct {
 func (c *Category) TableName() string {
 	return "categories"
 }
+
+// CategoryMoveRequest 移动分类请求
+type CategoryMoveRequest struct {
+	ParentID *int `json:"parent_id"` // 新的父分类ID，null表示移动为顶级分类
+}
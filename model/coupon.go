@@ -0,0 +1,99 @@
+package model
+
+import "time"
+
+// 优惠券类型
+const (
+	CouponTypeFixed   = "fixed"   // 满减券：减免固定金额
+	CouponTypePercent = "percent" // 折扣券：按百分比减免
+)
+
+// 优惠券适用范围
+const (
+	CouponScopeAll      = "all"      // 全场通用
+	CouponScopeCategory = "category" // 指定分类
+	CouponScopeBook     = "book"     // 指定图书
+)
+
+// 优惠券使用记录状态
+const (
+	CouponRedemptionStatusUsed     = 0 // 已使用
+	CouponRedemptionStatusReleased = 1 // 订单取消后已退回
+)
+
+// Coupon 优惠券模型
+type Coupon struct {
+	ID           int       `json:"id" gorm:"primaryKey"`                       // 优惠券ID
+	Code         string    `json:"code" gorm:"not null;unique"`                // 优惠码，下单时填写
+	Name         string    `json:"name" gorm:"not null"`                       // 优惠券名称
+	Description  string    `json:"description"`                                // 使用说明
+	Type         string    `json:"type" gorm:"not null"`                       // 优惠类型，取值见CouponType*常量
+	Value        int       `json:"value" gorm:"not null"`                      // 满减券为减免金额（元），折扣券为减免百分比
+	MaxDiscount  int       `json:"max_discount" gorm:"default:0"`              // 折扣券最高减免金额（元），0表示不限
+	MinSpend     int       `json:"min_spend" gorm:"default:0"`                 // 适用商品金额门槛（元），0表示无门槛
+	Scope        string    `json:"scope" gorm:"not null"`                      // 适用范围，取值见CouponScope*常量
+	ScopeIDs     []int     `json:"scope_ids" gorm:"serializer:json;type:text"` // 适用的分类ID或图书ID
+	TotalLimit   int       `json:"total_limit" gorm:"default:0"`               // 总使用次数上限，0表示不限
+	PerUserLimit int       `json:"per_user_limit" gorm:"default:0"`            // 每个用户使用次数上限，0表示不限
+	UsedCount    int       `json:"used_count" gorm:"default:0"`                // 已使用次数（不含已退回）
+	StartAt      time.Time `json:"start_at"`                                   // 生效时间
+	EndAt        time.Time `json:"end_at"`                                     // 失效时间
+	IsActive     bool      `json:"is_active" gorm:"default:true"`              // 是否启用
+	CreatedAt    time.Time `json:"created_at"`                                 // 创建时间
+	UpdatedAt    time.Time `json:"updated_at"`                                 // 更新时间
+}
+
+// TableName 指定Coupon模型对应的数据库表名
+func (c *Coupon) TableName() string {
+	return "coupons"
+}
+
+// CouponRedemption 优惠券使用记录模型
+// 每个使用了优惠券的订单对应一条记录，订单取消后记录标记为已退回
+type CouponRedemption struct {
+	ID             int       `json:"id" gorm:"primaryKey"`            // 记录ID
+	CouponID       int       `json:"coupon_id" gorm:"not null"`       // 优惠券ID
+	UserID         int       `json:"user_id" gorm:"not null"`         // 用户ID
+	OrderID        int       `json:"order_id" gorm:"not null"`        // 订单ID
+	DiscountAmount int       `json:"discount_amount" gorm:"not null"` // 优惠金额（元）
+	Status         int       `json:"status" gorm:"default:0"`         // 状态，取值见CouponRedemptionStatus*常量
+	CreatedAt      time.Time `json:"created_at"`                      // 创建时间
+	UpdatedAt      time.Time `json:"updated_at"`                      // 更新时间
+
+	// 关联字段
+	User  *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`   // 关联的用户信息
+	Order *Order `json:"order,omitempty" gorm:"foreignKey:OrderID"` // 关联的订单信息
+}
+
+// TableName 指定CouponRedemption模型对应的数据库表名
+func (r *CouponRedemption) TableName() string {
+	return "coupon_redemptions"
+}
+
+// OrderDiscount 订单优惠明细模型
+// 记录订单使用的每一项优惠及其金额，订单项上的DiscountAmount为该优惠分摊到各商品的金额
+type OrderDiscount struct {
+	ID          int       `json:"id" gorm:"primaryKey"`     // 明细ID
+	OrderID     int       `json:"order_id" gorm:"not null"` // 订单ID
+	CouponID    int       `json:"coupon_id"`                // 优惠券ID
+	CouponCode  string    `json:"coupon_code"`              // 优惠码
+	Description string    `json:"description"`              // 优惠说明，如"满100减20"
+	Amount      int       `json:"amount" gorm:"not null"`   // 优惠金额（元）
+	CreatedAt   time.Time `json:"created_at"`               // 创建时间
+}
+
+// TableName 指定OrderDiscount模型对应的数据库表名
+func (d *OrderDiscount) TableName() string {
+	return "order_discounts"
+}
+
+// CouponStats 优惠券核销统计
+type CouponStats struct {
+	CouponID         int   `json:"coupon_id"`          // 优惠券ID
+	RedemptionCount  int64 `json:"redemption_count"`   // 有效使用次数
+	ReleasedCount    int64 `json:"released_count"`     // 订单取消后退回的次数
+	UserCount        int64 `json:"user_count"`         // 使用过的用户数
+	TotalDiscount    int64 `json:"total_discount"`     // 累计优惠金额（元）
+	TotalOrderAmount int64 `json:"total_order_amount"` // 带动的订单实付金额（元）
+	PaidOrderCount   int64 `json:"paid_order_count"`   // 其中已支付的订单数
+}
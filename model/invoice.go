@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// 发票状态常量
+const (
+	InvoiceStatusIssued = 0 // 已开具
+	InvoiceStatusVoided = 1 // 已作废
+)
+
+// InvoiceItem 发票明细行
+// 开具时从订单项复制，之后图书信息或订单变化不影响已开具的发票
+type InvoiceItem struct {
+	BookID         int    `json:"book_id"`         // 图书ID
+	Title          string `json:"title"`           // 图书标题
+	ISBN           string `json:"isbn"`            // ISBN号
+	Quantity       int    `json:"quantity"`        // 数量
+	UnitPrice      int    `json:"unit_price"`      // 单价（元），即下单时的折后价
+	Subtotal       int    `json:"subtotal"`        // 小计（元）
+	DiscountAmount int    `json:"discount_amount"` // 分摊的优惠金额（元）
+	Amount         int    `json:"amount"`          // 实付金额（元），即小计减去优惠
+}
+
+// Invoice 发票模型
+// 记录已支付订单开具的发票，买方信息和明细在开具时保存快照；
+// 一个订单同一时间最多有一张已开具的发票，重新开具时原发票作废
+type Invoice struct {
+	ID             int           `json:"id" gorm:"primaryKey"`              // 发票ID
+	InvoiceNo      string        `json:"invoice_no" gorm:"not null;unique"` // 发票号
+	OrderID        int           `json:"order_id" gorm:"not null"`          // 订单ID
+	UserID         int           `json:"user_id" gorm:"not null"`           // 用户ID
+	OrderNo        string        `json:"order_no" gorm:"not null"`          // 订单号
+	Status         int           `json:"status" gorm:"default:0"`           // 发票状态，取值见InvoiceStatus*常量
+	BuyerName      string        `json:"buyer_name"`                        // 买方名称，优先使用收货人姓名
+	BuyerAccount   string        `json:"buyer_account"`                     // 买方账号（用户名）
+	BuyerPhone     string        `json:"buyer_phone"`                       // 买方电话
+	BuyerEmail     string        `json:"buyer_email"`                       // 买方邮箱
+	BuyerAddress   string        `json:"buyer_address"`                     // 买方地址
+	Items          []InvoiceItem `json:"items" gorm:"serializer:json"`      // 发票明细
+	SubtotalAmount int           `json:"subtotal_amount"`                   // 商品金额（元）
+	DiscountAmount int           `json:"discount_amount"`                   // 优惠金额（元）
+	TotalAmount    int           `json:"total_amount"`                      // 实付金额（元）
+	RefundedAmount int           `json:"refunded_amount"`                   // 开具时已退款金额（元）
+	PaidAt         *time.Time    `json:"paid_at"`                           // 支付时间
+	IssuedAt       time.Time     `json:"issued_at"`                         // 开具时间
+	IssuedBy       int           `json:"issued_by"`                         // 开具的管理员ID，用户首次查看时自动开具为0
+	VoidedAt       *time.Time    `json:"voided_at"`                         // 作废时间
+	VoidedBy       int           `json:"voided_by"`                         // 作废的管理员ID
+	VoidReason     string        `json:"void_reason"`                       // 作废原因
+	CreatedAt      time.Time     `json:"created_at"`                        // 创建时间
+	UpdatedAt      time.Time     `json:"updated_at"`                        // 更新时间
+}
+
+// TableName 指定Invoice模型对应的数据库表名
+func (i *Invoice) TableName() string {
+	return "invoices"
+}
+
+// InvoiceSequence 发票号序列模型
+// 每个序列（如按年份划分的发票号）保存最后一个已分配的序号，
+// 序号在开具发票的事务中递增，事务回滚时序号一并回滚，保证发票号连续
+type InvoiceSequence struct {
+	Name  string `json:"name" gorm:"primaryKey"` // 序列名称，如2026
+	Value int64  `json:"value" gorm:"not null"`  // 最后一个已分配的序号
+}
+
+// TableName 指定InvoiceSequence模型对应的数据库表名
+func (s *InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
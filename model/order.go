@@ -2,21 +2,85 @@ package model
 
 import "time"
 
+// 订单状态常量
+// 合法的状态流转由service.OrderService中的状态机控制
+const (
+	OrderStatusPending           = 0 // 待支付
+	OrderStatusPaid              = 1 // 已支付
+	OrderStatusCancelled         = 2 // 已取消
+	OrderStatusShipped           = 3 // 已发货
+	OrderStatusCompleted         = 4 // 已完成
+	OrderStatusRefunding         = 5 // 退款中
+	OrderStatusRefunded          = 6 // 已退款
+	OrderStatusPartiallyRefunded = 7 // 部分退款
+)
+
+// 订单状态变更操作人类型
+const (
+	OrderOperatorUser   = "user"   // 用户本人
+	OrderOperatorAdmin  = "admin"  // 管理员
+	OrderOperatorSystem = "system" // 系统自动处理
+)
+
+// OrderCancelReasonTimeout 订单超时未支付被系统取消时记录的原因
+const OrderCancelReasonTimeout = "timeout"
+
+// OrderStatusText 获取订单状态的中文描述
+// 参数:
+//
+//	status - 订单状态
+//
+// 返回:
+//
+//	string - 状态描述，未知状态返回"未知状态"
+func OrderStatusText(status int) string {
+	switch status {
+	case OrderStatusPending:
+		return "待支付"
+	case OrderStatusPaid:
+		return "已支付"
+	case OrderStatusCancelled:
+		return "已取消"
+	case OrderStatusShipped:
+		return "已发货"
+	case OrderStatusCompleted:
+		return "已完成"
+	case OrderStatusRefunding:
+		return "退款中"
+	case OrderStatusRefunded:
+		return "已退款"
+	case OrderStatusPartiallyRefunded:
+		return "部分退款"
+	default:
+		return "未知状态"
+	}
+}
+
 // Order 订单模型
 type Order struct {
-	ID          int        `json:"id" gorm:"primaryKey"`            // 订单ID
-	UserID      int        `json:"user_id" gorm:"not null"`         // 用户ID
-	OrderNo     string     `json:"order_no" gorm:"not null;unique"` // 订单号
-	TotalAmount int        `json:"total_amount" gorm:"not null"`    // 订单总金额（分）
-	Status      int        `json:"status" gorm:"default:0"`         // 订单状态：0-待支付，1-已支付，2-已取消
-	IsPaid      bool       `json:"is_paid" gorm:"default:false"`    // 是否已支付
-	PaymentTime *time.Time `json:"payment_time"`                    // 支付时间
-	CreatedAt   time.Time  `json:"created_at"`                      // 创建时间
-	UpdatedAt   time.Time  `json:"updated_at"`                      // 更新时间
+	ID             int        `json:"id" gorm:"primaryKey"`             // 订单ID
+	UserID         int        `json:"user_id" gorm:"not null"`          // 用户ID
+	OrderNo        string     `json:"order_no" gorm:"not null;unique"`  // 订单号
+	TotalAmount    int        `json:"total_amount" gorm:"not null"`     // 订单应付金额（元），即商品金额减去优惠金额
+	SubtotalAmount int        `json:"subtotal_amount" gorm:"default:0"` // 商品金额（元），即订单项小计之和
+	DiscountAmount int        `json:"discount_amount" gorm:"default:0"` // 优惠金额（元）
+	CouponCode     string     `json:"coupon_code"`                      // 使用的优惠码
+	RefundedAmount int        `json:"refunded_amount" gorm:"default:0"` // 已退款金额
+	Status         int        `json:"status" gorm:"default:0"`          // 订单状态，取值见OrderStatus*常量
+	IsPaid         bool       `json:"is_paid" gorm:"default:false"`     // 是否已支付
+	PaymentTime    *time.Time `json:"payment_time"`                     // 支付时间
+	CreatedAt      time.Time  `json:"created_at"`                       // 创建时间
+	UpdatedAt      time.Time  `json:"updated_at"`                       // 更新时间
+
+	// 下单时的收货地址快照，对应orders表中shipping_前缀的列
+	ShippingAddress AddressInfo `json:"shipping_address" gorm:"embedded;embeddedPrefix:shipping_"`
 
 	// 关联字段
-	User       *User       `json:"user,omitempty" gorm:"foreignKey:UserID"`         // 关联的用户信息
-	OrderItems []OrderItem `json:"order_items,omitempty" gorm:"foreignKey:OrderID"` // 订单项列表
+	User       *User            `json:"user,omitempty" gorm:"foreignKey:UserID"`         // 关联的用户信息
+	OrderItems []OrderItem      `json:"order_items,omitempty" gorm:"foreignKey:OrderID"` // 订单项列表
+	StatusLogs []OrderStatusLog `json:"status_logs,omitempty" gorm:"foreignKey:OrderID"` // 状态变更记录
+	Shipments  []Shipment       `json:"shipments,omitempty" gorm:"foreignKey:OrderID"`   // 发货记录及物流轨迹
+	Discounts  []OrderDiscount  `json:"discounts,omitempty" gorm:"foreignKey:OrderID"`   // 优惠明细
 }
 
 // TableName 指定Order模型对应的数据库表名
@@ -26,14 +90,16 @@ func (o *Order) TableName() string {
 
 // OrderItem 订单项模型
 type OrderItem struct {
-	ID        int       `json:"id" gorm:"primaryKey"`     // 订单项ID
-	OrderID   int       `json:"order_id" gorm:"not null"` // 订单ID
-	BookID    int       `json:"book_id" gorm:"not null"`  // 图书ID
-	Quantity  int       `json:"quantity" gorm:"not null"` // 购买数量
-	Price     int       `json:"price" gorm:"not null"`    // 单价（分）
-	Subtotal  int       `json:"subtotal" gorm:"not null"` // 小计金额（分）
-	CreatedAt time.Time `json:"created_at"`               // 创建时间
-	UpdatedAt time.Time `json:"updated_at"`               // 更新时间
+	ID               int       `json:"id" gorm:"primaryKey"`               // 订单项ID
+	OrderID          int       `json:"order_id" gorm:"not null"`           // 订单ID
+	BookID           int       `json:"book_id" gorm:"not null"`            // 图书ID
+	Quantity         int       `json:"quantity" gorm:"not null"`           // 购买数量
+	Price            int       `json:"price" gorm:"not null"`              // 单价（元）
+	Subtotal         int       `json:"subtotal" gorm:"not null"`           // 小计金额（元）
+	RefundedQuantity int       `json:"refunded_quantity" gorm:"default:0"` // 已退款数量
+	DiscountAmount   int       `json:"discount_amount" gorm:"default:0"`   // 分摊到该订单项的优惠金额（元）
+	CreatedAt        time.Time `json:"created_at"`                         // 创建时间
+	UpdatedAt        time.Time `json:"updated_at"`                         // 更新时间
 
 	// 关联字段
 	Book *Book `gorm:"foreignKey:BookID" json:"book,omitempty"` // 关联的图书信息
@@ -43,3 +109,51 @@ type OrderItem struct {
 func (oi *OrderItem) TableName() string {
 	return "order_items"
 }
+
+// OrderStatusLog 订单状态变更记录模型
+// 每一次合法的状态流转都会写入一条记录，用于追溯订单历史
+type OrderStatusLog struct {
+	ID         int       `json:"id" gorm:"primaryKey"`     // 记录ID
+	OrderID    int       `json:"order_id" gorm:"not null"` // 订单ID
+	FromStatus int       `json:"from_status"`              // 变更前状态
+	ToStatus   int       `json:"to_status"`                // 变更后状态
+	Operator   string    `json:"operator" gorm:"not null"` // 操作人类型：user/admin/system
+	OperatorID int       `json:"operator_id"`              // 操作人ID，系统操作为0
+	Reason     string    `json:"reason"`                   // 变更原因
+	CreatedAt  time.Time `json:"created_at"`               // 变更时间
+}
+
+// TableName 指定OrderStatusLog模型对应的数据库表名
+func (l *OrderStatusLog) TableName() string {
+	return "order_status_logs"
+}
+
+// OrderListRequest 订单列表请求（管理员）
+type OrderListRequest struct {
+	Page      int    `form:"page" binding:"min=0"`                 // 页码，从1开始
+	PageSize  int    `form:"page_size" binding:"min=0,max=100"`    // 每页数量，1-100之间
+	Status    *int   `form:"status"`                               // 按订单状态筛选，nil表示不过滤状态
+	UserID    int    `form:"user_id"`                              // 按用户ID筛选
+	Username  string `form:"username"`                             // 按用户名搜索
+	OrderNo   string `form:"order_no"`                             // 按订单号搜索
+	StartDate string `form:"start_date"`                           // 下单开始日期，格式2006-01-02
+	EndDate   string `form:"end_date"`                             // 下单结束日期（含当天），格式2006-01-02
+	MinAmount *int   `form:"min_amount" binding:"omitempty,min=0"` // 最小订单金额
+	MaxAmount *int   `form:"max_amount" binding:"omitempty,min=0"` // 最大订单金额
+}
+
+// OrderExportRequest 订单导出请求（管理员）
+type OrderExportRequest struct {
+	Format    string `form:"format" binding:"omitempty,oneof=csv xlsx"` // 导出格式：csv（默认）或xlsx
+	Status    *int   `form:"status"`                                    // 按订单状态筛选，nil表示不过滤状态
+	StartDate string `form:"start_date" binding:"required"`             // 下单开始日期，格式2006-01-02
+	EndDate   string `form:"end_date" binding:"required"`               // 下单结束日期（含当天），格式2006-01-02
+}
+
+// OrderListResponse 订单列表响应（管理员）
+type OrderListResponse struct {
+	Orders      []Order `json:"orders"`       // 订单列表
+	Total       int64   `json:"total"`        // 总数
+	TotalPage   int     `json:"total_page"`   // 总页数
+	CurrentPage int     `json:"current_page"` // 当前页码
+}
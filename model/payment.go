@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// 支付单状态常量
+const (
+	PaymentStatusPending  = 0 // 待支付
+	PaymentStatusSuccess  = 1 // 支付成功
+	PaymentStatusFailed   = 2 // 支付失败
+	PaymentStatusRefunded = 3 // 已全额退款
+	PaymentStatusOrphaned = 4 // 渠道已扣款但订单已取消或已由其他支付单支付，待原路退回
+)
+
+// Payment 支付单模型
+// 记录每一次向支付渠道发起的支付及其回调结果
+type Payment struct {
+	ID              int        `json:"id" gorm:"primaryKey"`              // 支付单ID
+	PaymentNo       string     `json:"payment_no" gorm:"not null;unique"` // 商户支付单号，传给支付渠道
+	OrderID         int        `json:"order_id" gorm:"not null"`          // 订单ID
+	UserID          int        `json:"user_id" gorm:"not null"`           // 用户ID
+	Provider        string     `json:"provider" gorm:"not null"`          // 支付渠道名称
+	TransactionID   string     `json:"transaction_id"`                    // 支付渠道交易号
+	Amount          int        `json:"amount" gorm:"not null"`            // 支付金额（元）
+	RefundedAmount  int        `json:"refunded_amount" gorm:"default:0"`  // 已退款金额（元）
+	Status          int        `json:"status" gorm:"default:0"`           // 支付状态，取值见PaymentStatus*常量
+	CallbackPayload string     `json:"-" gorm:"type:text"`                // 支付渠道回调原始报文
+	PaidAt          *time.Time `json:"paid_at"`                           // 支付成功时间
+	CreatedAt       time.Time  `json:"created_at"`                        // 创建时间
+	UpdatedAt       time.Time  `json:"updated_at"`                        // 更新时间
+}
+
+// TableName 指定Payment模型对应的数据库表名
+func (p *Payment) TableName() string {
+	return "payments"
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Publisher 出版社模型
+type Publisher struct {
+	ID          int       `json:"id" gorm:"primaryKey"`        // 出版社ID
+	Name        string    `json:"name" gorm:"not null;unique"` // 出版社名称（唯一）
+	Description string    `json:"description"`                 // 出版社简介
+	Website     string    `json:"website"`                     // 出版社网站
+	CreatedAt   time.Time `json:"created_at"`                  // 创建时间
+	UpdatedAt   time.Time `json:"updated_at"`                  // 更新时间
+}
+
+// TableName 指定Publisher模型对应的数据库表名
+func (p *Publisher) TableName() string {
+	return "publishers"
+}
+
+// PublisherAlias 出版社别名
+// 合并重复出版社时保留被合并出版社的名称，之后按该名称关联图书时直接使用合并后的出版社
+type PublisherAlias struct {
+	Name        string `json:"name" gorm:"primaryKey"` // 别名
+	PublisherID int    `json:"publisher_id"`           // 对应的出版社ID
+}
+
+// TableName 指定PublisherAlias模型对应的数据库表名
+func (p *PublisherAlias) TableName() string {
+	return "publisher_aliases"
+}
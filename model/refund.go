@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// 售后申请状态常量
+const (
+	RefundStatusPending   = 0 // 待审核
+	RefundStatusApproved  = 1 // 已同意并完成退款
+	RefundStatusRejected  = 2 // 已拒绝
+	RefundStatusRefunding = 3 // 已同意，等待支付渠道退款
+)
+
+// 售后类型常量
+const (
+	RefundTypeRefund = "refund" // 仅退款
+	RefundTypeReturn = "return" // 退货退款
+)
+
+// Refund 售后申请模型
+// 记录用户对已支付订单（整单或部分订单项）发起的退款或退货申请及审核结果
+type Refund struct {
+	ID                  int        `json:"id" gorm:"primaryKey"`             // 售后申请ID
+	RefundNo            string     `json:"refund_no" gorm:"not null;unique"` // 售后单号
+	OrderID             int        `json:"order_id" gorm:"not null"`         // 订单ID
+	UserID              int        `json:"user_id" gorm:"not null"`          // 用户ID
+	Type                string     `json:"type" gorm:"not null"`             // 售后类型：refund/return
+	Reason              string     `json:"reason" gorm:"not null"`           // 申请原因
+	Amount              int        `json:"amount" gorm:"not null"`           // 退款金额（元）
+	Status              int        `json:"status" gorm:"default:0"`          // 审核状态，取值见RefundStatus*常量
+	PrevOrderStatus     int        `json:"prev_order_status"`                // 申请前的订单状态，拒绝后恢复
+	AdminID             int        `json:"admin_id"`                         // 审核管理员ID
+	AdminRemark         string     `json:"admin_remark"`                     // 审核备注
+	PaymentNo           string     `json:"payment_no"`                       // 原路退款的支付单号，为空表示订单没有支付单，由线下退款
+	RefundTransactionID string     `json:"refund_transaction_id"`            // 支付渠道退款交易号
+	ProcessedAt         *time.Time `json:"processed_at"`                     // 审核时间
+	CreatedAt           time.Time  `json:"created_at"`                       // 创建时间
+	UpdatedAt           time.Time  `json:"updated_at"`                       // 更新时间
+
+	// 关联字段
+	Order *Order       `json:"order,omitempty" gorm:"foreignKey:OrderID"`  // 关联的订单信息
+	Items []RefundItem `json:"items,omitempty" gorm:"foreignKey:RefundID"` // 退款的订单项
+}
+
+// TableName 指定Refund模型对应的数据库表名
+func (r *Refund) TableName() string {
+	return "refunds"
+}
+
+// RefundItem 售后申请项模型
+// 记录售后申请涉及的订单项及退款数量
+type RefundItem struct {
+	ID          int       `json:"id" gorm:"primaryKey"`          // 售后申请项ID
+	RefundID    int       `json:"refund_id" gorm:"not null"`     // 售后申请ID
+	OrderItemID int       `json:"order_item_id" gorm:"not null"` // 订单项ID
+	BookID      int       `json:"book_id" gorm:"not null"`       // 图书ID
+	Quantity    int       `json:"quantity" gorm:"not null"`      // 退款数量
+	Amount      int       `json:"amount" gorm:"not null"`        // 退款金额（元）
+	CreatedAt   time.Time `json:"created_at"`                    // 创建时间
+
+	// 关联字段
+	Book *Book `json:"book,omitempty" gorm:"foreignKey:BookID"` // 关联的图书信息
+}
+
+// TableName 指定RefundItem模型对应的数据库表名
+func (ri *RefundItem) TableName() string {
+	return "refund_items"
+}
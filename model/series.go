@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Series 丛书模型
+// 丛书中的每本图书带有卷号，按卷号排列
+type Series struct {
+	ID          int       `json:"id" gorm:"primaryKey"`        // 丛书ID
+	Name        string    `json:"name" gorm:"not null;unique"` // 丛书名称（唯一）
+	Description string    `json:"description"`                 // 丛书简介
+	CreatedAt   time.Time `json:"created_at"`                  // 创建时间
+	UpdatedAt   time.Time `json:"updated_at"`                  // 更新时间
+}
+
+// TableName 指定Series模型对应的数据库表名
+func (s *Series) TableName() string {
+	return "series"
+}
+
+// SeriesRequest 创建或更新丛书请求
+type SeriesRequest struct {
+	Name        string `json:"name" binding:"required,max=100"` // 丛书名称
+	Description string `json:"description"`                     // 丛书简介
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// 物流状态
+const (
+	ShipmentStatusInTransit = 0 // 运输中
+	ShipmentStatusDelivered = 1 // 已签收
+)
+
+// Shipment 发货记录模型
+// 一个订单可以分多个包裹发货，每个包裹对应一条发货记录
+type Shipment struct {
+	ID          int        `json:"id" gorm:"primaryKey"`        // 发货记录ID
+	OrderID     int        `json:"order_id" gorm:"not null"`    // 订单ID
+	Carrier     string     `json:"carrier" gorm:"not null"`     // 承运物流公司
+	TrackingNo  string     `json:"tracking_no" gorm:"not null"` // 物流单号
+	Status      int        `json:"status" gorm:"default:0"`     // 物流状态，取值见ShipmentStatus*常量
+	Remark      string     `json:"remark"`                      // 备注
+	OperatorID  int        `json:"operator_id"`                 // 发货管理员ID
+	ShippedAt   time.Time  `json:"shipped_at"`                  // 发货时间
+	DeliveredAt *time.Time `json:"delivered_at"`                // 签收时间
+	CreatedAt   time.Time  `json:"created_at"`                  // 创建时间
+	UpdatedAt   time.Time  `json:"updated_at"`                  // 更新时间
+
+	// 关联字段
+	Events []ShipmentEvent `json:"events,omitempty" gorm:"foreignKey:ShipmentID"` // 物流轨迹
+}
+
+// TableName 指定Shipment模型对应的数据库表名
+func (s *Shipment) TableName() string {
+	return "shipments"
+}
+
+// ShipmentEvent 物流轨迹模型
+// 记录包裹的每一次状态更新，按时间顺序组成物流时间线
+type ShipmentEvent struct {
+	ID          int       `json:"id" gorm:"primaryKey"`        // 轨迹ID
+	ShipmentID  int       `json:"shipment_id" gorm:"not null"` // 发货记录ID
+	Status      int       `json:"status"`                      // 该节点的物流状态
+	Location    string    `json:"location"`                    // 所在地点
+	Description string    `json:"description" gorm:"not null"` // 轨迹描述
+	OccurredAt  time.Time `json:"occurred_at"`                 // 发生时间
+	CreatedAt   time.Time `json:"created_at"`                  // 创建时间
+}
+
+// TableName 指定ShipmentEvent模型对应的数据库表名
+func (e *ShipmentEvent) TableName() string {
+	return "shipment_events"
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Tag 标签模型
+// 标签与图书为多对多关系，用于分类和类型之外的自由标注，如“获奖作品”“考试用书”
+type Tag struct {
+	ID          int       `json:"id" gorm:"primaryKey"`        // 标签ID
+	Name        string    `json:"name" gorm:"not null;unique"` // 标签名称（唯一）
+	Slug        string    `json:"slug" gorm:"not null;unique"` // 标签别名（唯一），用于链接，只含小写字母、数字和连字符
+	Description string    `json:"description"`                 // 标签说明
+	CreatedAt   time.Time `json:"created_at"`                  // 创建时间
+	UpdatedAt   time.Time `json:"updated_at"`                  // 更新时间
+}
+
+// TableName 指定Tag模型对应的数据库表名
+func (t *Tag) TableName() string {
+	return "tags"
+}
+
+// BookTag 图书与标签的关联
+type BookTag struct {
+	BookID int `json:"book_id" gorm:"primaryKey"` // 图书ID
+	TagID  int `json:"tag_id" gorm:"primaryKey"`  // 标签ID
+}
+
+// TableName 指定BookTag模型对应的数据库表名
+func (b *BookTag) TableName() string {
+	return "book_tags"
+}
+
+// TagRequest 创建或更新标签请求
+type TagRequest struct {
+	Name        string `json:"name" binding:"required,max=50"` // 标签名称
+	Slug        string `json:"slug" binding:"required,max=50"` // 标签别名，只含小写字母、数字和连字符
+	Description string `json:"description" binding:"max=255"`  // 标签说明
+}
+
+// BookTagRequest 批量为图书添加或移除标签请求
+type BookTagRequest struct {
+	BookIDs []int `json:"book_ids" binding:"required,min=1,max=500"` // 图书ID
+	TagIDs  []int `json:"tag_ids" binding:"required,min=1,max=50"`   // 标签ID
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Work 作品模型
+// 同一作品的不同版本（如精装与平装、第一版与第二版）是多条图书记录，通过作品归为一组
+type Work struct {
+	ID          int       `json:"id" gorm:"primaryKey"`  // 作品ID
+	Title       string    `json:"title" gorm:"not null"` // 作品名称
+	Description string    `json:"description"`           // 作品简介
+	CreatedAt   time.Time `json:"created_at"`            // 创建时间
+	UpdatedAt   time.Time `json:"updated_at"`            // 更新时间
+}
+
+// TableName 指定Work模型对应的数据库表名
+func (w *Work) TableName() string {
+	return "works"
+}
+
+// WorkRequest 创建或更新作品请求
+type WorkRequest struct {
+	Title       string `json:"title" binding:"required,max=255"` // 作品名称
+	Description string `json:"description"`                      // 作品简介
+}
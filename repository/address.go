@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+)
+
+// AddressDAO 收货地址数据访问对象
+// 封装了所有与用户收货地址相关的数据库操作
+type AddressDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewAddressDAO 创建新的收货地址DAO实例
+// 返回:
+//
+//	*AddressDAO - 初始化后的收货地址数据访问对象
+func NewAddressDAO() *AddressDAO {
+	return &AddressDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的收货地址DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*AddressDAO - 使用该事务执行所有操作的收货地址数据访问对象
+func (a *AddressDAO) WithTx(tx *gorm.DB) *AddressDAO {
+	return &AddressDAO{db: tx}
+}
+
+// CreateAddress 创建收货地址
+// 参数:
+//
+//	address - 收货地址对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (a *AddressDAO) CreateAddress(address *model.Address) error {
+	// 对应SQL: INSERT INTO addresses (user_id, receiver_name, phone, province, ...) VALUES (...);
+	return a.db.Create(address).Error
+}
+
+// GetUserAddress 获取用户的指定收货地址
+// 参数:
+//
+//	userID - 用户ID
+//	id - 地址ID
+//
+// 返回:
+//
+//	*model.Address - 收货地址对象指针
+//	error - 地址不存在或不属于该用户时返回gorm.ErrRecordNotFound
+func (a *AddressDAO) GetUserAddress(userID, id int) (*model.Address, error) {
+	var address model.Address
+	// 对应SQL: SELECT * FROM addresses WHERE id = id AND user_id = userID LIMIT 1;
+	err := a.db.Where("id = ? AND user_id = ?", id, userID).First(&address).Error
+	return &address, err
+}
+
+// GetDefaultAddress 获取用户的默认收货地址
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	*model.Address - 收货地址对象指针
+//	error - 没有默认地址时返回gorm.ErrRecordNotFound
+func (a *AddressDAO) GetDefaultAddress(userID int) (*model.Address, error) {
+	var address model.Address
+	// 对应SQL: SELECT * FROM addresses WHERE user_id = userID AND is_default = true LIMIT 1;
+	err := a.db.Where("user_id = ? AND is_default = ?", userID, true).First(&address).Error
+	return &address, err
+}
+
+// GetUserAddresses 获取用户的全部收货地址
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	[]*model.Address - 收货地址列表，默认地址排在最前
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AddressDAO) GetUserAddresses(userID int) ([]*model.Address, error) {
+	var addresses []*model.Address
+	// 对应SQL: SELECT * FROM addresses WHERE user_id = userID ORDER BY is_default DESC, id DESC;
+	err := a.db.Where("user_id = ?", userID).Order("is_default DESC, id DESC").Find(&addresses).Error
+	return addresses, err
+}
+
+// CountUserAddresses 统计用户的收货地址数量
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	int64 - 收货地址数量
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AddressDAO) CountUserAddresses(userID int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM addresses WHERE user_id = userID;
+	err := a.db.Model(&model.Address{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// UpdateAddress 更新收货地址
+// 参数:
+//
+//	address - 收货地址对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (a *AddressDAO) UpdateAddress(address *model.Address) error {
+	// 对应SQL: UPDATE addresses SET receiver_name = ?, phone = ?, ..., is_default = ? WHERE id = address.ID;
+	return a.db.Save(address).Error
+}
+
+// DeleteAddress 删除用户的收货地址
+// 参数:
+//
+//	userID - 用户ID
+//	id - 地址ID
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (a *AddressDAO) DeleteAddress(userID, id int) error {
+	// 对应SQL: DELETE FROM addresses WHERE id = id AND user_id = userID;
+	return a.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Address{}).Error
+}
+
+// ClearDefault 取消用户所有地址的默认标记
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (a *AddressDAO) ClearDefault(userID int) error {
+	// 对应SQL: UPDATE addresses SET is_default = false WHERE user_id = userID AND is_default = true;
+	return a.db.Model(&model.Address{}).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		Update("is_default", false).Error
+}
+
+// GetLatestAddress 获取用户最近添加的收货地址
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	*model.Address - 收货地址对象指针
+//	error - 没有地址时返回gorm.ErrRecordNotFound
+func (a *AddressDAO) GetLatestAddress(userID int) (*model.Address, error) {
+	var address model.Address
+	// 对应SQL: SELECT * FROM addresses WHERE user_id = userID ORDER BY id DESC LIMIT 1;
+	err := a.db.Where("user_id = ?", userID).Order("id DESC").First(&address).Error
+	return &address, err
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthorDAO 作者数据访问对象
+// 封装了作者、作者别名以及图书与作者关联的数据库操作
+type AuthorDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewAuthorDAO 创建新的作者DAO实例
+// 返回:
+//
+//	*AuthorDAO - 初始化后的作者数据访问对象
+func NewAuthorDAO() *AuthorDAO {
+	return &AuthorDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的作者DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*AuthorDAO - 使用该事务执行所有操作的作者数据访问对象
+func (a *AuthorDAO) WithTx(tx *gorm.DB) *AuthorDAO {
+	return &AuthorDAO{db: tx}
+}
+
+// AuthorSummary 作者列表项，附带关联的图书数量
+type AuthorSummary struct {
+	model.Author `gorm:"embedded"`
+	BookCount    int64 `json:"book_count"` // 关联的图书数量（含下架图书）
+}
+
+// GetAuthorByID 根据ID获取作者
+// 参数:
+//
+//	id - 作者ID
+//
+// 返回:
+//
+//	*model.Author - 作者对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AuthorDAO) GetAuthorByID(id int) (*model.Author, error) {
+	var author model.Author
+	// 对应SQL: SELECT * FROM authors WHERE id = id LIMIT 1;
+	err := a.db.First(&author, id).Error
+	return &author, err
+}
+
+// GetAuthorsForUpdate 根据ID获取作者并加行锁（需在事务中调用）
+// 参数:
+//
+//	ids - 作者ID列表
+//
+// 返回:
+//
+//	[]*model.Author - 作者对象切片，不存在的ID不返回
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AuthorDAO) GetAuthorsForUpdate(ids []int) ([]*model.Author, error) {
+	var authors []*model.Author
+	// 对应SQL: SELECT * FROM authors WHERE id IN (ids) ORDER BY id FOR UPDATE;
+	err := a.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(&authors).Error
+	return authors, err
+}
+
+// GetAuthorsByPage 分页获取作者列表，按姓名模糊搜索
+// 参数:
+//
+//	keyword - 姓名关键词，为空时不过滤
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*AuthorSummary - 当前页的作者及其图书数量
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AuthorDAO) GetAuthorsByPage(keyword string, page, pageSize int) ([]*AuthorSummary, int64, error) {
+	// Count会修改查询的SELECT子句，每次查询使用新的查询对象
+	query := func() *gorm.DB {
+		q := a.db.Model(&model.Author{})
+		if keyword != "" {
+			q = q.Where("name LIKE ?", "%"+keyword+"%")
+		}
+		return q
+	}
+
+	var total int64
+	// 对应SQL: SELECT COUNT(*) FROM authors [WHERE name LIKE '%keyword%'];
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var authors []*AuthorSummary
+	// 对应SQL: SELECT authors.*, (SELECT COUNT(DISTINCT book_id) FROM book_authors WHERE author_id = authors.id) AS book_count
+	// FROM authors [WHERE name LIKE '%keyword%'] ORDER BY name LIMIT pageSize OFFSET offset;
+	offset := (page - 1) * pageSize
+	err := query().Select("authors.*, (SELECT COUNT(DISTINCT book_id) FROM book_authors WHERE author_id = authors.id) AS book_count").
+		Order("name").Offset(offset).Limit(pageSize).Scan(&authors).Error
+	return authors, total, err
+}
+
+// FindOrCreateAuthor 按姓名或别名查找作者，不存在时创建
+// 参数:
+//
+//	name - 作者姓名
+//
+// 返回:
+//
+//	*model.Author - 作者对象指针
+//	error - 如果查询或创建过程中出现错误则返回错误
+func (a *AuthorDAO) FindOrCreateAuthor(name string) (*model.Author, error) {
+	var alias model.AuthorAlias
+	// 对应SQL: SELECT * FROM author_aliases WHERE name = name LIMIT 1;
+	err := a.db.Where("name = ?", name).Limit(1).Find(&alias).Error
+	if err != nil {
+		return nil, err
+	}
+	if alias.AuthorID > 0 {
+		return a.GetAuthorByID(alias.AuthorID)
+	}
+
+	// 并发创建同名作者时唯一索引冲突，忽略冲突后按姓名重新查询
+	// 对应SQL: INSERT INTO authors (name, ...) VALUES (name, ...) ON DUPLICATE KEY UPDATE id = id;
+	if err := a.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Author{Name: name}).Error; err != nil {
+		return nil, err
+	}
+	var author model.Author
+	// 对应SQL: SELECT * FROM authors WHERE name = name LIMIT 1;
+	err = a.db.Where("name = ?", name).First(&author).Error
+	return &author, err
+}
+
+// GetBookContributors 获取图书的署名信息
+// 参数:
+//
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	[]model.BookContributor - 按署名顺序排列的作者
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AuthorDAO) GetBookContributors(bookID int) ([]model.BookContributor, error) {
+	contributors := make([]model.BookContributor, 0)
+	// 对应SQL: SELECT book_authors.author_id, authors.name, book_authors.role FROM book_authors
+	// JOIN authors ON authors.id = book_authors.author_id WHERE book_authors.book_id = bookID ORDER BY book_authors.sort;
+	err := a.db.Model(&model.BookAuthor{}).
+		Select("book_authors.author_id, authors.name, book_authors.role").
+		Joins("JOIN authors ON authors.id = book_authors.author_id").
+		Where("book_authors.book_id = ?", bookID).
+		Order("book_authors.sort").
+		Scan(&contributors).Error
+	return contributors, err
+}
+
+// GetAuthorRoles 获取作者在指定图书中的署名角色
+// 参数:
+//
+//	authorID - 作者ID
+//	bookIDs - 图书ID列表
+//
+// 返回:
+//
+//	map[int][]string - 以图书ID为键的角色列表
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AuthorDAO) GetAuthorRoles(authorID int, bookIDs []int) (map[int][]string, error) {
+	roles := make(map[int][]string)
+	if len(bookIDs) == 0 {
+		return roles, nil
+	}
+
+	var links []model.BookAuthor
+	// 对应SQL: SELECT * FROM book_authors WHERE author_id = authorID AND book_id IN (bookIDs) ORDER BY sort;
+	err := a.db.Where("author_id = ? AND book_id IN ?", authorID, bookIDs).Order("sort").Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		roles[link.BookID] = append(roles[link.BookID], link.Role)
+	}
+	return roles, nil
+}
+
+// CountBookAuthors 统计图书的作者关联数量
+// 参数:
+//
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	int64 - 关联数量
+//	error - 如果查询过程中出现错误则返回错误
+func (a *AuthorDAO) CountBookAuthors(bookID int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM book_authors WHERE book_id = bookID;
+	err := a.db.Model(&model.BookAuthor{}).Where("book_id = ?", bookID).Count(&count).Error
+	return count, err
+}
+
+// ReplaceBookAuthors 替换图书的全部作者关联
+// 参数:
+//
+//	bookID - 图书ID
+//	links - 新的作者关联，为空时只删除原有关联
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (a *AuthorDAO) ReplaceBookAuthors(bookID int, links []model.BookAuthor) error {
+	// 对应SQL: DELETE FROM book_authors WHERE book_id = bookID;
+	if err := a.db.Where("book_id = ?", bookID).Delete(&model.BookAuthor{}).Error; err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	// 对应SQL: INSERT INTO book_authors (book_id, author_id, role, sort) VALUES (...), (...);
+	return a.db.Create(&links).Error
+}
+
+// MergeAuthors 将多个作者合并到目标作者（需在事务中调用）
+// 被合并作者的图书关联转移到目标作者，姓名和别名保留为目标作者的别名，随后删除被合并作者
+// 参数:
+//
+//	targetID - 保留的作者ID
+//	sourceIDs - 被合并的作者ID列表，不能包含targetID
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (a *AuthorDAO) MergeAuthors(targetID int, sourceIDs []int) error {
+	// 目标作者已有相同角色的关联时忽略重复的关联
+	// 对应SQL: INSERT IGNORE INTO book_authors (book_id, author_id, role, sort)
+	//          SELECT book_id, targetID, role, sort FROM book_authors WHERE author_id IN (sourceIDs);
+	err := a.db.Exec("INSERT IGNORE INTO book_authors (book_id, author_id, role, sort) "+
+		"SELECT book_id, ?, role, sort FROM book_authors WHERE author_id IN ?", targetID, sourceIDs).Error
+	if err != nil {
+		return err
+	}
+	// 对应SQL: DELETE FROM book_authors WHERE author_id IN (sourceIDs);
+	if err := a.db.Where("author_id IN ?", sourceIDs).Delete(&model.BookAuthor{}).Error; err != nil {
+		return err
+	}
+
+	// 对应SQL: UPDATE author_aliases SET author_id = targetID WHERE author_id IN (sourceIDs);
+	err = a.db.Model(&model.AuthorAlias{}).Where("author_id IN ?", sourceIDs).Update("author_id", targetID).Error
+	if err != nil {
+		return err
+	}
+	// 对应SQL: INSERT INTO author_aliases (name, author_id) SELECT name, targetID FROM authors WHERE id IN (sourceIDs)
+	//          ON DUPLICATE KEY UPDATE author_id = VALUES(author_id);
+	err = a.db.Exec("INSERT INTO author_aliases (name, author_id) SELECT name, ? FROM authors WHERE id IN ? "+
+		"ON DUPLICATE KEY UPDATE author_id = VALUES(author_id)", targetID, sourceIDs).Error
+	if err != nil {
+		return err
+	}
+
+	// 对应SQL: DELETE FROM authors WHERE id IN (sourceIDs);
+	return a.db.Where("id IN ?", sourceIDs).Delete(&model.Author{}).Error
+}
@@ -3,10 +3,18 @@ package repository
 import (
 	"bookstore/global"
 	"bookstore/model"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrInsufficientStock 可售库存不足
+var ErrInsufficientStock = errors.New("库存不足")
+
 // BookDAO 书籍数据访问对象
 // 封装了所有与书籍相关的数据库操作
 type BookDAO struct {
@@ -23,6 +31,18 @@ func NewBookDAO() *BookDAO {
 	}
 }
 
+// WithTx 返回绑定到指定事务的书籍DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*BookDAO - 使用该事务执行所有操作的书籍数据访问对象
+func (b *BookDAO) WithTx(tx *gorm.DB) *BookDAO {
+	return &BookDAO{db: tx}
+}
+
 // GetAllBooks 获取所有书籍
 // 返回:
 //
@@ -102,13 +122,14 @@ func (b *BookDAO) GetBooksByPageForAdmin(page, pageSize int) ([]*model.Book, int
 //	author - 作者搜索关键词
 //	bookType - 书籍类型筛选条件
 //	status - 状态筛选条件（指针类型，nil表示不筛选）
+//	tagID - 标签ID筛选条件，0表示不筛选
 //
 // 返回:
 //
 //	[]*model.Book - 当前页的书籍对象切片
 //	int64 - 符合条件的总记录数
 //	error - 如果查询过程中出现错误则返回错误
-func (b *BookDAO) GetBooksByPageForAdminWithSearch(page, pageSize int, title, author, bookType string, status *int) ([]*model.Book, int64, error) {
+func (b *BookDAO) GetBooksByPageForAdminWithSearch(page, pageSize int, title, author, bookType string, status *int, tagID int) ([]*model.Book, int64, error) {
 	var books []*model.Book
 	var total int64
 
@@ -132,6 +153,10 @@ func (b *BookDAO) GetBooksByPageForAdminWithSearch(page, pageSize int, title, au
 		// 对应SQL条件: status = status
 		query = query.Where("status = ?", *status)
 	}
+	if tagID > 0 {
+		// 对应SQL条件: id IN (SELECT book_id FROM book_tags WHERE tag_id = tagID)
+		query = query.Where("id IN (SELECT book_id FROM book_tags WHERE tag_id = ?)", tagID)
+	}
 
 	// 获取总数
 	// 对应SQL: SELECT COUNT(*) FROM books [WHERE conditions...]
@@ -147,6 +172,242 @@ func (b *BookDAO) GetBooksByPageForAdminWithSearch(page, pageSize int, title, au
 	return books, total, err
 }
 
+// BookFilter 前台图书列表的筛选条件，只包含上架图书
+type BookFilter struct {
+	IDs         []int    // 限定的图书ID（如搜索命中的图书），nil表示不限定
+	CategoryID  uint     // 分类ID，包含其全部子孙分类下的图书，0表示不限
+	AuthorID    int      // 作者ID（任一署名角色），0表示不限
+	PublisherID int      // 出版社ID，0表示不限
+	Tags        []string // 标签别名，需同时带有全部标签，空表示不限
+	Type        string   // 图书类型，空表示不限
+	Language    string   // 语言，空表示不限
+	Format      string   // 装帧格式，空表示不限
+	Publisher   string   // 出版社，空表示不限
+	MinPrice    *int     // 最低折后价（元，含），nil表示不限
+	MaxPrice    *int     // 最高折后价（元，不含），nil表示不限
+	InStock     bool     // 是否只包含有可售库存的图书
+}
+
+// BookFacetCount 按字段分组统计的图书数量
+type BookFacetCount struct {
+	Value string // 字段取值
+	Count int64  // 图书数量
+}
+
+// bookFinalPriceExpr 计算折后单价的SQL表达式，与model.Book.FinalPrice保持一致
+const bookFinalPriceExpr = "(CASE WHEN discount > 0 AND discount < 100 THEN price * (100 - discount) DIV 100 ELSE price END)"
+
+// bookCategorySubtreeCond 图书属于指定分类或其子孙分类的SQL条件，子孙分类的物化路径以该分类的路径为前缀
+const bookCategorySubtreeCond = "category_id IN (SELECT c.id FROM categories c JOIN categories p ON c.path LIKE CONCAT(p.path, '%') WHERE p.id = ?)"
+
+// bookTagCond 图书带有指定别名的标签的SQL条件
+const bookTagCond = "id IN (SELECT bt.book_id FROM book_tags bt JOIN tags t ON t.id = bt.tag_id WHERE t.slug = ?)"
+
+// bookFacetColumns 允许分组统计的字段
+var bookFacetColumns = map[string]bool{
+	"category_id": true,
+	"type":        true,
+	"language":    true,
+	"format":      true,
+	"publisher":   true,
+}
+
+// filterBooks 根据筛选条件构建上架图书查询
+// 参数:
+//
+//	filter - 筛选条件
+//
+// 返回:
+//
+//	*gorm.DB - 带有筛选条件的查询
+func (b *BookDAO) filterBooks(filter *BookFilter) *gorm.DB {
+	// 对应SQL条件: status = 1 [AND id IN (ids)] [AND category_id IN (子树分类ID)] [AND id IN (作者的图书ID)]
+	//             [AND id IN (带有标签的图书ID)] ... [AND stock - reserved > 0]
+	query := b.db.Model(&model.Book{}).Where("status = ?", 1)
+	if filter.IDs != nil {
+		if len(filter.IDs) == 0 {
+			return query.Where("1 = 0")
+		}
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	if filter.CategoryID > 0 {
+		query = query.Where(bookCategorySubtreeCond, filter.CategoryID)
+	}
+	if filter.AuthorID > 0 {
+		query = query.Where("id IN (SELECT book_id FROM book_authors WHERE author_id = ?)", filter.AuthorID)
+	}
+	if filter.PublisherID > 0 {
+		query = query.Where("publisher_id = ?", filter.PublisherID)
+	}
+	for _, slug := range filter.Tags {
+		if slug != "" {
+			query = query.Where(bookTagCond, slug)
+		}
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Language != "" {
+		query = query.Where("language = ?", filter.Language)
+	}
+	if filter.Format != "" {
+		query = query.Where("format = ?", filter.Format)
+	}
+	if filter.Publisher != "" {
+		query = query.Where("publisher = ?", filter.Publisher)
+	}
+	if filter.MinPrice != nil {
+		query = query.Where(bookFinalPriceExpr+" >= ?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		query = query.Where(bookFinalPriceExpr+" < ?", *filter.MaxPrice)
+	}
+	if filter.InStock {
+		query = query.Where("stock - reserved > 0")
+	}
+	return query
+}
+
+// GetBooksByFilter 按筛选条件和排序方式分页获取上架图书
+// 参数:
+//
+//	filter - 筛选条件
+//	sort - 排序方式，取值见model.BookSort*；按相关度排序时按filter.IDs的顺序返回
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*model.Book - 当前页的书籍对象切片
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) GetBooksByFilter(filter *BookFilter, sort string, page, pageSize int) ([]*model.Book, int64, error) {
+	var books []*model.Book
+	var total int64
+
+	// 对应SQL: SELECT COUNT(*) FROM books WHERE [conditions...];
+	if err := b.filterBooks(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := b.filterBooks(filter)
+	switch sort {
+	case model.BookSortRelevance:
+		if len(filter.IDs) > 0 {
+			// 对应SQL: ORDER BY FIELD(id, ids...)，图书ID均为整数，可直接拼接
+			ids := make([]string, 0, len(filter.IDs))
+			for _, id := range filter.IDs {
+				ids = append(ids, strconv.Itoa(id))
+			}
+			query = query.Order("FIELD(id, " + strings.Join(ids, ",") + ")")
+		}
+	case model.BookSortPriceAsc:
+		query = query.Order(bookFinalPriceExpr + " ASC")
+	case model.BookSortPriceDesc:
+		query = query.Order(bookFinalPriceExpr + " DESC")
+	case model.BookSortSale:
+		query = query.Order("sale DESC")
+	case model.BookSortNewest:
+		query = query.Order("created_at DESC")
+	case model.BookSortRating:
+		query = query.Order("rating DESC")
+	}
+
+	// 对应SQL: SELECT * FROM books WHERE [conditions...] ORDER BY [sort], id ASC LIMIT pageSize OFFSET offset;
+	offset := (page - 1) * pageSize
+	err := query.Order("id ASC").Offset(offset).Limit(pageSize).Find(&books).Error
+	return books, total, err
+}
+
+// CountBooksByField 按字段分组统计符合条件的上架图书数量
+// 参数:
+//
+//	filter - 筛选条件
+//	column - 分组字段，只允许category_id、type、language、format、publisher
+//	limit - 返回的最大分组数，按数量降序，0表示不限
+//
+// 返回:
+//
+//	[]BookFacetCount - 各取值的图书数量，不包含空值
+//	error - 字段不允许分组或查询过程中出现错误时返回错误
+func (b *BookDAO) CountBooksByField(filter *BookFilter, column string, limit int) ([]BookFacetCount, error) {
+	if !bookFacetColumns[column] {
+		return nil, errors.New("不支持的统计字段: " + column)
+	}
+
+	var counts []BookFacetCount
+	// 对应SQL: SELECT column AS value, COUNT(*) AS count FROM books WHERE [conditions...] AND column IS NOT NULL AND column <> ''
+	// GROUP BY column ORDER BY count DESC, value ASC [LIMIT limit];
+	query := b.filterBooks(filter).
+		Select(column + " AS value, COUNT(*) AS count").
+		Where(column + " IS NOT NULL AND " + column + " <> ''").
+		Group(column).
+		Order("count DESC, value ASC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Scan(&counts).Error
+	return counts, err
+}
+
+// CountBooksByPriceRange 按折后价区间统计符合条件的上架图书数量
+// 参数:
+//
+//	filter - 筛选条件
+//	bounds - 升序排列的区间边界（元），n个边界划分出n+1个区间，区间含下界不含上界
+//
+// 返回:
+//
+//	[]int64 - 各区间的图书数量，长度为len(bounds)+1
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) CountBooksByPriceRange(filter *BookFilter, bounds []int) ([]int64, error) {
+	// 对应SQL: SELECT CASE WHEN final_price < bounds[0] THEN 0 WHEN final_price < bounds[1] THEN 1 ... ELSE n END AS bucket,
+	// COUNT(*) AS count FROM books WHERE [conditions...] GROUP BY bucket;
+	bucketExpr := "CASE"
+	vars := make([]any, 0, len(bounds))
+	for i, bound := range bounds {
+		bucketExpr += fmt.Sprintf(" WHEN %s < ? THEN %d", bookFinalPriceExpr, i)
+		vars = append(vars, bound)
+	}
+	bucketExpr += fmt.Sprintf(" ELSE %d END", len(bounds))
+
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := b.filterBooks(filter).
+		Select(bucketExpr+" AS bucket, COUNT(*) AS count", vars...).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int64, len(bounds)+1)
+	for _, row := range rows {
+		if row.Bucket >= 0 && row.Bucket < len(counts) {
+			counts[row.Bucket] = row.Count
+		}
+	}
+	return counts, nil
+}
+
+// CountBooksByFilter 统计符合条件的上架图书数量
+// 参数:
+//
+//	filter - 筛选条件
+//
+// 返回:
+//
+//	int64 - 图书数量
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) CountBooksByFilter(filter *BookFilter) (int64, error) {
+	var total int64
+	// 对应SQL: SELECT COUNT(*) FROM books WHERE [conditions...];
+	err := b.filterBooks(filter).Count(&total).Error
+	return total, err
+}
+
 // GetHotBooks 获取热销书籍（只返回上架状态）
 // 参数:
 //
@@ -227,54 +488,209 @@ func (b *BookDAO) GetBooksByType(bookType string) ([]*model.Book, error) {
 	return books, err
 }
 
-// SearchBooks 搜索书籍（只返回上架状态）
+// GetBooksByCategory 获取分类及其全部子孙分类下的上架图书
 // 参数:
 //
-//	keyword - 搜索关键词
+//	categoryID - 分类ID
+//
+// 返回:
+//
+//	[]*model.Book - 书籍对象切片
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) GetBooksByCategory(categoryID int) ([]*model.Book, error) {
+	var books []*model.Book
+	// 对应SQL: SELECT * FROM books WHERE status = 1 AND category_id IN (子树分类ID) ORDER BY id;
+	err := b.filterBooks(&BookFilter{CategoryID: uint(categoryID)}).Order("id ASC").Find(&books).Error
+	return books, err
+}
+
+// CountBooksByCategory 统计直接属于指定分类的图书数量，包含下架图书
+// 参数:
+//
+//	categoryID - 分类ID
+//
+// 返回:
+//
+//	int64 - 图书数量
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) CountBooksByCategory(categoryID int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM books WHERE category_id = categoryID;
+	err := b.db.Model(&model.Book{}).Where("category_id = ?", categoryID).Count(&count).Error
+	return count, err
+}
+
+// GetBooksWithoutContributors 按ID顺序获取尚未关联作者或出版社的图书，用于迁移署名文字
+// 参数:
+//
+//	afterID - 只返回ID大于该值的图书，用于分批读取
+//	limit - 返回的最大数量
+//
+// 返回:
+//
+//	[]*model.Book - 书籍对象切片
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) GetBooksWithoutContributors(afterID, limit int) ([]*model.Book, error) {
+	var books []*model.Book
+	// 对应SQL: SELECT * FROM books WHERE id > afterID AND ((author <> '' AND NOT EXISTS (SELECT 1 FROM book_authors WHERE book_id = books.id))
+	//          OR (publisher <> '' AND publisher_id IS NULL)) ORDER BY id LIMIT limit;
+	err := b.db.Where("id > ?", afterID).
+		Where("((author <> '' AND NOT EXISTS (SELECT 1 FROM book_authors WHERE book_id = books.id)) OR (publisher <> '' AND publisher_id IS NULL))").
+		Order("id").Limit(limit).Find(&books).Error
+	return books, err
+}
+
+// SetPublisherID 设置图书关联的出版社
+// 参数:
+//
+//	id - 图书ID
+//	publisherID - 出版社ID，nil表示取消关联
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (b *BookDAO) SetPublisherID(id int, publisherID *int) error {
+	// 对应SQL: UPDATE books SET publisher_id = publisherID WHERE id = id;
+	return b.db.Model(&model.Book{}).Where("id = ?", id).UpdateColumn("publisher_id", publisherID).Error
+}
+
+// GetEditions 获取同一作品下的其他上架版本
+// 参数:
+//
+//	workID - 作品ID
+//	excludeID - 排除的图书ID，通常为当前查看的图书
 //
 // 返回:
 //
-//	[]*model.Book - 符合条件的书籍对象切片
+//	[]*model.Book - 书籍对象切片，按出版日期从新到旧排列
 //	error - 如果查询过程中出现错误则返回错误
-func (b *BookDAO) SearchBooks(keyword string) ([]*model.Book, error) {
+func (b *BookDAO) GetEditions(workID, excludeID int) ([]*model.Book, error) {
 	var books []*model.Book
-	// 对应SQL: SELECT * FROM books WHERE status = 1 AND (title LIKE '%keyword%' OR author LIKE '%keyword%' OR description LIKE '%keyword%');
-	err := b.db.Where("status = ? AND (title LIKE ? OR author LIKE ? OR description LIKE ?)", 1, "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%").Find(&books).Error
+	// 对应SQL: SELECT * FROM books WHERE status = 1 AND work_id = workID AND id <> excludeID ORDER BY publish_date DESC, id;
+	err := b.db.Where("status = ? AND work_id = ? AND id <> ?", 1, workID, excludeID).
+		Order("publish_date DESC, id").Find(&books).Error
 	return books, err
 }
 
-// SearchBooksWithPagination 分页搜索书籍（只返回上架状态）
+// GetSeriesBooks 获取丛书中的上架图书
+// 参数:
+//
+//	seriesID - 丛书ID
+//
+// 返回:
+//
+//	[]*model.Book - 书籍对象切片，按卷号排列，同一卷的多个版本按销量从高到低排列
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) GetSeriesBooks(seriesID int) ([]*model.Book, error) {
+	var books []*model.Book
+	// 对应SQL: SELECT * FROM books WHERE status = 1 AND series_id = seriesID ORDER BY volume, sale DESC, id;
+	err := b.db.Where("status = ? AND series_id = ?", 1, seriesID).
+		Order("volume, sale DESC, id").Find(&books).Error
+	return books, err
+}
+
+// SetGrouping 设置图书所属的作品、版本说明、丛书和卷号
+// 参数:
+//
+//	id - 图书ID
+//	req - 作品和丛书设置，各字段整体覆盖
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (b *BookDAO) SetGrouping(id int, req *model.BookGroupingRequest) error {
+	// 对应SQL: UPDATE books SET work_id = ?, edition = ?, series_id = ?, volume = ?, updated_at = NOW() WHERE id = id;
+	return b.db.Model(&model.Book{}).Where("id = ?", id).Updates(map[string]any{
+		"work_id":   req.WorkID,
+		"edition":   req.Edition,
+		"series_id": req.SeriesID,
+		"volume":    req.Volume,
+	}).Error
+}
+
+// CategoryBookCount 分类下按状态统计的图书数量
+type CategoryBookCount struct {
+	CategoryID int // 分类ID
+	OnSale     int // 上架图书数量
+	OffShelf   int // 下架图书数量
+}
+
+// CountBooksGroupByCategory 按分类统计上架和下架图书数量
+// 返回:
+//
+//	[]CategoryBookCount - 有图书的分类的统计结果
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) CountBooksGroupByCategory() ([]CategoryBookCount, error) {
+	var counts []CategoryBookCount
+	// 对应SQL: SELECT category_id, SUM(status = 1) AS on_sale, SUM(status <> 1) AS off_shelf FROM books
+	// WHERE category_id IS NOT NULL GROUP BY category_id;
+	err := b.db.Model(&model.Book{}).
+		Select("category_id, SUM(status = 1) AS on_sale, SUM(status <> 1) AS off_shelf").
+		Where("category_id IS NOT NULL").
+		Group("category_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// BookSearchWeights 全文搜索中各字段的权重
+type BookSearchWeights struct {
+	Title       float64 // 标题权重
+	Author      float64 // 作者权重
+	Publisher   float64 // 出版社权重
+	Description float64 // 描述权重
+}
+
+// BookSearchScore 全文搜索命中的图书ID及相关度得分
+type BookSearchScore struct {
+	ID    int     // 图书ID
+	Score float64 // 按字段权重加权后的相关度得分
+}
+
+// bookFullTextColumns 组合全文索引ft_books_search包含的列，MATCH的列必须与索引定义完全一致
+const bookFullTextColumns = "title, author, publisher, description"
+
+// FullTextSearch 使用ngram全文索引搜索上架图书，按字段加权的相关度降序排列
 // 参数:
 //
 //	keyword - 搜索关键词
-//	page - 页码，从1开始
-//	pageSize - 每页记录数
+//	weights - 各字段的权重
+//	offset - 跳过的结果数
+//	limit - 返回的最大结果数
 //
 // 返回:
 //
-//	[]*model.Book - 当前页的书籍对象切片
+//	[]BookSearchScore - 当前页的图书ID及得分
 //	int64 - 符合条件的总记录数
 //	error - 如果查询过程中出现错误则返回错误
-func (b *BookDAO) SearchBooksWithPagination(keyword string, page, pageSize int) ([]*model.Book, int64, error) {
-	var books []*model.Book
+func (b *BookDAO) FullTextSearch(keyword string, weights BookSearchWeights, offset, limit int) ([]BookSearchScore, int64, error) {
+	var scores []BookSearchScore
 	var total int64
 
-	// 构建搜索条件（只搜索上架状态）
-	// 对应SQL条件: status = 1 AND (title LIKE '%keyword%' OR author LIKE '%keyword%' OR description LIKE '%keyword%')
-	searchCondition := b.db.Where("status = ? AND (title LIKE ? OR author LIKE ? OR description LIKE ?)", 1, "%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
+	// 对应SQL条件: status = 1 AND MATCH(title, author, publisher, description) AGAINST('keyword' IN NATURAL LANGUAGE MODE)
+	// 计数和分页查询各自使用新的查询，避免SELECT子句互相影响
+	query := func() *gorm.DB {
+		return b.db.Model(&model.Book{}).
+			Where("status = ? AND MATCH("+bookFullTextColumns+") AGAINST(? IN NATURAL LANGUAGE MODE)", 1, keyword)
+	}
 
-	// 获取总数
-	// 对应SQL: SELECT COUNT(*) FROM books WHERE status = 1 AND (title LIKE '%keyword%' OR author LIKE '%keyword%' OR description LIKE '%keyword%');
-	err := searchCondition.Model(&model.Book{}).Count(&total).Error
-	if err != nil {
+	// 对应SQL: SELECT COUNT(*) FROM books WHERE [conditions...];
+	if err := query().Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// 分页查询
-	// 对应SQL: SELECT * FROM books WHERE status = 1 AND (title LIKE '%keyword%' OR author LIKE '%keyword%' OR description LIKE '%keyword%') LIMIT pageSize OFFSET offset;
-	offset := (page - 1) * pageSize
-	err = searchCondition.Offset(offset).Limit(pageSize).Find(&books).Error
-	return books, total, err
+	// 对应SQL: SELECT id, ? * MATCH(title) AGAINST(?) + ? * MATCH(author) AGAINST(?) + ... AS score
+	// FROM books WHERE [conditions...] ORDER BY score DESC, id ASC LIMIT limit OFFSET offset;
+	err := query().
+		Select("id, "+
+			"? * MATCH(title) AGAINST(? IN NATURAL LANGUAGE MODE) + "+
+			"? * MATCH(author) AGAINST(? IN NATURAL LANGUAGE MODE) + "+
+			"? * MATCH(publisher) AGAINST(? IN NATURAL LANGUAGE MODE) + "+
+			"? * MATCH(description) AGAINST(? IN NATURAL LANGUAGE MODE) AS score",
+			weights.Title, keyword, weights.Author, keyword, weights.Publisher, keyword, weights.Description, keyword).
+		Order("score DESC, id ASC").
+		Offset(offset).Limit(limit).
+		Scan(&scores).Error
+	return scores, total, err
 }
 
 // CreateBook 创建书籍
@@ -292,6 +708,7 @@ func (b *BookDAO) CreateBook(book *model.Book) error {
 }
 
 // UpdateBook 更新书籍
+// 预留数量和销售量只能由订单流程修改，此处不会覆盖
 // 参数:
 //
 //	book - 书籍对象指针
@@ -300,11 +717,117 @@ func (b *BookDAO) CreateBook(book *model.Book) error {
 //
 //	error - 如果更新过程中出现错误则返回错误
 func (b *BookDAO) UpdateBook(book *model.Book) error {
+	// 所属作品和丛书只通过SetGrouping修改，编辑图书信息时不覆盖
 	// 对应SQL: UPDATE books SET title = book.Title, author = book.Author, ... WHERE id = book.ID;
-	err := b.db.Save(book).Error
+	err := b.db.Omit("reserved", "sale", "work_id", "edition", "series_id", "volume").Save(book).Error
 	return err
 }
 
+// GetBooksByIDs 根据ID批量获取书籍（不过滤状态）
+// 参数:
+//
+//	ids - 书籍ID切片
+//
+// 返回:
+//
+//	[]*model.Book - 书籍对象切片，不存在的ID会被忽略
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) GetBooksByIDs(ids []int) ([]*model.Book, error) {
+	var books []*model.Book
+	if len(ids) == 0 {
+		return books, nil
+	}
+	// 对应SQL: SELECT * FROM books WHERE id IN (ids);
+	err := b.db.Where("id IN ?", ids).Find(&books).Error
+	return books, err
+}
+
+// GetBooksForUpdate 根据ID批量获取书籍并加行锁（需在事务中调用）
+// 按ID升序加锁，避免并发事务之间死锁
+// 参数:
+//
+//	ids - 书籍ID切片
+//
+// 返回:
+//
+//	[]*model.Book - 书籍对象切片
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookDAO) GetBooksForUpdate(ids []int) ([]*model.Book, error) {
+	var books []*model.Book
+	// 对应SQL: SELECT * FROM books WHERE id IN (ids) ORDER BY id ASC FOR UPDATE;
+	err := b.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id ASC").Find(&books).Error
+	return books, err
+}
+
+// ReserveStock 为待支付订单预留库存
+// 参数:
+//
+//	id - 书籍ID
+//	quantity - 预留数量
+//
+// 返回:
+//
+//	error - 可售库存不足时返回ErrInsufficientStock
+func (b *BookDAO) ReserveStock(id, quantity int) error {
+	// 对应SQL: UPDATE books SET reserved = reserved + quantity WHERE id = id AND stock - reserved >= quantity;
+	result := b.db.Model(&model.Book{}).
+		Where("id = ? AND stock - reserved >= ?", id, quantity).
+		Update("reserved", gorm.Expr("reserved + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// ReleaseStock 释放待支付订单预留的库存
+// 参数:
+//
+//	id - 书籍ID
+//	quantity - 释放数量
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (b *BookDAO) ReleaseStock(id, quantity int) error {
+	// 对应SQL: UPDATE books SET reserved = GREATEST(reserved - quantity, 0) WHERE id = id;
+	err := b.db.Model(&model.Book{}).
+		Where("id = ?", id).
+		Update("reserved", gorm.Expr("GREATEST(reserved - ?, 0)", quantity)).Error
+	return err
+}
+
+// ConfirmReservedStock 将预留库存转为实际销售
+// 同时扣减库存和预留数量，并增加销售量
+// 参数:
+//
+//	id - 书籍ID
+//	quantity - 销售数量
+//
+// 返回:
+//
+//	error - 库存不足时返回ErrInsufficientStock
+func (b *BookDAO) ConfirmReservedStock(id, quantity int) error {
+	// 对应SQL: UPDATE books SET stock = stock - quantity, reserved = GREATEST(reserved - quantity, 0), sale = sale + quantity
+	// WHERE id = id AND stock >= quantity;
+	result := b.db.Model(&model.Book{}).
+		Where("id = ? AND stock >= ?", id, quantity).
+		Updates(map[string]any{
+			"stock":    gorm.Expr("stock - ?", quantity),
+			"reserved": gorm.Expr("GREATEST(reserved - ?, 0)", quantity),
+			"sale":     gorm.Expr("sale + ?", quantity),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
 // DeleteBook 删除书籍
 // 参数:
 //
@@ -318,3 +841,23 @@ func (b *BookDAO) DeleteBook(id int) error {
 	err := b.db.Delete(&model.Book{}, id).Error
 	return err
 }
+
+// RestoreStock 退款后恢复库存并扣减销售量
+// 参数:
+//
+//	id - 书籍ID
+//	quantity - 退回数量
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (b *BookDAO) RestoreStock(id, quantity int) error {
+	// 对应SQL: UPDATE books SET stock = stock + quantity, sale = GREATEST(sale - quantity, 0) WHERE id = id;
+	err := b.db.Model(&model.Book{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"stock": gorm.Expr("stock + ?", quantity),
+			"sale":  gorm.Expr("GREATEST(sale - ?, 0)", quantity),
+		}).Error
+	return err
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"bookstore/global"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CartDAO 购物车数据访问对象
+// 购物车保存在Redis哈希中，键为购物车标识，字段为图书ID，值为数量
+type CartDAO struct {
+	rdb *redis.Client // Redis客户端实例
+}
+
+// NewCartDAO 创建新的购物车DAO实例
+// 返回:
+//
+//	*CartDAO - 初始化后的购物车数据访问对象
+func NewCartDAO() *CartDAO {
+	return &CartDAO{
+		rdb: global.RedisClient, // 从全局变量获取Redis连接
+	}
+}
+
+// cartKey 生成购物车在Redis中的键
+func cartKey(owner string) string {
+	return "cart:" + owner
+}
+
+// GetItems 获取购物车中的全部商品
+// 参数:
+//
+//	owner - 购物车标识
+//
+// 返回:
+//
+//	map[int]int - 以图书ID为键的数量
+//	error - 如果读取过程中出现错误则返回错误
+func (c *CartDAO) GetItems(owner string) (map[int]int, error) {
+	// 对应Redis命令: HGETALL cart:{owner}
+	fields, err := c.rdb.HGetAll(context.Background(), cartKey(owner)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[int]int, len(fields))
+	for field, value := range fields {
+		bookID, err := strconv.Atoi(field)
+		if err != nil {
+			continue
+		}
+		quantity, err := strconv.Atoi(value)
+		if err != nil || quantity <= 0 {
+			continue
+		}
+		items[bookID] = quantity
+	}
+	return items, nil
+}
+
+// GetQuantity 获取购物车中某本图书的数量
+// 参数:
+//
+//	owner - 购物车标识
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	int - 数量，不在购物车中时为0
+//	error - 如果读取过程中出现错误则返回错误
+func (c *CartDAO) GetQuantity(owner string, bookID int) (int, error) {
+	// 对应Redis命令: HGET cart:{owner} {bookID}
+	quantity, err := c.rdb.HGet(context.Background(), cartKey(owner), strconv.Itoa(bookID)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return quantity, err
+}
+
+// SetQuantity 设置购物车中某本图书的数量，并刷新购物车过期时间
+// 参数:
+//
+//	owner - 购物车标识
+//	bookID - 图书ID
+//	quantity - 数量
+//	ttl - 购物车过期时间，0表示不过期
+//
+// 返回:
+//
+//	error - 如果写入过程中出现错误则返回错误
+func (c *CartDAO) SetQuantity(owner string, bookID, quantity int, ttl time.Duration) error {
+	return c.SetItems(owner, map[int]int{bookID: quantity}, ttl)
+}
+
+// SetItems 批量设置购物车中图书的数量，并刷新购物车过期时间
+// 参数:
+//
+//	owner - 购物车标识
+//	items - 以图书ID为键的数量
+//	ttl - 购物车过期时间，0表示不过期
+//
+// 返回:
+//
+//	error - 如果写入过程中出现错误则返回错误
+func (c *CartDAO) SetItems(owner string, items map[int]int, ttl time.Duration) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	values := make(map[string]any, len(items))
+	for bookID, quantity := range items {
+		values[strconv.Itoa(bookID)] = quantity
+	}
+
+	// 对应Redis命令: MULTI; HSET cart:{owner} {bookID} {quantity} ...; EXPIRE cart:{owner} {ttl}; EXEC
+	ctx := context.Background()
+	key := cartKey(owner)
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, values)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// RemoveItems 从购物车中移除图书
+// 参数:
+//
+//	owner - 购物车标识
+//	bookIDs - 要移除的图书ID
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (c *CartDAO) RemoveItems(owner string, bookIDs ...int) error {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+
+	fields := make([]string, 0, len(bookIDs))
+	for _, bookID := range bookIDs {
+		fields = append(fields, strconv.Itoa(bookID))
+	}
+	// 对应Redis命令: HDEL cart:{owner} {bookID} ...
+	return c.rdb.HDel(context.Background(), cartKey(owner), fields...).Err()
+}
+
+// Clear 清空购物车
+// 参数:
+//
+//	owner - 购物车标识
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (c *CartDAO) Clear(owner string) error {
+	// 对应Redis命令: DEL cart:{owner}
+	return c.rdb.Del(context.Background(), cartKey(owner)).Err()
+}
@@ -5,6 +5,7 @@ import (
 	"bookstore/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CategoryDAO 分类数据访问对象
@@ -23,6 +24,18 @@ func NewCategoryDAO() *CategoryDAO {
 	}
 }
 
+// WithTx 返回绑定到指定事务的分类DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*CategoryDAO - 使用该事务执行所有操作的分类数据访问对象
+func (c *CategoryDAO) WithTx(tx *gorm.DB) *CategoryDAO {
+	return &CategoryDAO{db: tx}
+}
+
 // GetAllCategories 获取所有分类
 // 返回:
 //
@@ -51,6 +64,103 @@ func (c *CategoryDAO) GetCategoryByID(id int) (*model.Category, error) {
 	return &category, err
 }
 
+// GetCategoryByName 根据名称获取分类
+// 参数:
+//
+//	name - 分类名称
+//
+// 返回:
+//
+//	*model.Category - 分类对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CategoryDAO) GetCategoryByName(name string) (*model.Category, error) {
+	var category model.Category
+	// 对应SQL: SELECT * FROM categories WHERE name = name LIMIT 1;
+	err := c.db.Where("name = ?", name).First(&category).Error
+	return &category, err
+}
+
+// GetCategoriesByIDs 根据ID批量获取分类
+// 参数:
+//
+//	ids - 分类ID列表
+//
+// 返回:
+//
+//	[]*model.Category - 分类对象切片，不存在的ID不返回
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CategoryDAO) GetCategoriesByIDs(ids []int) ([]*model.Category, error) {
+	var categories []*model.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	// 对应SQL: SELECT * FROM categories WHERE id IN (ids);
+	err := c.db.Where("id IN ?", ids).Find(&categories).Error
+	return categories, err
+}
+
+// GetCategoriesForUpdate 根据ID获取分类并加行锁（需在事务中调用）
+// 按ID顺序加锁，避免并发移动分类时死锁
+// 参数:
+//
+//	ids - 分类ID列表
+//
+// 返回:
+//
+//	[]*model.Category - 分类对象切片，不存在的ID不返回
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CategoryDAO) GetCategoriesForUpdate(ids []int) ([]*model.Category, error) {
+	var categories []*model.Category
+	// 对应SQL: SELECT * FROM categories WHERE id IN (ids) ORDER BY id FOR UPDATE;
+	err := c.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(&categories).Error
+	return categories, err
+}
+
+// GetAllCategoriesForUpdate 获取所有分类并加行锁（需在事务中调用）
+// 返回:
+//
+//	[]*model.Category - 分类对象切片
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CategoryDAO) GetAllCategoriesForUpdate() ([]*model.Category, error) {
+	var categories []*model.Category
+	// 对应SQL: SELECT * FROM categories ORDER BY id FOR UPDATE;
+	err := c.db.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Find(&categories).Error
+	return categories, err
+}
+
+// CountChildren 统计分类的直接子分类数量
+// 参数:
+//
+//	id - 分类ID
+//
+// 返回:
+//
+//	int64 - 子分类数量
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CategoryDAO) CountChildren(id int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM categories WHERE parent_id = id;
+	err := c.db.Model(&model.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// GetSubtreeMaxDepth 获取子树（含根节点）中最深分类的层级深度
+// 参数:
+//
+//	path - 子树根节点的物化路径
+//
+// 返回:
+//
+//	int - 最大层级深度
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CategoryDAO) GetSubtreeMaxDepth(path string) (int, error) {
+	var depth int
+	// 对应SQL: SELECT COALESCE(MAX(depth), 0) FROM categories WHERE path LIKE 'path%';
+	err := c.db.Model(&model.Category{}).Where("path LIKE ?", path+"%").
+		Select("COALESCE(MAX(depth), 0)").Scan(&depth).Error
+	return depth, err
+}
+
 // CreateCategory 创建分类
 // 参数:
 //
@@ -74,11 +184,55 @@ func (c *CategoryDAO) CreateCategory(category *model.Category) error {
 //
 //	error - 如果更新过程中出现错误则返回错误
 func (c *CategoryDAO) UpdateCategory(category *model.Category) error {
+	// 父分类、物化路径和层级只能通过MoveSubtree修改，图书数量只能通过AdjustBookCounts和SetBookCounts修改
 	// 对应SQL: UPDATE categories SET name = category.Name, description = category.Description, ... WHERE id = category.ID;
-	err := c.db.Save(category).Error
+	err := c.db.Omit("parent_id", "path", "depth", "book_count", "off_shelf_book_count").Save(category).Error
 	return err
 }
 
+// SetPath 设置分类的物化路径和层级深度，用于新建分类后补全路径
+// 参数:
+//
+//	id - 分类ID
+//	path - 物化路径
+//	depth - 层级深度
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (c *CategoryDAO) SetPath(id int, path string, depth int) error {
+	// 对应SQL: UPDATE categories SET path = path, depth = depth WHERE id = id;
+	return c.db.Model(&model.Category{}).Where("id = ?", id).
+		Updates(map[string]any{"path": path, "depth": depth}).Error
+}
+
+// MoveSubtree 将分类连同其全部子孙分类移动到新的父分类下（需在事务中调用）
+// 参数:
+//
+//	id - 被移动的分类ID
+//	parentID - 新的父分类ID，nil表示移动为顶级分类
+//	oldPath - 被移动分类原来的物化路径
+//	newPath - 被移动分类新的物化路径
+//	depthDelta - 子树中每个分类层级深度的变化量
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (c *CategoryDAO) MoveSubtree(id int, parentID *int, oldPath, newPath string, depthDelta int) error {
+	// 对应SQL: UPDATE categories SET path = CONCAT(newPath, SUBSTRING(path, LENGTH(oldPath) + 1)), depth = depth + depthDelta
+	//          WHERE path LIKE 'oldPath%';
+	err := c.db.Model(&model.Category{}).Where("path LIKE ?", oldPath+"%").Updates(map[string]any{
+		"path":  gorm.Expr("CONCAT(?, SUBSTRING(path, ?))", newPath, len(oldPath)+1),
+		"depth": gorm.Expr("depth + ?", depthDelta),
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	// 对应SQL: UPDATE categories SET parent_id = parentID WHERE id = id;
+	return c.db.Model(&model.Category{}).Where("id = ?", id).Update("parent_id", parentID).Error
+}
+
 // DeleteCategory 删除分类
 // 参数:
 //
@@ -92,3 +246,40 @@ func (c *CategoryDAO) DeleteCategory(id int) error {
 	err := c.db.Delete(&model.Category{}, id).Error
 	return err
 }
+
+// AdjustBookCounts 增减分类的上架和下架图书数量
+// 统计值的变化不属于分类信息的修改，因此不更新updated_at
+// 参数:
+//
+//	id - 分类ID
+//	onSale - 上架图书数量的变化量
+//	offShelf - 下架图书数量的变化量
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (c *CategoryDAO) AdjustBookCounts(id, onSale, offShelf int) error {
+	// 对应SQL: UPDATE categories SET book_count = book_count + onSale, off_shelf_book_count = off_shelf_book_count + offShelf WHERE id = id;
+	return c.db.Model(&model.Category{}).Where("id = ?", id).UpdateColumns(map[string]any{
+		"book_count":           gorm.Expr("book_count + ?", onSale),
+		"off_shelf_book_count": gorm.Expr("off_shelf_book_count + ?", offShelf),
+	}).Error
+}
+
+// SetBookCounts 设置分类的上架和下架图书数量，用于重新统计
+// 参数:
+//
+//	id - 分类ID
+//	onSale - 上架图书数量
+//	offShelf - 下架图书数量
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (c *CategoryDAO) SetBookCounts(id, onSale, offShelf int) error {
+	// 对应SQL: UPDATE categories SET book_count = onSale, off_shelf_book_count = offShelf WHERE id = id;
+	return c.db.Model(&model.Category{}).Where("id = ?", id).UpdateColumns(map[string]any{
+		"book_count":           onSale,
+		"off_shelf_book_count": offShelf,
+	}).Error
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CouponDAO 优惠券数据访问对象
+// 封装了所有与优惠券、优惠券使用记录相关的数据库操作
+type CouponDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewCouponDAO 创建新的优惠券DAO实例
+// 返回:
+//
+//	*CouponDAO - 初始化后的优惠券数据访问对象
+func NewCouponDAO() *CouponDAO {
+	return &CouponDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的优惠券DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*CouponDAO - 使用该事务执行所有操作的优惠券数据访问对象
+func (c *CouponDAO) WithTx(tx *gorm.DB) *CouponDAO {
+	return &CouponDAO{db: tx}
+}
+
+// CreateCoupon 创建优惠券
+// 参数:
+//
+//	coupon - 优惠券对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (c *CouponDAO) CreateCoupon(coupon *model.Coupon) error {
+	// 对应SQL: INSERT INTO coupons (code, name, type, value, ...) VALUES (...);
+	return c.db.Create(coupon).Error
+}
+
+// GetCouponByID 根据ID获取优惠券
+// 参数:
+//
+//	id - 优惠券ID
+//
+// 返回:
+//
+//	*model.Coupon - 优惠券对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CouponDAO) GetCouponByID(id int) (*model.Coupon, error) {
+	var coupon model.Coupon
+	// 对应SQL: SELECT * FROM coupons WHERE id = id LIMIT 1;
+	err := c.db.First(&coupon, id).Error
+	return &coupon, err
+}
+
+// GetCouponByCodeForUpdate 根据优惠码获取优惠券并加行锁（需在事务中调用）
+// 参数:
+//
+//	code - 优惠码
+//
+// 返回:
+//
+//	*model.Coupon - 优惠券对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CouponDAO) GetCouponByCodeForUpdate(code string) (*model.Coupon, error) {
+	var coupon model.Coupon
+	// 对应SQL: SELECT * FROM coupons WHERE code = code LIMIT 1 FOR UPDATE;
+	err := c.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("code = ?", code).First(&coupon).Error
+	return &coupon, err
+}
+
+// GetCoupons 获取优惠券列表（分页）
+// 参数:
+//
+//	keyword - 按优惠码或名称模糊搜索，为空表示不过滤
+//	page - 页码
+//	pageSize - 每页大小
+//
+// 返回:
+//
+//	[]*model.Coupon - 优惠券对象切片
+//	int64 - 优惠券总数
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CouponDAO) GetCoupons(keyword string, page, pageSize int) ([]*model.Coupon, int64, error) {
+	var coupons []*model.Coupon
+	var total int64
+
+	query := c.db.Model(&model.Coupon{})
+	if keyword != "" {
+		// 对应SQL条件: code LIKE '%keyword%' OR name LIKE '%keyword%'
+		query = query.Where("code LIKE ? OR name LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	// 对应SQL: SELECT COUNT(*) FROM coupons WHERE ...;
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 对应SQL: SELECT * FROM coupons WHERE ... ORDER BY id DESC LIMIT pageSize OFFSET (page-1)*pageSize;
+	offset := (page - 1) * pageSize
+	err := query.Order("id DESC").Offset(offset).Limit(pageSize).Find(&coupons).Error
+	return coupons, total, err
+}
+
+// UpdateCoupon 更新优惠券
+// 参数:
+//
+//	coupon - 优惠券对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (c *CouponDAO) UpdateCoupon(coupon *model.Coupon) error {
+	// 已使用次数由下单和取消流程原子维护，更新优惠券配置时不覆盖
+	// 对应SQL: UPDATE coupons SET name = ?, type = ?, value = ?, ... WHERE id = coupon.ID;
+	return c.db.Omit("used_count").Save(coupon).Error
+}
+
+// DeleteCoupon 删除优惠券
+// 参数:
+//
+//	id - 优惠券ID
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (c *CouponDAO) DeleteCoupon(id int) error {
+	// 对应SQL: DELETE FROM coupons WHERE id = id;
+	return c.db.Delete(&model.Coupon{}, id).Error
+}
+
+// AddUsedCount 调整优惠券的已使用次数
+// 参数:
+//
+//	id - 优惠券ID
+//	delta - 变化量，使用时为1，退回时为-1
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (c *CouponDAO) AddUsedCount(id, delta int) error {
+	// 对应SQL: UPDATE coupons SET used_count = GREATEST(used_count + delta, 0) WHERE id = id;
+	return c.db.Model(&model.Coupon{}).
+		Where("id = ?", id).
+		Update("used_count", gorm.Expr("GREATEST(used_count + ?, 0)", delta)).Error
+}
+
+// CountUserRedemptions 统计用户对某张优惠券的有效使用次数
+// 参数:
+//
+//	couponID - 优惠券ID
+//	userID - 用户ID
+//
+// 返回:
+//
+//	int64 - 有效使用次数（不含已退回）
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CouponDAO) CountUserRedemptions(couponID, userID int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = couponID AND user_id = userID AND status = 0;
+	err := c.db.Model(&model.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ? AND status = ?", couponID, userID, model.CouponRedemptionStatusUsed).
+		Count(&count).Error
+	return count, err
+}
+
+// CountRedemptions 统计优惠券的使用记录数量（含已退回）
+// 参数:
+//
+//	couponID - 优惠券ID
+//
+// 返回:
+//
+//	int64 - 使用记录数量
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CouponDAO) CountRedemptions(couponID int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = couponID;
+	err := c.db.Model(&model.CouponRedemption{}).Where("coupon_id = ?", couponID).Count(&count).Error
+	return count, err
+}
+
+// CreateRedemption 创建优惠券使用记录
+// 参数:
+//
+//	redemption - 使用记录对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (c *CouponDAO) CreateRedemption(redemption *model.CouponRedemption) error {
+	// 对应SQL: INSERT INTO coupon_redemptions (coupon_id, user_id, order_id, discount_amount, status) VALUES (...);
+	return c.db.Create(redemption).Error
+}
+
+// GetUsedRedemptionByOrderID 获取订单的有效优惠券使用记录
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	*model.CouponRedemption - 使用记录对象指针
+//	error - 订单未使用优惠券或已退回时返回gorm.ErrRecordNotFound
+func (c *CouponDAO) GetUsedRedemptionByOrderID(orderID int) (*model.CouponRedemption, error) {
+	var redemption model.CouponRedemption
+	// 对应SQL: SELECT * FROM coupon_redemptions WHERE order_id = orderID AND status = 0 LIMIT 1;
+	err := c.db.Where("order_id = ? AND status = ?", orderID, model.CouponRedemptionStatusUsed).First(&redemption).Error
+	return &redemption, err
+}
+
+// UpdateRedemptionStatus 更新优惠券使用记录的状态
+// 参数:
+//
+//	id - 使用记录ID
+//	status - 目标状态
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (c *CouponDAO) UpdateRedemptionStatus(id, status int) error {
+	// 对应SQL: UPDATE coupon_redemptions SET status = status WHERE id = id;
+	return c.db.Model(&model.CouponRedemption{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// GetRedemptions 获取优惠券的使用记录（分页）
+// 参数:
+//
+//	couponID - 优惠券ID
+//	page - 页码
+//	pageSize - 每页大小
+//
+// 返回:
+//
+//	[]*model.CouponRedemption - 使用记录切片（包含用户和订单信息）
+//	int64 - 记录总数
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CouponDAO) GetRedemptions(couponID, page, pageSize int) ([]*model.CouponRedemption, int64, error) {
+	var redemptions []*model.CouponRedemption
+	var total int64
+
+	query := c.db.Model(&model.CouponRedemption{}).Where("coupon_id = ?", couponID)
+
+	// 对应SQL: SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = couponID;
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 对应SQL: SELECT * FROM coupon_redemptions WHERE coupon_id = couponID ORDER BY id DESC LIMIT pageSize OFFSET (page-1)*pageSize;
+	// 以及对应的users和orders
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").Preload("Order").
+		Order("id DESC").Offset(offset).Limit(pageSize).
+		Find(&redemptions).Error
+	return redemptions, total, err
+}
+
+// GetCouponStats 统计优惠券的核销情况
+// 参数:
+//
+//	couponID - 优惠券ID
+//
+// 返回:
+//
+//	*model.CouponStats - 核销统计
+//	error - 如果查询过程中出现错误则返回错误
+func (c *CouponDAO) GetCouponStats(couponID int) (*model.CouponStats, error) {
+	stats := &model.CouponStats{CouponID: couponID}
+
+	// 对应SQL:
+	// SELECT COUNT(*) AS redemption_count, COUNT(DISTINCT r.user_id) AS user_count,
+	//        COALESCE(SUM(r.discount_amount), 0) AS total_discount,
+	//        COALESCE(SUM(o.total_amount), 0) AS total_order_amount,
+	//        COALESCE(SUM(CASE WHEN o.is_paid THEN 1 ELSE 0 END), 0) AS paid_order_count
+	// FROM coupon_redemptions r JOIN orders o ON o.id = r.order_id
+	// WHERE r.coupon_id = couponID AND r.status = 0;
+	err := c.db.Table("coupon_redemptions AS r").
+		Joins("JOIN orders AS o ON o.id = r.order_id").
+		Where("r.coupon_id = ? AND r.status = ?", couponID, model.CouponRedemptionStatusUsed).
+		Select("COUNT(*) AS redemption_count, COUNT(DISTINCT r.user_id) AS user_count, " +
+			"COALESCE(SUM(r.discount_amount), 0) AS total_discount, " +
+			"COALESCE(SUM(o.total_amount), 0) AS total_order_amount, " +
+			"COALESCE(SUM(CASE WHEN o.is_paid THEN 1 ELSE 0 END), 0) AS paid_order_count").
+		Scan(stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// 对应SQL: SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = couponID AND status = 1;
+	err = c.db.Model(&model.CouponRedemption{}).
+		Where("coupon_id = ? AND status = ?", couponID, model.CouponRedemptionStatusReleased).
+		Count(&stats.ReleasedCount).Error
+	return stats, err
+}
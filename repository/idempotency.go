@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"bookstore/global"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// IdempotencyRecord 幂等键对应的请求记录
+// 请求处理中时只记录请求指纹，处理完成后保存首次响应用于重放
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`            // 请求指纹，由请求方法、路径和请求体计算
+	Completed   bool   `json:"completed"`              // 首次请求是否已处理完成
+	StatusCode  int    `json:"status_code,omitempty"`  // 首次响应的状态码
+	ContentType string `json:"content_type,omitempty"` // 首次响应的Content-Type
+	Body        []byte `json:"body,omitempty"`         // 首次响应的响应体
+}
+
+// IdempotencyDAO 幂等键数据访问对象
+// 幂等记录以JSON格式保存在Redis中，过期后自动删除
+type IdempotencyDAO struct {
+	rdb *redis.Client // Redis客户端实例
+}
+
+// NewIdempotencyDAO 创建新的幂等键DAO实例
+// 返回:
+//
+//	*IdempotencyDAO - 初始化后的幂等键数据访问对象
+func NewIdempotencyDAO() *IdempotencyDAO {
+	return &IdempotencyDAO{
+		rdb: global.RedisClient, // 从全局变量获取Redis连接
+	}
+}
+
+// idempotencyKey 生成幂等记录在Redis中的键
+func idempotencyKey(key string) string {
+	return "idempotency:" + key
+}
+
+// Acquire 尝试占用幂等键，仅在该键不存在时写入处理中的记录
+// 参数:
+//
+//	key - 幂等键
+//	record - 处理中的请求记录
+//	ttl - 占用的过期时间，防止请求异常中断后幂等键一直被占用
+//
+// 返回:
+//
+//	bool - 占用成功返回true，该键已存在时返回false
+//	error - 如果写入过程中出现错误则返回错误
+func (i *IdempotencyDAO) Acquire(key string, record *IdempotencyRecord, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return false, err
+	}
+	// 对应Redis命令: SET idempotency:{key} {record} NX PX {ttl}
+	return i.rdb.SetNX(context.Background(), idempotencyKey(key), data, ttl).Result()
+}
+
+// Get 获取幂等键对应的请求记录
+// 参数:
+//
+//	key - 幂等键
+//
+// 返回:
+//
+//	*IdempotencyRecord - 请求记录，不存在时为nil
+//	error - 如果读取过程中出现错误则返回错误
+func (i *IdempotencyDAO) Get(key string) (*IdempotencyRecord, error) {
+	// 对应Redis命令: GET idempotency:{key}
+	data, err := i.rdb.Get(context.Background(), idempotencyKey(key)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Save 保存处理完成的请求记录
+// 参数:
+//
+//	key - 幂等键
+//	record - 包含首次响应的请求记录
+//	ttl - 记录的保留时间
+//
+// 返回:
+//
+//	error - 如果写入过程中出现错误则返回错误
+func (i *IdempotencyDAO) Save(key string, record *IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	// 对应Redis命令: SET idempotency:{key} {record} PX {ttl}
+	return i.rdb.Set(context.Background(), idempotencyKey(key), data, ttl).Err()
+}
+
+// Release 释放幂等键，之后可以使用同一个键重新发起请求
+// 参数:
+//
+//	key - 幂等键
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (i *IdempotencyDAO) Release(key string) error {
+	// 对应Redis命令: DEL idempotency:{key}
+	return i.rdb.Del(context.Background(), idempotencyKey(key)).Err()
+}
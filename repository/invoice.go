@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvoiceDAO 发票数据访问对象
+// 封装了所有与发票及发票号序列相关的数据库操作
+type InvoiceDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewInvoiceDAO 创建新的发票DAO实例
+// 返回:
+//
+//	*InvoiceDAO - 初始化后的发票数据访问对象
+func NewInvoiceDAO() *InvoiceDAO {
+	return &InvoiceDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的发票DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*InvoiceDAO - 使用该事务执行所有操作的发票数据访问对象
+func (i *InvoiceDAO) WithTx(tx *gorm.DB) *InvoiceDAO {
+	return &InvoiceDAO{db: tx}
+}
+
+// NextSequence 分配指定序列的下一个序号
+// 序列行在事务提交前保持锁定，事务回滚时序号一并回滚；
+// 依赖LAST_INSERT_ID()读取本连接分配的序号，必须在事务中调用
+// 参数:
+//
+//	name - 序列名称
+//
+// 返回:
+//
+//	int64 - 分配的序号，从1开始
+//	error - 如果更新过程中出现错误则返回错误
+func (i *InvoiceDAO) NextSequence(name string) (int64, error) {
+	// 对应SQL:
+	// 1. INSERT INTO invoice_sequences (name, value) VALUES (name, LAST_INSERT_ID(1))
+	//    ON DUPLICATE KEY UPDATE value = LAST_INSERT_ID(value + 1);
+	// 2. SELECT LAST_INSERT_ID();
+	err := i.db.Exec("INSERT INTO invoice_sequences (name, value) VALUES (?, LAST_INSERT_ID(1)) "+
+		"ON DUPLICATE KEY UPDATE value = LAST_INSERT_ID(value + 1)", name).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var value int64
+	err = i.db.Raw("SELECT LAST_INSERT_ID()").Scan(&value).Error
+	return value, err
+}
+
+// CreateInvoice 创建发票
+// 参数:
+//
+//	invoice - 发票对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (i *InvoiceDAO) CreateInvoice(invoice *model.Invoice) error {
+	// 对应SQL: INSERT INTO invoices (invoice_no, order_id, user_id, ...) VALUES (...);
+	return i.db.Create(invoice).Error
+}
+
+// GetInvoiceByID 根据ID获取发票
+// 参数:
+//
+//	id - 发票ID
+//
+// 返回:
+//
+//	*model.Invoice - 发票对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (i *InvoiceDAO) GetInvoiceByID(id int) (*model.Invoice, error) {
+	var invoice model.Invoice
+	// 对应SQL: SELECT * FROM invoices WHERE id = id LIMIT 1;
+	err := i.db.First(&invoice, id).Error
+	return &invoice, err
+}
+
+// GetIssuedInvoiceByOrderID 获取订单当前有效（未作废）的发票
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	*model.Invoice - 发票对象指针
+//	error - 没有有效发票时返回gorm.ErrRecordNotFound
+func (i *InvoiceDAO) GetIssuedInvoiceByOrderID(orderID int) (*model.Invoice, error) {
+	var invoice model.Invoice
+	// 对应SQL: SELECT * FROM invoices WHERE order_id = orderID AND status = 0 ORDER BY id DESC LIMIT 1;
+	err := i.db.Where("order_id = ? AND status = ?", orderID, model.InvoiceStatusIssued).
+		Order("id DESC").First(&invoice).Error
+	return &invoice, err
+}
+
+// GetInvoicesByOrderID 获取订单的全部发票（包括已作废的）
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	[]*model.Invoice - 发票列表，按开具时间倒序排列
+//	error - 如果查询过程中出现错误则返回错误
+func (i *InvoiceDAO) GetInvoicesByOrderID(orderID int) ([]*model.Invoice, error) {
+	var invoices []*model.Invoice
+	// 对应SQL: SELECT * FROM invoices WHERE order_id = orderID ORDER BY id DESC;
+	err := i.db.Where("order_id = ?", orderID).Order("id DESC").Find(&invoices).Error
+	return invoices, err
+}
+
+// CountInvoicesByOrderID 统计订单开具过的发票数量（包括已作废的）
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	int64 - 发票数量
+//	error - 如果查询过程中出现错误则返回错误
+func (i *InvoiceDAO) CountInvoicesByOrderID(orderID int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM invoices WHERE order_id = orderID;
+	err := i.db.Model(&model.Invoice{}).Where("order_id = ?", orderID).Count(&count).Error
+	return count, err
+}
+
+// VoidInvoice 作废发票，仅对已开具的发票生效
+// 参数:
+//
+//	id - 发票ID
+//	adminID - 作废的管理员ID
+//	reason - 作废原因
+//
+// 返回:
+//
+//	bool - 发票已被作废（本次调用生效）时返回true，发票不存在或已作废时返回false
+//	error - 如果更新过程中出现错误则返回错误
+func (i *InvoiceDAO) VoidInvoice(id, adminID int, reason string) (bool, error) {
+	// 对应SQL: UPDATE invoices SET status = 1, voided_at = NOW(), voided_by = adminID, void_reason = reason, updated_at = NOW()
+	//          WHERE id = id AND status = 0;
+	result := i.db.Model(&model.Invoice{}).
+		Where("id = ? AND status = ?", id, model.InvoiceStatusIssued).
+		Updates(map[string]any{
+			"status":      model.InvoiceStatusVoided,
+			"voided_at":   time.Now(),
+			"voided_by":   adminID,
+			"void_reason": reason,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // OrderDAO 订单数据访问对象
@@ -25,6 +26,18 @@ func NewOrderDAO() *OrderDAO {
 	}
 }
 
+// WithTx 返回绑定到指定事务的订单DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*OrderDAO - 使用该事务执行所有操作的订单数据访问对象
+func (o *OrderDAO) WithTx(tx *gorm.DB) *OrderDAO {
+	return &OrderDAO{db: tx}
+}
+
 // CreateOrder 创建订单
 // 参数:
 //
@@ -47,7 +60,7 @@ func (o *OrderDAO) CreateOrder(order *model.Order) error {
 //
 // 返回:
 //
-//	*model.Order - 订单对象指针（包含订单项和图书信息）
+//	*model.Order - 订单对象指针（包含订单项、图书信息、状态变更记录、物流时间线和优惠明细）
 //	error - 如果查询过程中出现错误则返回错误
 func (o *OrderDAO) GetOrderByID(id int) (*model.Order, error) {
 	var order model.Order
@@ -55,7 +68,33 @@ func (o *OrderDAO) GetOrderByID(id int) (*model.Order, error) {
 	// 1. SELECT * FROM orders WHERE id = id LIMIT 1;
 	// 2. SELECT * FROM order_items WHERE order_id = id;
 	// 3. SELECT * FROM books WHERE id IN (SELECT book_id FROM order_items WHERE order_id = id);
-	err := o.db.Preload("OrderItems.Book").First(&order, id).Error
+	// 4. SELECT * FROM order_status_logs WHERE order_id = id ORDER BY id ASC;
+	// 5. SELECT * FROM shipments WHERE order_id = id ORDER BY id ASC; 及对应的shipment_events
+	// 6. SELECT * FROM order_discounts WHERE order_id = id;
+	err := o.db.Preload("OrderItems.Book").
+		Preload("StatusLogs", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Shipments", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Shipments.Events", func(db *gorm.DB) *gorm.DB { return db.Order("occurred_at ASC, id ASC") }).
+		Preload("Discounts").
+		First(&order, id).Error
+	return &order, err
+}
+
+// GetOrderForUpdate 根据ID获取订单并加行锁（需在事务中调用）
+// 参数:
+//
+//	id - 订单ID
+//
+// 返回:
+//
+//	*model.Order - 订单对象指针（包含订单项）
+//	error - 如果查询过程中出现错误则返回错误
+func (o *OrderDAO) GetOrderForUpdate(id int) (*model.Order, error) {
+	var order model.Order
+	// 对应SQL:
+	// 1. SELECT * FROM orders WHERE id = id LIMIT 1 FOR UPDATE;
+	// 2. SELECT * FROM order_items WHERE order_id = id;
+	err := o.db.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("OrderItems").First(&order, id).Error
 	return &order, err
 }
 
@@ -111,6 +150,149 @@ func (o *OrderDAO) GetUserOrders(userID int, page, pageSize int) ([]*model.Order
 	return orders, total, err
 }
 
+// OrderFilter 订单查询条件（管理员）
+// 零值字段表示不按该条件过滤
+type OrderFilter struct {
+	Status    *int       // 订单状态
+	UserID    int        // 用户ID
+	Username  string     // 用户名关键词
+	OrderNo   string     // 订单号关键词
+	StartTime *time.Time // 下单时间下限（含）
+	EndTime   *time.Time // 下单时间上限（不含）
+	MinAmount *int       // 订单金额下限（含）
+	MaxAmount *int       // 订单金额上限（含）
+}
+
+// GetOrdersForAdmin 分页获取订单（管理员用，支持筛选）
+// 参数:
+//
+//	filter - 查询条件
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*model.Order - 当前页的订单对象切片（包含用户、订单项和图书信息）
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (o *OrderDAO) GetOrdersForAdmin(filter *OrderFilter, page, pageSize int) ([]*model.Order, int64, error) {
+	var orders []*model.Order
+	var total int64
+
+	query := o.filterOrders(filter)
+
+	// 获取总数
+	// 对应SQL: SELECT COUNT(*) FROM orders [WHERE conditions...]
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	// 对应SQL: SELECT * FROM orders [WHERE conditions...] ORDER BY created_at DESC LIMIT pageSize OFFSET offset;
+	// 并预加载用户、订单项及图书信息
+	offset := (page - 1) * pageSize
+	err = query.Preload("User").Preload("OrderItems.Book").
+		Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&orders).Error
+	return orders, total, err
+}
+
+// GetOrdersAfterID 按ID游标获取一批订单（管理员导出用，支持筛选）
+// 使用 id > afterID 的游标分批读取，不受翻页偏移量影响，适合逐批遍历大量订单
+// 参数:
+//
+//	filter - 查询条件
+//	afterID - 上一批最后一个订单的ID，第一批传0
+//	limit - 本批最多返回的订单数
+//
+// 返回:
+//
+//	[]*model.Order - 按ID升序排列的订单对象切片（包含用户、订单项和图书信息），为空表示已读取完毕
+//	error - 如果查询过程中出现错误则返回错误
+func (o *OrderDAO) GetOrdersAfterID(filter *OrderFilter, afterID, limit int) ([]*model.Order, error) {
+	var orders []*model.Order
+	// 对应SQL: SELECT * FROM orders [WHERE conditions...] AND id > afterID ORDER BY id ASC LIMIT limit;
+	// 并预加载用户、订单项及图书信息
+	err := o.filterOrders(filter).Where("id > ?", afterID).
+		Preload("User").Preload("OrderItems", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("OrderItems.Book").
+		Order("id ASC").Limit(limit).Find(&orders).Error
+	return orders, err
+}
+
+// filterOrders 根据查询条件构建订单查询
+// 参数:
+//
+//	filter - 查询条件
+//
+// 返回:
+//
+//	*gorm.DB - 带有筛选条件的订单查询
+func (o *OrderDAO) filterOrders(filter *OrderFilter) *gorm.DB {
+	query := o.db.Model(&model.Order{})
+
+	if filter.Status != nil {
+		// 对应SQL条件: status = status
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.UserID > 0 {
+		// 对应SQL条件: user_id = userID
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Username != "" {
+		// 对应SQL条件: user_id IN (SELECT id FROM users WHERE username LIKE '%username%')
+		query = query.Where("user_id IN (?)", o.db.Model(&model.User{}).Select("id").Where("username LIKE ?", "%"+filter.Username+"%"))
+	}
+	if filter.OrderNo != "" {
+		// 对应SQL条件: order_no LIKE '%orderNo%'
+		query = query.Where("order_no LIKE ?", "%"+filter.OrderNo+"%")
+	}
+	if filter.StartTime != nil {
+		// 对应SQL条件: created_at >= startTime
+		query = query.Where("created_at >= ?", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		// 对应SQL条件: created_at < endTime
+		query = query.Where("created_at < ?", *filter.EndTime)
+	}
+	if filter.MinAmount != nil {
+		// 对应SQL条件: total_amount >= minAmount
+		query = query.Where("total_amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		// 对应SQL条件: total_amount <= maxAmount
+		query = query.Where("total_amount <= ?", *filter.MaxAmount)
+	}
+	return query
+}
+
+// GetOrderByIDForAdmin 根据ID获取订单详情（管理员用）
+// 参数:
+//
+//	id - 订单ID
+//
+// 返回:
+//
+//	*model.Order - 订单对象指针（包含用户、订单项、图书、状态变更记录、发货记录和优惠明细）
+//	error - 如果查询过程中出现错误则返回错误
+func (o *OrderDAO) GetOrderByIDForAdmin(id int) (*model.Order, error) {
+	var order model.Order
+	// 对应SQL:
+	// 1. SELECT * FROM orders WHERE id = id LIMIT 1;
+	// 2. SELECT * FROM users WHERE id = order.user_id;
+	// 3. SELECT * FROM order_items WHERE order_id = id; 及对应的books
+	// 4. SELECT * FROM order_status_logs WHERE order_id = id ORDER BY id ASC;
+	// 5. SELECT * FROM shipments WHERE order_id = id ORDER BY id ASC; 及对应的shipment_events
+	// 6. SELECT * FROM order_discounts WHERE order_id = id;
+	err := o.db.Preload("User").Preload("OrderItems.Book").
+		Preload("StatusLogs", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Shipments", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Shipments.Events", func(db *gorm.DB) *gorm.DB { return db.Order("occurred_at ASC, id ASC") }).
+		Preload("Discounts").
+		First(&order, id).Error
+	return &order, err
+}
+
 // UpdateOrderStatus 更新订单状态
 // 参数:
 //
@@ -126,6 +308,20 @@ func (o *OrderDAO) UpdateOrderStatus(orderID int, status int) error {
 	return err
 }
 
+// CreateStatusLog 创建订单状态变更记录
+// 参数:
+//
+//	log - 状态变更记录对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (o *OrderDAO) CreateStatusLog(log *model.OrderStatusLog) error {
+	// 对应SQL: INSERT INTO order_status_logs (order_id, from_status, to_status, operator, ...) VALUES (...);
+	err := o.db.Create(log).Error
+	return err
+}
+
 // MarkOrderAsPaid 标记订单为已支付
 // 参数:
 //
@@ -138,21 +334,102 @@ func (o *OrderDAO) MarkOrderAsPaid(orderID int) error {
 	now := time.Now()
 	// 对应SQL: UPDATE orders SET status = 1, is_paid = true, payment_time = now WHERE id = orderID;
 	err := o.db.Model(&model.Order{}).Where("id = ?", orderID).Updates(map[string]any{
-		"status":       1,
+		"status":       model.OrderStatusPaid,
 		"is_paid":      true,
 		"payment_time": &now,
 	}).Error
 	return err
 }
 
-// GenerateOrderNo 生成订单号
+// AddItemRefundedQuantity 累加订单项的已退款数量
+// 参数:
+//
+//	orderItemID - 订单项ID
+//	quantity - 本次退款数量
+//
+// 返回:
+//
+//	error - 累加后超过购买数量时返回错误
+func (o *OrderDAO) AddItemRefundedQuantity(orderItemID, quantity int) error {
+	// 对应SQL: UPDATE order_items SET refunded_quantity = refunded_quantity + quantity
+	// WHERE id = orderItemID AND refunded_quantity + quantity <= quantity;
+	result := o.db.Model(&model.OrderItem{}).
+		Where("id = ? AND refunded_quantity + ? <= quantity", orderItemID, quantity).
+		Update("refunded_quantity", gorm.Expr("refunded_quantity + ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("订单项%d的退款数量超过可退数量", orderItemID)
+	}
+	return nil
+}
+
+// AddRefundedAmount 累加订单的已退款金额
+// 参数:
+//
+//	orderID - 订单ID
+//	amount - 本次退款金额
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (o *OrderDAO) AddRefundedAmount(orderID, amount int) error {
+	// 对应SQL: UPDATE orders SET refunded_amount = refunded_amount + amount WHERE id = orderID;
+	err := o.db.Model(&model.Order{}).
+		Where("id = ?", orderID).
+		Update("refunded_amount", gorm.Expr("refunded_amount + ?", amount)).Error
+	return err
+}
+
+// GetExpiredPendingOrderIDs 获取超时未支付的订单ID
+// 参数:
+//
+//	before - 创建时间早于该时间的待支付订单视为超时
+//	limit - 单次返回的最大数量
+//
 // 返回:
 //
-//	string - 生成的订单号
-func (o *OrderDAO) GenerateOrderNo() string {
-	// 使用时间戳生成唯一订单号
-	orderNo := fmt.Sprintf("ORD%d", time.Now().UnixNano())
-	return orderNo
+//	[]int - 超时订单ID切片
+//	error - 如果查询过程中出现错误则返回错误
+func (o *OrderDAO) GetExpiredPendingOrderIDs(before time.Time, limit int) ([]int, error) {
+	var ids []int
+	// 对应SQL: SELECT id FROM orders WHERE status = 0 AND created_at < before ORDER BY id ASC LIMIT limit;
+	err := o.db.Model(&model.Order{}).
+		Where("status = ? AND created_at < ?", model.OrderStatusPending, before).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetOverdueShippedOrderIDs 获取发货后超过确认收货期限的订单ID
+// 以订单最后一个包裹的发货时间为准，分批发货的订单从最后一次发货开始计时
+// 参数:
+//
+//	before - 最后发货时间早于该时间的已发货订单视为超期
+//	limit - 单次返回的最大数量
+//
+// 返回:
+//
+//	[]int - 超期订单ID切片
+//	error - 如果查询过程中出现错误则返回错误
+func (o *OrderDAO) GetOverdueShippedOrderIDs(before time.Time, limit int) ([]int, error) {
+	var ids []int
+	// 对应SQL:
+	// SELECT id FROM orders WHERE status = 3 AND id IN (
+	//     SELECT order_id FROM shipments GROUP BY order_id HAVING MAX(shipped_at) < before
+	// ) ORDER BY id ASC LIMIT limit;
+	shipped := o.db.Model(&model.Shipment{}).
+		Select("order_id").
+		Group("order_id").
+		Having("MAX(shipped_at) < ?", before)
+	err := o.db.Model(&model.Order{}).
+		Where("status = ? AND id IN (?)", model.OrderStatusShipped, shipped).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
 }
 
 // CreateOrderWithItems 创建订单和订单项（事务操作）
@@ -168,6 +445,7 @@ func (o *OrderDAO) CreateOrderWithItems(order *model.Order, items []*model.Order
 	// 对应SQL:
 	// BEGIN TRANSACTION;
 	// INSERT INTO orders (order_no, user_id, total_amount, ...) VALUES (order.OrderNo, order.UserID, order.TotalAmount, ...);
+	// 对于每条优惠明细: INSERT INTO order_discounts (order_id, coupon_id, amount, ...) VALUES (order.ID, ...);
 	// 对于每个订单项: INSERT INTO order_items (order_id, book_id, quantity, price, ...) VALUES (order.ID, item.BookID, item.Quantity, item.Price, ...);
 	// COMMIT;
 	err := o.db.Transaction(func(tx *gorm.DB) error {
@@ -221,9 +499,9 @@ func (o *OrderDAO) GetOrderStatistics(userID int) (map[string]any, error) {
 		return nil, err
 	}
 
-	// 对应SQL: SELECT COUNT(*) FROM orders WHERE user_id = userID AND is_paid = false;
+	// 对应SQL: SELECT COUNT(*) FROM orders WHERE user_id = userID AND status = 0;
 	err = o.db.Model(&model.Order{}).
-		Where("user_id = ? AND is_paid = ?", userID, false).
+		Where("user_id = ? AND status = ?", userID, model.OrderStatusPending).
 		Count(&stats.PendingOrders).Error
 	if err != nil {
 		return nil, err
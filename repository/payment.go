@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentDAO 支付单数据访问对象
+// 封装了所有与支付单相关的数据库操作
+type PaymentDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewPaymentDAO 创建新的支付单DAO实例
+// 返回:
+//
+//	*PaymentDAO - 初始化后的支付单数据访问对象
+func NewPaymentDAO() *PaymentDAO {
+	return &PaymentDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的支付单DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*PaymentDAO - 使用该事务执行所有操作的支付单数据访问对象
+func (p *PaymentDAO) WithTx(tx *gorm.DB) *PaymentDAO {
+	return &PaymentDAO{db: tx}
+}
+
+// CreatePayment 创建支付单
+// 参数:
+//
+//	payment - 支付单对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (p *PaymentDAO) CreatePayment(payment *model.Payment) error {
+	// 对应SQL: INSERT INTO payments (payment_no, order_id, user_id, provider, amount, ...) VALUES (...);
+	err := p.db.Create(payment).Error
+	return err
+}
+
+// GetPaymentByPaymentNo 根据支付单号获取支付单
+// 参数:
+//
+//	paymentNo - 支付单号
+//
+// 返回:
+//
+//	*model.Payment - 支付单对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (p *PaymentDAO) GetPaymentByPaymentNo(paymentNo string) (*model.Payment, error) {
+	var payment model.Payment
+	// 对应SQL: SELECT * FROM payments WHERE payment_no = paymentNo LIMIT 1;
+	err := p.db.Where("payment_no = ?", paymentNo).First(&payment).Error
+	return &payment, err
+}
+
+// GetPaymentByTransactionID 根据支付渠道交易号获取支付单
+// 参数:
+//
+//	provider - 支付渠道名称
+//	transactionID - 支付渠道交易号
+//
+// 返回:
+//
+//	*model.Payment - 支付单对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (p *PaymentDAO) GetPaymentByTransactionID(provider, transactionID string) (*model.Payment, error) {
+	var payment model.Payment
+	// 对应SQL: SELECT * FROM payments WHERE provider = provider AND transaction_id = transactionID LIMIT 1;
+	err := p.db.Where("provider = ? AND transaction_id = ?", provider, transactionID).First(&payment).Error
+	return &payment, err
+}
+
+// GetPaymentForUpdate 根据支付单号获取支付单并加行锁（需在事务中调用）
+// 参数:
+//
+//	paymentNo - 支付单号
+//
+// 返回:
+//
+//	*model.Payment - 支付单对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (p *PaymentDAO) GetPaymentForUpdate(paymentNo string) (*model.Payment, error) {
+	var payment model.Payment
+	// 对应SQL: SELECT * FROM payments WHERE payment_no = paymentNo LIMIT 1 FOR UPDATE;
+	err := p.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("payment_no = ?", paymentNo).First(&payment).Error
+	return &payment, err
+}
+
+// GetPaidPaymentByOrderID 获取订单支付成功的支付单
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	*model.Payment - 支付单对象指针（包括已部分或全额退款的支付单）
+//	error - 订单没有支付成功的支付单时返回gorm.ErrRecordNotFound
+func (p *PaymentDAO) GetPaidPaymentByOrderID(orderID int) (*model.Payment, error) {
+	var payment model.Payment
+	// 对应SQL: SELECT * FROM payments WHERE order_id = orderID AND status IN (1, 3) ORDER BY id DESC LIMIT 1;
+	err := p.db.Where("order_id = ? AND status IN ?", orderID, []int{model.PaymentStatusSuccess, model.PaymentStatusRefunded}).
+		Order("id DESC").First(&payment).Error
+	return &payment, err
+}
+
+// UpdatePayment 更新支付单
+// 参数:
+//
+//	payment - 支付单对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (p *PaymentDAO) UpdatePayment(payment *model.Payment) error {
+	// 对应SQL: UPDATE payments SET transaction_id = ..., status = ..., callback_payload = ..., paid_at = ... WHERE id = payment.ID;
+	err := p.db.Save(payment).Error
+	return err
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PublisherDAO 出版社数据访问对象
+// 封装了出版社和出版社别名的数据库操作
+type PublisherDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewPublisherDAO 创建新的出版社DAO实例
+// 返回:
+//
+//	*PublisherDAO - 初始化后的出版社数据访问对象
+func NewPublisherDAO() *PublisherDAO {
+	return &PublisherDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的出版社DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*PublisherDAO - 使用该事务执行所有操作的出版社数据访问对象
+func (p *PublisherDAO) WithTx(tx *gorm.DB) *PublisherDAO {
+	return &PublisherDAO{db: tx}
+}
+
+// PublisherSummary 出版社列表项，附带关联的图书数量
+type PublisherSummary struct {
+	model.Publisher `gorm:"embedded"`
+	BookCount       int64 `json:"book_count"` // 关联的图书数量（含下架图书）
+}
+
+// GetPublisherByID 根据ID获取出版社
+// 参数:
+//
+//	id - 出版社ID
+//
+// 返回:
+//
+//	*model.Publisher - 出版社对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (p *PublisherDAO) GetPublisherByID(id int) (*model.Publisher, error) {
+	var publisher model.Publisher
+	// 对应SQL: SELECT * FROM publishers WHERE id = id LIMIT 1;
+	err := p.db.First(&publisher, id).Error
+	return &publisher, err
+}
+
+// GetPublishersForUpdate 根据ID获取出版社并加行锁（需在事务中调用）
+// 参数:
+//
+//	ids - 出版社ID列表
+//
+// 返回:
+//
+//	[]*model.Publisher - 出版社对象切片，不存在的ID不返回
+//	error - 如果查询过程中出现错误则返回错误
+func (p *PublisherDAO) GetPublishersForUpdate(ids []int) ([]*model.Publisher, error) {
+	var publishers []*model.Publisher
+	// 对应SQL: SELECT * FROM publishers WHERE id IN (ids) ORDER BY id FOR UPDATE;
+	err := p.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(&publishers).Error
+	return publishers, err
+}
+
+// GetPublishersByPage 分页获取出版社列表，按名称模糊搜索
+// 参数:
+//
+//	keyword - 名称关键词，为空时不过滤
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*PublisherSummary - 当前页的出版社及其图书数量
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (p *PublisherDAO) GetPublishersByPage(keyword string, page, pageSize int) ([]*PublisherSummary, int64, error) {
+	// Count会修改查询的SELECT子句，每次查询使用新的查询对象
+	query := func() *gorm.DB {
+		q := p.db.Model(&model.Publisher{})
+		if keyword != "" {
+			q = q.Where("name LIKE ?", "%"+keyword+"%")
+		}
+		return q
+	}
+
+	var total int64
+	// 对应SQL: SELECT COUNT(*) FROM publishers [WHERE name LIKE '%keyword%'];
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var publishers []*PublisherSummary
+	// 对应SQL: SELECT publishers.*, (SELECT COUNT(*) FROM books WHERE publisher_id = publishers.id) AS book_count
+	// FROM publishers [WHERE name LIKE '%keyword%'] ORDER BY name LIMIT pageSize OFFSET offset;
+	offset := (page - 1) * pageSize
+	err := query().Select("publishers.*, (SELECT COUNT(*) FROM books WHERE publisher_id = publishers.id) AS book_count").
+		Order("name").Offset(offset).Limit(pageSize).Scan(&publishers).Error
+	return publishers, total, err
+}
+
+// FindOrCreatePublisher 按名称或别名查找出版社，不存在时创建
+// 参数:
+//
+//	name - 出版社名称
+//
+// 返回:
+//
+//	*model.Publisher - 出版社对象指针
+//	error - 如果查询或创建过程中出现错误则返回错误
+func (p *PublisherDAO) FindOrCreatePublisher(name string) (*model.Publisher, error) {
+	var alias model.PublisherAlias
+	// 对应SQL: SELECT * FROM publisher_aliases WHERE name = name LIMIT 1;
+	err := p.db.Where("name = ?", name).Limit(1).Find(&alias).Error
+	if err != nil {
+		return nil, err
+	}
+	if alias.PublisherID > 0 {
+		return p.GetPublisherByID(alias.PublisherID)
+	}
+
+	// 并发创建同名出版社时唯一索引冲突，忽略冲突后按名称重新查询
+	// 对应SQL: INSERT INTO publishers (name, ...) VALUES (name, ...) ON DUPLICATE KEY UPDATE id = id;
+	if err := p.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Publisher{Name: name}).Error; err != nil {
+		return nil, err
+	}
+	var publisher model.Publisher
+	// 对应SQL: SELECT * FROM publishers WHERE name = name LIMIT 1;
+	err = p.db.Where("name = ?", name).First(&publisher).Error
+	return &publisher, err
+}
+
+// MergePublishers 将多个出版社合并到目标出版社（需在事务中调用）
+// 被合并出版社的图书改为关联目标出版社，名称和别名保留为目标出版社的别名，随后删除被合并出版社
+// 参数:
+//
+//	targetID - 保留的出版社ID
+//	sourceIDs - 被合并的出版社ID列表，不能包含targetID
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (p *PublisherDAO) MergePublishers(targetID int, sourceIDs []int) error {
+	// 对应SQL: UPDATE books SET publisher_id = targetID WHERE publisher_id IN (sourceIDs);
+	err := p.db.Model(&model.Book{}).Where("publisher_id IN ?", sourceIDs).UpdateColumn("publisher_id", targetID).Error
+	if err != nil {
+		return err
+	}
+
+	// 对应SQL: UPDATE publisher_aliases SET publisher_id = targetID WHERE publisher_id IN (sourceIDs);
+	err = p.db.Model(&model.PublisherAlias{}).Where("publisher_id IN ?", sourceIDs).Update("publisher_id", targetID).Error
+	if err != nil {
+		return err
+	}
+	// 对应SQL: INSERT INTO publisher_aliases (name, publisher_id) SELECT name, targetID FROM publishers WHERE id IN (sourceIDs)
+	//          ON DUPLICATE KEY UPDATE publisher_id = VALUES(publisher_id);
+	err = p.db.Exec("INSERT INTO publisher_aliases (name, publisher_id) SELECT name, ? FROM publishers WHERE id IN ? "+
+		"ON DUPLICATE KEY UPDATE publisher_id = VALUES(publisher_id)", targetID, sourceIDs).Error
+	if err != nil {
+		return err
+	}
+
+	// 对应SQL: DELETE FROM publishers WHERE id IN (sourceIDs);
+	return p.db.Where("id IN ?", sourceIDs).Delete(&model.Publisher{}).Error
+}
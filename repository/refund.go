@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RefundDAO 售后申请数据访问对象
+// 封装了所有与售后申请相关的数据库操作
+type RefundDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewRefundDAO 创建新的售后申请DAO实例
+// 返回:
+//
+//	*RefundDAO - 初始化后的售后申请数据访问对象
+func NewRefundDAO() *RefundDAO {
+	return &RefundDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的售后申请DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*RefundDAO - 使用该事务执行所有操作的售后申请数据访问对象
+func (r *RefundDAO) WithTx(tx *gorm.DB) *RefundDAO {
+	return &RefundDAO{db: tx}
+}
+
+// CreateRefund 创建售后申请及申请项
+// 参数:
+//
+//	refund - 售后申请对象指针，Items中的申请项会一并创建
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (r *RefundDAO) CreateRefund(refund *model.Refund) error {
+	// 对应SQL:
+	// 1. INSERT INTO refunds (refund_no, order_id, user_id, type, reason, amount, ...) VALUES (...);
+	// 2. 对于每个申请项: INSERT INTO refund_items (refund_id, order_item_id, book_id, quantity, amount, ...) VALUES (...);
+	err := r.db.Create(refund).Error
+	return err
+}
+
+// GetRefundByID 根据ID获取售后申请
+// 参数:
+//
+//	id - 售后申请ID
+//
+// 返回:
+//
+//	*model.Refund - 售后申请对象指针（包含申请项、图书和订单信息）
+//	error - 如果查询过程中出现错误则返回错误
+func (r *RefundDAO) GetRefundByID(id int) (*model.Refund, error) {
+	var refund model.Refund
+	// 对应SQL:
+	// 1. SELECT * FROM refunds WHERE id = id LIMIT 1;
+	// 2. SELECT * FROM refund_items WHERE refund_id = id; 及对应的books
+	// 3. SELECT * FROM orders WHERE id = refund.order_id;
+	err := r.db.Preload("Items.Book").Preload("Order").First(&refund, id).Error
+	return &refund, err
+}
+
+// GetRefundForUpdate 根据ID获取售后申请并加行锁（需在事务中调用）
+// 参数:
+//
+//	id - 售后申请ID
+//
+// 返回:
+//
+//	*model.Refund - 售后申请对象指针（包含申请项）
+//	error - 如果查询过程中出现错误则返回错误
+func (r *RefundDAO) GetRefundForUpdate(id int) (*model.Refund, error) {
+	var refund model.Refund
+	// 对应SQL:
+	// 1. SELECT * FROM refunds WHERE id = id LIMIT 1 FOR UPDATE;
+	// 2. SELECT * FROM refund_items WHERE refund_id = id;
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items").First(&refund, id).Error
+	return &refund, err
+}
+
+// GetRefunds 分页获取售后申请
+// 参数:
+//
+//	userID - 用户ID，0表示不按用户过滤
+//	status - 审核状态，nil表示不按状态过滤
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*model.Refund - 当前页的售后申请对象切片（包含申请项和图书信息）
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (r *RefundDAO) GetRefunds(userID int, status *int, page, pageSize int) ([]*model.Refund, int64, error) {
+	var refunds []*model.Refund
+	var total int64
+
+	query := r.db.Model(&model.Refund{})
+	if userID > 0 {
+		// 对应SQL条件: user_id = userID
+		query = query.Where("user_id = ?", userID)
+	}
+	if status != nil {
+		// 对应SQL条件: status = status
+		query = query.Where("status = ?", *status)
+	}
+
+	// 获取总数
+	// 对应SQL: SELECT COUNT(*) FROM refunds [WHERE conditions...]
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	// 对应SQL: SELECT * FROM refunds [WHERE conditions...] ORDER BY created_at DESC LIMIT pageSize OFFSET offset;
+	offset := (page - 1) * pageSize
+	err = query.Preload("Items.Book").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&refunds).Error
+	return refunds, total, err
+}
+
+// UpdateRefund 更新售后申请
+// 参数:
+//
+//	refund - 售后申请对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (r *RefundDAO) UpdateRefund(refund *model.Refund) error {
+	// 对应SQL: UPDATE refunds SET status = ..., admin_id = ..., admin_remark = ..., ... WHERE id = refund.ID;
+	err := r.db.Omit(clause.Associations).Save(refund).Error
+	return err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"bookstore/global"
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// searchPopularityKey 保存搜索词热度的有序集合，成员为规范化后的搜索词，分数为搜索次数
+const searchPopularityKey = "search:popularity"
+
+// SearchPopularityDAO 搜索热度数据访问对象
+// 搜索词热度保存在Redis有序集合中，多实例共享
+type SearchPopularityDAO struct {
+	rdb *redis.Client // Redis客户端实例
+}
+
+// NewSearchPopularityDAO 创建新的搜索热度DAO实例
+// 返回:
+//
+//	*SearchPopularityDAO - 初始化后的搜索热度数据访问对象
+func NewSearchPopularityDAO() *SearchPopularityDAO {
+	return &SearchPopularityDAO{
+		rdb: global.RedisClient, // 从全局变量获取Redis连接
+	}
+}
+
+// Increment 增加搜索词的搜索次数，并只保留最热门的部分搜索词
+// 参数:
+//
+//	term - 规范化后的搜索词
+//	keep - 保留的搜索词数量
+//
+// 返回:
+//
+//	float64 - 增加后的搜索次数
+//	error - 如果写入过程中出现错误则返回错误
+func (s *SearchPopularityDAO) Increment(term string, keep int) (float64, error) {
+	// 对应Redis命令: MULTI; ZINCRBY search:popularity 1 {term}; ZREMRANGEBYRANK search:popularity 0 -{keep+1}; EXEC
+	ctx := context.Background()
+	var incr *redis.FloatCmd
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.ZIncrBy(ctx, searchPopularityKey, 1, term)
+		pipe.ZRemRangeByRank(ctx, searchPopularityKey, 0, int64(-keep-1))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// GetTop 获取搜索次数最多的搜索词
+// 参数:
+//
+//	limit - 返回的最大数量
+//
+// 返回:
+//
+//	map[string]float64 - 以搜索词为键的搜索次数
+//	error - 如果读取过程中出现错误则返回错误
+func (s *SearchPopularityDAO) GetTop(limit int) (map[string]float64, error) {
+	// 对应Redis命令: ZREVRANGE search:popularity 0 {limit-1} WITHSCORES
+	members, err := s.rdb.ZRevRangeWithScores(context.Background(), searchPopularityKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make(map[string]float64, len(members))
+	for _, member := range members {
+		if term, ok := member.Member.(string); ok {
+			terms[term] = member.Score
+		}
+	}
+	return terms, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"bookstore/global"
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SequenceDAO 序号数据访问对象
+// 基于Redis INCR生成多实例间全局递增的序号
+type SequenceDAO struct {
+	rdb *redis.Client // Redis客户端实例
+}
+
+// NewSequenceDAO 创建新的序号DAO实例
+// 返回:
+//
+//	*SequenceDAO - 初始化后的序号数据访问对象
+func NewSequenceDAO() *SequenceDAO {
+	return &SequenceDAO{
+		rdb: global.RedisClient, // 从全局变量获取Redis连接
+	}
+}
+
+// Next 获取指定序列的下一个序号，并刷新序列的过期时间
+// 参数:
+//
+//	name - 序列名称，如order_no:20261016
+//	ttl - 序列最后一次使用后的保留时间，0表示不过期
+//
+// 返回:
+//
+//	int64 - 下一个序号，从1开始
+//	error - 如果写入过程中出现错误则返回错误
+func (s *SequenceDAO) Next(name string, ttl time.Duration) (int64, error) {
+	// 对应Redis命令: MULTI; INCR seq:{name}; EXPIRE seq:{name} {ttl}; EXEC
+	ctx := context.Background()
+	key := "seq:" + name
+	var incr *redis.IntCmd
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+)
+
+// SeriesDAO 丛书数据访问对象
+// 封装了丛书的数据库操作，丛书中的图书通过books.series_id关联
+type SeriesDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewSeriesDAO 创建新的丛书DAO实例
+// 返回:
+//
+//	*SeriesDAO - 初始化后的丛书数据访问对象
+func NewSeriesDAO() *SeriesDAO {
+	return &SeriesDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的丛书DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*SeriesDAO - 使用该事务执行所有操作的丛书数据访问对象
+func (s *SeriesDAO) WithTx(tx *gorm.DB) *SeriesDAO {
+	return &SeriesDAO{db: tx}
+}
+
+// SeriesSummary 丛书列表项，附带图书数量
+type SeriesSummary struct {
+	model.Series `gorm:"embedded"`
+	BookCount    int64 `json:"book_count"` // 丛书中的图书数量（含下架图书）
+}
+
+// GetSeriesByID 根据ID获取丛书
+// 参数:
+//
+//	id - 丛书ID
+//
+// 返回:
+//
+//	*model.Series - 丛书对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (s *SeriesDAO) GetSeriesByID(id int) (*model.Series, error) {
+	var series model.Series
+	// 对应SQL: SELECT * FROM series WHERE id = id LIMIT 1;
+	err := s.db.First(&series, id).Error
+	return &series, err
+}
+
+// GetSeriesByPage 分页获取丛书列表，按名称模糊搜索
+// 参数:
+//
+//	keyword - 名称关键词，为空时不过滤
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*SeriesSummary - 当前页的丛书及其图书数量
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (s *SeriesDAO) GetSeriesByPage(keyword string, page, pageSize int) ([]*SeriesSummary, int64, error) {
+	// Count会修改查询的SELECT子句，每次查询使用新的查询对象
+	query := func() *gorm.DB {
+		q := s.db.Model(&model.Series{})
+		if keyword != "" {
+			q = q.Where("name LIKE ?", "%"+keyword+"%")
+		}
+		return q
+	}
+
+	var total int64
+	// 对应SQL: SELECT COUNT(*) FROM series [WHERE name LIKE '%keyword%'];
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var series []*SeriesSummary
+	// 对应SQL: SELECT series.*, (SELECT COUNT(*) FROM books WHERE series_id = series.id) AS book_count
+	// FROM series [WHERE name LIKE '%keyword%'] ORDER BY name LIMIT pageSize OFFSET offset;
+	offset := (page - 1) * pageSize
+	err := query().Select("series.*, (SELECT COUNT(*) FROM books WHERE series_id = series.id) AS book_count").
+		Order("name").Offset(offset).Limit(pageSize).Scan(&series).Error
+	return series, total, err
+}
+
+// CreateSeries 创建丛书
+// 参数:
+//
+//	series - 丛书对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (s *SeriesDAO) CreateSeries(series *model.Series) error {
+	// 对应SQL: INSERT INTO series (name, description, ...) VALUES (...);
+	return s.db.Create(series).Error
+}
+
+// UpdateSeries 更新丛书
+// 参数:
+//
+//	series - 丛书对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (s *SeriesDAO) UpdateSeries(series *model.Series) error {
+	// 对应SQL: UPDATE series SET name = ?, description = ?, ... WHERE id = series.ID;
+	return s.db.Save(series).Error
+}
+
+// DeleteSeries 删除丛书，并将丛书中的图书移出丛书、清空卷号（需在事务中调用）
+// 参数:
+//
+//	id - 丛书ID
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (s *SeriesDAO) DeleteSeries(id int) error {
+	// 对应SQL: UPDATE books SET series_id = NULL, volume = 0 WHERE series_id = id;
+	err := s.db.Model(&model.Book{}).Where("series_id = ?", id).
+		UpdateColumns(map[string]any{"series_id": nil, "volume": 0}).Error
+	if err != nil {
+		return err
+	}
+	// 对应SQL: DELETE FROM series WHERE id = id;
+	return s.db.Delete(&model.Series{}, id).Error
+}
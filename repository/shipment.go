@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ShipmentDAO 发货记录数据访问对象
+// 封装了所有与发货记录和物流轨迹相关的数据库操作
+type ShipmentDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewShipmentDAO 创建新的发货记录DAO实例
+// 返回:
+//
+//	*ShipmentDAO - 初始化后的发货记录数据访问对象
+func NewShipmentDAO() *ShipmentDAO {
+	return &ShipmentDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WithTx 返回绑定到指定事务的发货记录DAO
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//
+// 返回:
+//
+//	*ShipmentDAO - 使用该事务执行所有操作的发货记录数据访问对象
+func (s *ShipmentDAO) WithTx(tx *gorm.DB) *ShipmentDAO {
+	return &ShipmentDAO{db: tx}
+}
+
+// CreateShipment 创建发货记录
+// 参数:
+//
+//	shipment - 发货记录对象指针，Events中的物流轨迹会一并写入
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (s *ShipmentDAO) CreateShipment(shipment *model.Shipment) error {
+	// 对应SQL:
+	// 1. INSERT INTO shipments (order_id, carrier, tracking_no, ...) VALUES (...);
+	// 2. INSERT INTO shipment_events (shipment_id, status, description, ...) VALUES (...);
+	return s.db.Create(shipment).Error
+}
+
+// GetShipmentByID 根据ID获取发货记录
+// 参数:
+//
+//	id - 发货记录ID
+//
+// 返回:
+//
+//	*model.Shipment - 发货记录对象指针（包含物流轨迹）
+//	error - 如果查询过程中出现错误则返回错误
+func (s *ShipmentDAO) GetShipmentByID(id int) (*model.Shipment, error) {
+	var shipment model.Shipment
+	// 对应SQL:
+	// 1. SELECT * FROM shipments WHERE id = id LIMIT 1;
+	// 2. SELECT * FROM shipment_events WHERE shipment_id = id ORDER BY occurred_at ASC, id ASC;
+	err := s.db.Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("occurred_at ASC, id ASC") }).
+		First(&shipment, id).Error
+	return &shipment, err
+}
+
+// GetShipmentForUpdate 根据ID获取发货记录并加行锁（需在事务中调用）
+// 参数:
+//
+//	id - 发货记录ID
+//
+// 返回:
+//
+//	*model.Shipment - 发货记录对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (s *ShipmentDAO) GetShipmentForUpdate(id int) (*model.Shipment, error) {
+	var shipment model.Shipment
+	// 对应SQL: SELECT * FROM shipments WHERE id = id LIMIT 1 FOR UPDATE;
+	err := s.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&shipment, id).Error
+	return &shipment, err
+}
+
+// GetOrderShipments 获取订单的全部发货记录
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	[]*model.Shipment - 发货记录列表（包含物流轨迹）
+//	error - 如果查询过程中出现错误则返回错误
+func (s *ShipmentDAO) GetOrderShipments(orderID int) ([]*model.Shipment, error) {
+	var shipments []*model.Shipment
+	// 对应SQL:
+	// 1. SELECT * FROM shipments WHERE order_id = orderID ORDER BY id ASC;
+	// 2. SELECT * FROM shipment_events WHERE shipment_id IN (...) ORDER BY occurred_at ASC, id ASC;
+	err := s.db.Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("occurred_at ASC, id ASC") }).
+		Where("order_id = ?", orderID).
+		Order("id ASC").
+		Find(&shipments).Error
+	return shipments, err
+}
+
+// CountUndeliveredShipments 统计订单中尚未签收的包裹数量
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	int64 - 未签收的包裹数量
+//	error - 如果查询过程中出现错误则返回错误
+func (s *ShipmentDAO) CountUndeliveredShipments(orderID int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM shipments WHERE order_id = orderID AND status <> 1;
+	err := s.db.Model(&model.Shipment{}).
+		Where("order_id = ? AND status <> ?", orderID, model.ShipmentStatusDelivered).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateShipment 更新发货记录（不包含物流轨迹）
+// 参数:
+//
+//	shipment - 发货记录对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (s *ShipmentDAO) UpdateShipment(shipment *model.Shipment) error {
+	// 对应SQL: UPDATE shipments SET carrier = ?, tracking_no = ?, status = ?, ... WHERE id = shipment.ID;
+	return s.db.Omit(clause.Associations).Save(shipment).Error
+}
+
+// CreateEvent 添加物流轨迹
+// 参数:
+//
+//	event - 物流轨迹对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (s *ShipmentDAO) CreateEvent(event *model.ShipmentEvent) error {
+	// 对应SQL: INSERT INTO shipment_events (shipment_id, status, location, description, occurred_at) VALUES (...);
+	return s.db.Create(event).Error
+}
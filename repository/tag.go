@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagDAO 标签数据访问对象
+// 封装了标签以及图书与标签关联的数据库操作
+type TagDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewTagDAO 创建新的标签DAO实例
+// 返回:
+//
+//	*TagDAO - 初始化后的标签数据访问对象
+func NewTagDAO() *TagDAO {
+	return &TagDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// TagSummary 标签列表项，附带图书数量
+type TagSummary struct {
+	model.Tag `gorm:"embedded"`
+	BookCount int64 `json:"book_count"` // 带有该标签的图书数量，后台列表含下架图书，前台列表只统计上架图书
+}
+
+// GetTagByID 根据ID获取标签
+// 参数:
+//
+//	id - 标签ID
+//
+// 返回:
+//
+//	*model.Tag - 标签对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (t *TagDAO) GetTagByID(id int) (*model.Tag, error) {
+	var tag model.Tag
+	// 对应SQL: SELECT * FROM tags WHERE id = id LIMIT 1;
+	err := t.db.First(&tag, id).Error
+	return &tag, err
+}
+
+// GetTagBySlug 根据别名获取标签
+// 参数:
+//
+//	slug - 标签别名
+//
+// 返回:
+//
+//	*model.Tag - 标签对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (t *TagDAO) GetTagBySlug(slug string) (*model.Tag, error) {
+	var tag model.Tag
+	// 对应SQL: SELECT * FROM tags WHERE slug = slug LIMIT 1;
+	err := t.db.Where("slug = ?", slug).First(&tag).Error
+	return &tag, err
+}
+
+// CountTagsByIDs 统计指定ID中存在的标签数量
+// 参数:
+//
+//	ids - 标签ID列表
+//
+// 返回:
+//
+//	int64 - 存在的标签数量
+//	error - 如果查询过程中出现错误则返回错误
+func (t *TagDAO) CountTagsByIDs(ids []int) (int64, error) {
+	var count int64
+	// 对应SQL: SELECT COUNT(*) FROM tags WHERE id IN (ids);
+	err := t.db.Model(&model.Tag{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
+
+// GetTagsByPage 分页获取标签列表，按名称或别名模糊搜索
+// 参数:
+//
+//	keyword - 名称或别名关键词，为空时不过滤
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*TagSummary - 当前页的标签及其图书数量（含下架图书）
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (t *TagDAO) GetTagsByPage(keyword string, page, pageSize int) ([]*TagSummary, int64, error) {
+	// Count会修改查询的SELECT子句，每次查询使用新的查询对象
+	query := func() *gorm.DB {
+		q := t.db.Model(&model.Tag{})
+		if keyword != "" {
+			q = q.Where("name LIKE ? OR slug LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+		}
+		return q
+	}
+
+	var total int64
+	// 对应SQL: SELECT COUNT(*) FROM tags [WHERE name LIKE '%keyword%' OR slug LIKE '%keyword%'];
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tags []*TagSummary
+	// 对应SQL: SELECT tags.*, (SELECT COUNT(*) FROM book_tags WHERE tag_id = tags.id) AS book_count
+	// FROM tags [WHERE ...] ORDER BY name LIMIT pageSize OFFSET offset;
+	offset := (page - 1) * pageSize
+	err := query().Select("tags.*, (SELECT COUNT(*) FROM book_tags WHERE tag_id = tags.id) AS book_count").
+		Order("name").Offset(offset).Limit(pageSize).Scan(&tags).Error
+	return tags, total, err
+}
+
+// GetTagsWithBookCount 获取带有上架图书的全部标签，供前台按标签浏览
+// 返回:
+//
+//	[]*TagSummary - 标签及其上架图书数量，按图书数量从多到少排列
+//	error - 如果查询过程中出现错误则返回错误
+func (t *TagDAO) GetTagsWithBookCount() ([]*TagSummary, error) {
+	var tags []*TagSummary
+	// 对应SQL: SELECT tags.*, COUNT(*) AS book_count FROM tags JOIN book_tags ON book_tags.tag_id = tags.id
+	// JOIN books ON books.id = book_tags.book_id WHERE books.status = 1 GROUP BY tags.id ORDER BY book_count DESC, tags.name;
+	err := t.db.Model(&model.Tag{}).
+		Select("tags.*, COUNT(*) AS book_count").
+		Joins("JOIN book_tags ON book_tags.tag_id = tags.id").
+		Joins("JOIN books ON books.id = book_tags.book_id").
+		Where("books.status = ?", 1).
+		Group("tags.id").
+		Order("book_count DESC, tags.name").
+		Scan(&tags).Error
+	return tags, err
+}
+
+// GetBookTags 获取图书的全部标签
+// 参数:
+//
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	[]*model.Tag - 标签对象切片，按名称排列
+//	error - 如果查询过程中出现错误则返回错误
+func (t *TagDAO) GetBookTags(bookID int) ([]*model.Tag, error) {
+	var tags []*model.Tag
+	// 对应SQL: SELECT tags.* FROM tags JOIN book_tags ON book_tags.tag_id = tags.id WHERE book_tags.book_id = bookID ORDER BY tags.name;
+	err := t.db.Joins("JOIN book_tags ON book_tags.tag_id = tags.id").
+		Where("book_tags.book_id = ?", bookID).Order("tags.name").Find(&tags).Error
+	return tags, err
+}
+
+// CreateTag 创建标签
+// 参数:
+//
+//	tag - 标签对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (t *TagDAO) CreateTag(tag *model.Tag) error {
+	// 对应SQL: INSERT INTO tags (name, slug, description, ...) VALUES (...);
+	return t.db.Create(tag).Error
+}
+
+// UpdateTag 更新标签
+// 参数:
+//
+//	tag - 标签对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (t *TagDAO) UpdateTag(tag *model.Tag) error {
+	// 对应SQL: UPDATE tags SET name = ?, slug = ?, description = ?, ... WHERE id = tag.ID;
+	return t.db.Save(tag).Error
+}
+
+// DeleteTag 删除标签，图书与该标签的关联由外键级联删除
+// 参数:
+//
+//	id - 标签ID
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (t *TagDAO) DeleteTag(id int) error {
+	// 对应SQL: DELETE FROM tags WHERE id = id;
+	return t.db.Delete(&model.Tag{}, id).Error
+}
+
+// AddBookTags 为多本图书添加多个标签，已有的关联保持不变
+// 参数:
+//
+//	bookIDs - 图书ID列表
+//	tagIDs - 标签ID列表
+//
+// 返回:
+//
+//	int64 - 新增的关联数量
+//	error - 如果写入过程中出现错误则返回错误
+func (t *TagDAO) AddBookTags(bookIDs, tagIDs []int) (int64, error) {
+	links := make([]model.BookTag, 0, len(bookIDs)*len(tagIDs))
+	for _, bookID := range bookIDs {
+		for _, tagID := range tagIDs {
+			links = append(links, model.BookTag{BookID: bookID, TagID: tagID})
+		}
+	}
+	// 对应SQL: INSERT INTO book_tags (book_id, tag_id) VALUES (...), (...) ON DUPLICATE KEY UPDATE book_id = book_id;
+	result := t.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&links)
+	return result.RowsAffected, result.Error
+}
+
+// RemoveBookTags 移除多本图书的多个标签
+// 参数:
+//
+//	bookIDs - 图书ID列表
+//	tagIDs - 标签ID列表
+//
+// 返回:
+//
+//	int64 - 移除的关联数量
+//	error - 如果删除过程中出现错误则返回错误
+func (t *TagDAO) RemoveBookTags(bookIDs, tagIDs []int) (int64, error) {
+	// 对应SQL: DELETE FROM book_tags WHERE book_id IN (bookIDs) AND tag_id IN (tagIDs);
+	result := t.db.Where("book_id IN ? AND tag_id IN ?", bookIDs, tagIDs).Delete(&model.BookTag{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+
+	"gorm.io/gorm"
+)
+
+// WorkDAO 作品数据访问对象
+// 封装了作品的数据库操作，作品下的版本通过books.work_id关联
+type WorkDAO struct {
+	db *gorm.DB // GORM数据库连接实例
+}
+
+// NewWorkDAO 创建新的作品DAO实例
+// 返回:
+//
+//	*WorkDAO - 初始化后的作品数据访问对象
+func NewWorkDAO() *WorkDAO {
+	return &WorkDAO{
+		db: global.GetDB(), // 从全局变量获取数据库连接
+	}
+}
+
+// WorkSummary 作品列表项，附带版本数量
+type WorkSummary struct {
+	model.Work   `gorm:"embedded"`
+	EditionCount int64 `json:"edition_count"` // 归入该作品的图书数量（含下架图书）
+}
+
+// GetWorkByID 根据ID获取作品
+// 参数:
+//
+//	id - 作品ID
+//
+// 返回:
+//
+//	*model.Work - 作品对象指针
+//	error - 如果查询过程中出现错误则返回错误
+func (w *WorkDAO) GetWorkByID(id int) (*model.Work, error) {
+	var work model.Work
+	// 对应SQL: SELECT * FROM works WHERE id = id LIMIT 1;
+	err := w.db.First(&work, id).Error
+	return &work, err
+}
+
+// GetWorksByPage 分页获取作品列表，按名称模糊搜索
+// 参数:
+//
+//	keyword - 名称关键词，为空时不过滤
+//	page - 页码，从1开始
+//	pageSize - 每页记录数
+//
+// 返回:
+//
+//	[]*WorkSummary - 当前页的作品及其版本数量
+//	int64 - 符合条件的总记录数
+//	error - 如果查询过程中出现错误则返回错误
+func (w *WorkDAO) GetWorksByPage(keyword string, page, pageSize int) ([]*WorkSummary, int64, error) {
+	// Count会修改查询的SELECT子句，每次查询使用新的查询对象
+	query := func() *gorm.DB {
+		q := w.db.Model(&model.Work{})
+		if keyword != "" {
+			q = q.Where("title LIKE ?", "%"+keyword+"%")
+		}
+		return q
+	}
+
+	var total int64
+	// 对应SQL: SELECT COUNT(*) FROM works [WHERE title LIKE '%keyword%'];
+	if err := query().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var works []*WorkSummary
+	// 对应SQL: SELECT works.*, (SELECT COUNT(*) FROM books WHERE work_id = works.id) AS edition_count
+	// FROM works [WHERE title LIKE '%keyword%'] ORDER BY id DESC LIMIT pageSize OFFSET offset;
+	offset := (page - 1) * pageSize
+	err := query().Select("works.*, (SELECT COUNT(*) FROM books WHERE work_id = works.id) AS edition_count").
+		Order("id DESC").Offset(offset).Limit(pageSize).Scan(&works).Error
+	return works, total, err
+}
+
+// CreateWork 创建作品
+// 参数:
+//
+//	work - 作品对象指针
+//
+// 返回:
+//
+//	error - 如果创建过程中出现错误则返回错误
+func (w *WorkDAO) CreateWork(work *model.Work) error {
+	// 对应SQL: INSERT INTO works (title, description, ...) VALUES (...);
+	return w.db.Create(work).Error
+}
+
+// UpdateWork 更新作品
+// 参数:
+//
+//	work - 作品对象指针
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (w *WorkDAO) UpdateWork(work *model.Work) error {
+	// 对应SQL: UPDATE works SET title = ?, description = ?, ... WHERE id = work.ID;
+	return w.db.Save(work).Error
+}
+
+// DeleteWork 删除作品
+// 作品下的图书由外键置空work_id，图书本身保留
+// 参数:
+//
+//	id - 作品ID
+//
+// 返回:
+//
+//	error - 如果删除过程中出现错误则返回错误
+func (w *WorkDAO) DeleteWork(id int) error {
+	// 对应SQL: DELETE FROM works WHERE id = id;
+	return w.db.Delete(&model.Work{}, id).Error
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// maxAddressesPerUser 每个用户最多可保存的收货地址数量
+const maxAddressesPerUser = 20
+
+// AddressService 收货地址服务
+// 负责用户地址簿的增删改查以及默认地址的维护
+type AddressService struct {
+	AddressDAO *repository.AddressDAO // 收货地址数据访问对象
+}
+
+// AddressRequest 创建或更新收货地址请求
+type AddressRequest struct {
+	ReceiverName string `json:"receiver_name" binding:"required,max=50"` // 收货人姓名（必填）
+	Phone        string `json:"phone" binding:"required,max=20"`         // 收货人手机号（必填）
+	Province     string `json:"province" binding:"required,max=50"`      // 省份（必填）
+	City         string `json:"city" binding:"required,max=50"`          // 城市（必填）
+	District     string `json:"district" binding:"max=50"`               // 区县
+	Detail       string `json:"detail" binding:"required,max=255"`       // 详细地址（必填）
+	PostalCode   string `json:"postal_code" binding:"max=20"`            // 邮政编码
+	IsDefault    bool   `json:"is_default"`                              // 是否设为默认地址
+}
+
+// addressInfo 将请求转换为收货地址信息
+func (r *AddressRequest) addressInfo() model.AddressInfo {
+	return model.AddressInfo{
+		ReceiverName: r.ReceiverName,
+		Phone:        r.Phone,
+		Province:     r.Province,
+		City:         r.City,
+		District:     r.District,
+		Detail:       r.Detail,
+		PostalCode:   r.PostalCode,
+	}
+}
+
+// NewAddressService 创建新的收货地址服务实例
+// 返回:
+//
+//	*AddressService - 初始化好的收货地址服务
+func NewAddressService() *AddressService {
+	return &AddressService{
+		AddressDAO: repository.NewAddressDAO(),
+	}
+}
+
+// GetUserAddresses 获取用户的地址簿
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	[]*model.Address - 收货地址列表，默认地址排在最前
+//	error - 错误信息
+func (a *AddressService) GetUserAddresses(userID int) ([]*model.Address, error) {
+	return a.AddressDAO.GetUserAddresses(userID)
+}
+
+// GetUserAddress 获取用户的指定收货地址
+// 参数:
+//
+//	userID - 用户ID
+//	id - 地址ID
+//
+// 返回:
+//
+//	*model.Address - 收货地址
+//	error - 错误信息
+func (a *AddressService) GetUserAddress(userID, id int) (*model.Address, error) {
+	address, err := a.AddressDAO.GetUserAddress(userID, id)
+	if err != nil {
+		return nil, errors.New("收货地址不存在")
+	}
+	return address, nil
+}
+
+// CreateAddress 添加收货地址
+// 用户的第一个地址自动成为默认地址；设为默认时取消其他地址的默认标记
+// 参数:
+//
+//	userID - 用户ID
+//	req - 收货地址请求
+//
+// 返回:
+//
+//	*model.Address - 创建的收货地址
+//	error - 错误信息
+func (a *AddressService) CreateAddress(userID int, req *AddressRequest) (*model.Address, error) {
+	address := &model.Address{
+		UserID:      userID,
+		AddressInfo: req.addressInfo(),
+		IsDefault:   req.IsDefault,
+	}
+
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		addressDAO := a.AddressDAO.WithTx(tx)
+
+		count, err := addressDAO.CountUserAddresses(userID)
+		if err != nil {
+			return err
+		}
+		if count >= maxAddressesPerUser {
+			return errors.New("收货地址数量已达上限")
+		}
+		if count == 0 {
+			address.IsDefault = true
+		}
+
+		if address.IsDefault {
+			if err := addressDAO.ClearDefault(userID); err != nil {
+				return err
+			}
+		}
+		return addressDAO.CreateAddress(address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// UpdateAddress 更新收货地址
+// 默认地址不能直接取消默认标记，需要将其他地址设为默认
+// 参数:
+//
+//	userID - 用户ID
+//	id - 地址ID
+//	req - 收货地址请求
+//
+// 返回:
+//
+//	*model.Address - 更新后的收货地址
+//	error - 错误信息
+func (a *AddressService) UpdateAddress(userID, id int, req *AddressRequest) (*model.Address, error) {
+	var address *model.Address
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		addressDAO := a.AddressDAO.WithTx(tx)
+
+		var err error
+		address, err = addressDAO.GetUserAddress(userID, id)
+		if err != nil {
+			return errors.New("收货地址不存在")
+		}
+
+		if req.IsDefault && !address.IsDefault {
+			if err := addressDAO.ClearDefault(userID); err != nil {
+				return err
+			}
+			address.IsDefault = true
+		}
+		address.AddressInfo = req.addressInfo()
+		return addressDAO.UpdateAddress(address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// SetDefaultAddress 设置默认收货地址
+// 参数:
+//
+//	userID - 用户ID
+//	id - 地址ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (a *AddressService) SetDefaultAddress(userID, id int) error {
+	return global.DBClient.Transaction(func(tx *gorm.DB) error {
+		addressDAO := a.AddressDAO.WithTx(tx)
+
+		address, err := addressDAO.GetUserAddress(userID, id)
+		if err != nil {
+			return errors.New("收货地址不存在")
+		}
+		if address.IsDefault {
+			return nil
+		}
+
+		if err := addressDAO.ClearDefault(userID); err != nil {
+			return err
+		}
+		address.IsDefault = true
+		return addressDAO.UpdateAddress(address)
+	})
+}
+
+// DeleteAddress 删除收货地址
+// 删除默认地址时，将最近添加的地址设为新的默认地址
+// 参数:
+//
+//	userID - 用户ID
+//	id - 地址ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (a *AddressService) DeleteAddress(userID, id int) error {
+	return global.DBClient.Transaction(func(tx *gorm.DB) error {
+		addressDAO := a.AddressDAO.WithTx(tx)
+
+		address, err := addressDAO.GetUserAddress(userID, id)
+		if err != nil {
+			return errors.New("收货地址不存在")
+		}
+		if err := addressDAO.DeleteAddress(userID, id); err != nil {
+			return err
+		}
+		if !address.IsDefault {
+			return nil
+		}
+
+		latest, err := addressDAO.GetLatestAddress(userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		latest.IsDefault = true
+		return addressDAO.UpdateAddress(latest)
+	})
+}
+
+// resolveOrderAddress 获取下单使用的收货地址
+// 参数:
+//
+//	userID - 用户ID
+//	addressID - 地址ID，0表示使用默认地址
+//
+// 返回:
+//
+//	*model.Address - 收货地址
+//	error - 地址不存在或用户没有任何地址时返回错误
+func (a *AddressService) resolveOrderAddress(userID, addressID int) (*model.Address, error) {
+	if addressID != 0 {
+		return a.GetUserAddress(userID, addressID)
+	}
+
+	address, err := a.AddressDAO.GetDefaultAddress(userID)
+	if err != nil {
+		return nil, errors.New("请选择收货地址")
+	}
+	return address, nil
+}
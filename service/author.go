@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAuthorNotFound      = errors.New("作者不存在")
+	ErrMergeTargetInSource = errors.New("被合并的ID中不能包含保留的ID")
+)
+
+// AuthorService 作者服务
+// 负责作者详情、作者列表和合并重复作者
+type AuthorService struct {
+	AuthorDAO *repository.AuthorDAO // 作者数据访问对象
+	BookDAO   *repository.BookDAO   // 书籍数据访问对象
+}
+
+// NewAuthorService 创建新的作者服务实例
+// 返回:
+//
+//	*AuthorService - 初始化好的作者服务
+func NewAuthorService() *AuthorService {
+	return &AuthorService{
+		AuthorDAO: repository.NewAuthorDAO(),
+		BookDAO:   repository.NewBookDAO(),
+	}
+}
+
+// AuthorBook 作者页中的图书，附带该作者在书中的署名角色
+type AuthorBook struct {
+	*model.Book
+	Roles []string `json:"roles"` // 署名角色：author、translator、editor
+}
+
+// AuthorDetail 作者详情
+type AuthorDetail struct {
+	Author *model.Author `json:"author"` // 作者信息
+	Books  []*AuthorBook `json:"books"`  // 当前页的上架图书
+	Total  int64         `json:"total"`  // 上架图书总数
+}
+
+// GetAuthorDetail 获取作者信息及其分页的上架图书
+// 参数:
+//
+//	id - 作者ID
+//	req - 分页、排序和筛选条件
+//
+// 返回:
+//
+//	*AuthorDetail - 作者详情
+//	error - 作者不存在时返回ErrAuthorNotFound
+func (a *AuthorService) GetAuthorDetail(id int, req *model.BookQueryRequest) (*AuthorDetail, error) {
+	author, err := a.AuthorDAO.GetAuthorByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuthorNotFound
+		}
+		return nil, err
+	}
+
+	filter := bookFilterFromRequest(req)
+	filter.AuthorID = id
+	books, total, err := a.BookDAO.GetBooksByFilter(filter, req.Sort, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	bookIDs := make([]int, 0, len(books))
+	for _, book := range books {
+		bookIDs = append(bookIDs, book.ID)
+	}
+	roles, err := a.AuthorDAO.GetAuthorRoles(id, bookIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &AuthorDetail{Author: author, Books: make([]*AuthorBook, 0, len(books)), Total: total}
+	for _, book := range books {
+		detail.Books = append(detail.Books, &AuthorBook{Book: book, Roles: roles[book.ID]})
+	}
+	return detail, nil
+}
+
+// GetAuthors 分页获取作者列表，供后台查找重复作者
+// 参数:
+//
+//	keyword - 姓名关键词
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*repository.AuthorSummary - 作者及其图书数量
+//	int64 - 作者总数
+//	error - 错误信息
+func (a *AuthorService) GetAuthors(keyword string, page, pageSize int) ([]*repository.AuthorSummary, int64, error) {
+	return a.AuthorDAO.GetAuthorsByPage(keyword, page, pageSize)
+}
+
+// MergeAuthors 将重复的作者合并到目标作者
+// 被合并作者的图书改为关联目标作者，其姓名作为目标作者的别名保留，之后按该姓名录入的图书会直接关联目标作者；
+// 图书上的署名文字保持不变
+// 参数:
+//
+//	req - 合并请求
+//
+// 返回:
+//
+//	*model.Author - 合并后的目标作者
+//	error - 作者不存在或目标作者在被合并列表中时返回错误
+func (a *AuthorService) MergeAuthors(req *model.MergeRequest) (*model.Author, error) {
+	if slices.Contains(req.SourceIDs, req.TargetID) {
+		return nil, ErrMergeTargetInSource
+	}
+	ids := append([]int{req.TargetID}, req.SourceIDs...)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var target *model.Author
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		authorDAO := a.AuthorDAO.WithTx(tx)
+
+		locked, err := authorDAO.GetAuthorsForUpdate(ids)
+		if err != nil {
+			return err
+		}
+		if len(locked) != len(ids) {
+			return ErrAuthorNotFound
+		}
+		for _, author := range locked {
+			if author.ID == req.TargetID {
+				target = author
+			}
+		}
+		return authorDAO.MergeAuthors(req.TargetID, req.SourceIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
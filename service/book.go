@@ -1,15 +1,32 @@
 package service
 
 import (
+	"bookstore/global"
 	"bookstore/model"
 	"bookstore/repository"
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
 )
 
 // BookService 书籍服务
 // 封装了所有与书籍相关的业务逻辑操作
 type BookService struct {
-	BookDB     *repository.BookDAO     // 书籍数据访问对象
-	CategoryDB *repository.CategoryDAO // 分类数据访问对象
+	BookDB       *repository.BookDAO     // 书籍数据访问对象
+	CategoryDB   *repository.CategoryDAO // 分类数据访问对象
+	WorkDB       *repository.WorkDAO     // 作品数据访问对象
+	SeriesDB     *repository.SeriesDAO   // 丛书数据访问对象
+	TagDB        *repository.TagDAO      // 标签数据访问对象
+	SearchEngine SearchEngine            // 全文搜索引擎
+	SuggestIndex *SuggestIndex           // 搜索建议索引
+
+	ContributorService *ContributorService // 图书署名服务，维护图书与作者、出版社的关联
 }
 
 // NewBookService 创建新的书籍服务实例
@@ -18,8 +35,15 @@ type BookService struct {
 //	*BookService - 初始化后的书籍服务对象
 func NewBookService() *BookService {
 	return &BookService{
-		BookDB:     repository.NewBookDAO(),
-		CategoryDB: repository.NewCategoryDAO(),
+		BookDB:       repository.NewBookDAO(),
+		CategoryDB:   repository.NewCategoryDAO(),
+		WorkDB:       repository.NewWorkDAO(),
+		SeriesDB:     repository.NewSeriesDAO(),
+		TagDB:        repository.NewTagDAO(),
+		SearchEngine: getSearchEngine(),
+		SuggestIndex: getSuggestIndex(),
+
+		ContributorService: NewContributorService(),
 	}
 }
 
@@ -99,6 +123,65 @@ func (b *BookService) GetBookByIDForAdmin(id int) (*model.Book, error) {
 	return b.BookDB.GetBookByIDForAdmin(id)
 }
 
+// BookDetail 图书详情，附带可链接到作者页的署名信息、标签，以及同一作品的其他版本和丛书中的前后卷
+type BookDetail struct {
+	*model.Book
+	Contributors []model.BookContributor `json:"contributors"`     // 按署名顺序排列的作者、译者和编者
+	Tags         []*model.Tag            `json:"tags"`             // 图书的标签，按名称排列
+	Editions     []*model.Book           `json:"editions"`         // 同一作品的其他上架版本
+	Series       *SeriesNeighbours       `json:"series,omitempty"` // 所属丛书及前后卷，不属于丛书时省略
+}
+
+// GetBookDetail 获取上架图书的详情
+// 参数:
+//
+//	id - 书籍ID
+//
+// 返回:
+//
+//	*BookDetail - 图书详情
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookService) GetBookDetail(id int) (*BookDetail, error) {
+	book, err := b.BookDB.GetBookByID(id)
+	if err != nil {
+		return nil, err
+	}
+	contributors, err := b.ContributorService.GetBookContributors(id)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := b.TagDB.GetBookTags(id)
+	if err != nil {
+		return nil, err
+	}
+	detail := &BookDetail{Book: book, Contributors: contributors, Tags: tags, Editions: []*model.Book{}}
+
+	if book.WorkID != nil {
+		if detail.Editions, err = b.BookDB.GetEditions(*book.WorkID, book.ID); err != nil {
+			return nil, err
+		}
+	}
+	if book.SeriesID != nil {
+		series, err := b.SeriesDB.GetSeriesByID(*book.SeriesID)
+		if err != nil {
+			return nil, err
+		}
+		books, err := b.BookDB.GetSeriesBooks(series.ID)
+		if err != nil {
+			return nil, err
+		}
+		previous, next := findSeriesNeighbours(books, book.Volume)
+		detail.Series = &SeriesNeighbours{
+			Series:   series,
+			Volume:   book.Volume,
+			Total:    len(books),
+			Previous: previous,
+			Next:     next,
+		}
+	}
+	return detail, nil
+}
+
 // GetBooksByType 根据类型获取书籍
 // 参数:
 //
@@ -112,36 +195,290 @@ func (b *BookService) GetBooksByType(bookType string) ([]*model.Book, error) {
 	return b.BookDB.GetBooksByType(bookType)
 }
 
-// SearchBooks 搜索书籍
+// GetBooksByCategory 获取分类及其全部子孙分类下的上架图书
+// 分类可以用ID或名称指定，找不到对应分类时按图书类型查询，兼容按类型名称访问的旧链接
 // 参数:
 //
-//	keyword - 搜索关键词
+//	category - 分类ID或分类名称
 //
 // 返回:
 //
 //	[]*model.Book - 书籍对象切片
 //	error - 如果查询过程中出现错误则返回错误
-func (b *BookService) SearchBooks(keyword string) ([]*model.Book, error) {
-	return b.BookDB.SearchBooks(keyword)
+func (b *BookService) GetBooksByCategory(category string) ([]*model.Book, error) {
+	var found *model.Category
+	var err error
+	if id, convErr := strconv.Atoi(category); convErr == nil {
+		found, err = b.CategoryDB.GetCategoryByID(id)
+	} else {
+		found, err = b.CategoryDB.GetCategoryByName(category)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return b.BookDB.GetBooksByType(category)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b.BookDB.GetBooksByCategory(found.ID)
 }
 
-// SearchBooksWithPagination 分页搜索书籍
+const (
+	// bookPublisherFacetLimit 出版社筛选项最多返回的数量
+	bookPublisherFacetLimit = 20
+	// searchMaxHits 全文搜索参与筛选和排序的最大命中数，超出部分按相关度截断
+	searchMaxHits = 1000
+)
+
+// bookPriceBounds 价格区间筛选项的边界（元），划分出0-20、20-50、50-100、100-200和200以上五个区间
+var bookPriceBounds = []int{20, 50, 100, 200}
+
+// SuggestBooks 根据用户已输入的内容返回标题、作者和分类建议
 // 参数:
 //
-//	keyword - 搜索关键词
-//	page - 页码
-//	pageSize - 每页大小
+//	prefix - 用户已输入的内容
+//	limit - 返回的最大数量
 //
 // 返回:
 //
-//	[]*model.Book - 书籍对象切片
+//	[]Suggestion - 按销量和搜索热度排序的搜索建议
+func (b *BookService) SuggestBooks(prefix string, limit int) []Suggestion {
+	return b.SuggestIndex.Suggest(prefix, limit)
+}
+
+// GetBooksByFilter 按筛选条件和排序方式分页获取上架图书，并统计各筛选项的数量
+// 参数:
+//
+//	req - 图书列表请求，不使用其中的关键词
+//
+// 返回:
+//
+//	[]*model.Book - 当前页的书籍对象切片
+//	int64 - 符合条件的总记录数
+//	*model.BookFacets - 筛选项统计
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookService) GetBooksByFilter(req *model.BookQueryRequest) ([]*model.Book, int64, *model.BookFacets, error) {
+	filter := bookFilterFromRequest(req)
+	books, total, err := b.BookDB.GetBooksByFilter(filter, req.Sort, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	facets, err := b.getBookFacets(filter)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return books, total, facets, nil
+}
+
+// SearchBooksWithPagination 分页全文搜索上架图书，支持与图书列表相同的筛选和排序
+// 默认按相关度降序排列，结果附带标题、作者、出版社和描述中命中词的高亮片段
+// 参数:
+//
+//	req - 搜索请求，关键词不能为空
+//
+// 返回:
+//
+//	[]*BookSearchHit - 带相关度和高亮片段的图书搜索结果
 //	int64 - 搜索结果总数
+//	*model.BookFacets - 搜索结果的筛选项统计
+//	bool - 命中数是否超过searchMaxHits，为true时总数、筛选项统计和非相关度排序只基于相关度最高的部分结果
+//	error - 如果查询过程中出现错误则返回错误
+func (b *BookService) SearchBooksWithPagination(req *model.BookQueryRequest) ([]*BookSearchHit, int64, *model.BookFacets, bool, error) {
+	result, err := b.SearchEngine.Search(&SearchQuery{
+		Keyword: req.Keyword,
+		Limit:   searchMaxHits,
+	})
+	if err != nil {
+		return nil, 0, nil, false, err
+	}
+	truncated := result.Total > int64(len(result.Hits))
+
+	// 在命中的图书中按筛选条件过滤，并按相关度或指定方式排序
+	filter := bookFilterFromRequest(req)
+	filter.IDs = make([]int, 0, len(result.Hits))
+	hitMap := make(map[int]SearchHit, len(result.Hits))
+	for _, hit := range result.Hits {
+		filter.IDs = append(filter.IDs, hit.BookID)
+		hitMap[hit.BookID] = hit
+	}
+
+	sort := req.Sort
+	if sort == "" {
+		sort = model.BookSortRelevance
+	}
+	books, total, err := b.BookDB.GetBooksByFilter(filter, sort, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, nil, false, err
+	}
+
+	hits := make([]*BookSearchHit, 0, len(books))
+	for _, book := range books {
+		hits = append(hits, newBookSearchHit(book, hitMap[book.ID]))
+	}
+
+	// 只在首页且有结果时记录搜索热度，翻页和无结果的搜索不计入
+	if req.Page == 1 && total > 0 {
+		b.SuggestIndex.RecordSearch(req.Keyword)
+	}
+
+	facets, err := b.getBookFacets(filter)
+	if err != nil {
+		return nil, 0, nil, false, err
+	}
+	return hits, total, facets, truncated, nil
+}
+
+// bookFilterFromRequest 将列表请求转换为数据访问层的筛选条件
+// 参数:
+//
+//	req - 图书列表请求
+//
+// 返回:
+//
+//	*repository.BookFilter - 筛选条件
+func bookFilterFromRequest(req *model.BookQueryRequest) *repository.BookFilter {
+	return &repository.BookFilter{
+		CategoryID:  req.CategoryID,
+		AuthorID:    req.AuthorID,
+		PublisherID: req.PublisherID,
+		Tags:        req.Tags,
+		Type:        req.Type,
+		Language:    req.Language,
+		Format:      req.Format,
+		Publisher:   req.Publisher,
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		InStock:     req.InStock,
+	}
+}
+
+// getBookFacets 统计各筛选项的图书数量
+// 每个维度按除该维度外的其他条件统计，选中某个分类后仍能看到其他分类的数量
+// 参数:
+//
+//	filter - 当前的筛选条件
+//
+// 返回:
+//
+//	*model.BookFacets - 筛选项统计
 //	error - 如果查询过程中出现错误则返回错误
-func (b *BookService) SearchBooksWithPagination(keyword string, page, pageSize int) ([]*model.Book, int64, error) {
-	return b.BookDB.SearchBooksWithPagination(keyword, page, pageSize)
+func (b *BookService) getBookFacets(filter *repository.BookFilter) (*model.BookFacets, error) {
+	facets := &model.BookFacets{}
+
+	// 字段类筛选项，without清除该维度自身的条件
+	fields := []struct {
+		column  string
+		limit   int
+		without func(f *repository.BookFilter)
+		target  *[]model.FacetCount
+	}{
+		{"category_id", 0, func(f *repository.BookFilter) { f.CategoryID = 0 }, &facets.Categories},
+		{"type", 0, func(f *repository.BookFilter) { f.Type = "" }, &facets.Types},
+		{"language", 0, func(f *repository.BookFilter) { f.Language = "" }, &facets.Languages},
+		{"format", 0, func(f *repository.BookFilter) { f.Format = "" }, &facets.Formats},
+		{"publisher", bookPublisherFacetLimit, func(f *repository.BookFilter) { f.Publisher = "" }, &facets.Publishers},
+	}
+	for _, field := range fields {
+		f := *filter
+		field.without(&f)
+		counts, err := b.BookDB.CountBooksByField(&f, field.column, field.limit)
+		if err != nil {
+			return nil, err
+		}
+		*field.target = make([]model.FacetCount, 0, len(counts))
+		for _, count := range counts {
+			*field.target = append(*field.target, model.FacetCount{Value: count.Value, Count: count.Count})
+		}
+	}
+
+	// 分类筛选项的数量累加到各级父分类，并补充分类名称
+	if len(facets.Categories) > 0 {
+		categories, err := b.CategoryDB.GetAllCategories()
+		if err != nil {
+			return nil, err
+		}
+		facets.Categories = rollUpCategoryFacets(facets.Categories, categories)
+	}
+
+	priceFilter := *filter
+	priceFilter.MinPrice, priceFilter.MaxPrice = nil, nil
+	priceCounts, err := b.BookDB.CountBooksByPriceRange(&priceFilter, bookPriceBounds)
+	if err != nil {
+		return nil, err
+	}
+	facets.PriceRanges = make([]model.PriceRangeFacet, 0, len(priceCounts))
+	for i, count := range priceCounts {
+		priceRange := model.PriceRangeFacet{Count: count}
+		if i > 0 {
+			priceRange.Min = bookPriceBounds[i-1]
+		}
+		if i < len(bookPriceBounds) {
+			upper := bookPriceBounds[i]
+			priceRange.Max = &upper
+		}
+		facets.PriceRanges = append(facets.PriceRanges, priceRange)
+	}
+
+	stockFilter := *filter
+	stockFilter.InStock = true
+	if facets.InStock, err = b.BookDB.CountBooksByFilter(&stockFilter); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// rollUpCategoryFacets 将直属分类的图书数量累加到其所有祖先分类
+// 按分类筛选时包含子孙分类的图书，父分类的数量因此与筛选结果一致
+// 参数:
+//
+//	counts - 按图书直属分类统计的数量
+//	categories - 全部分类
+//
+// 返回:
+//
+//	[]model.FacetCount - 各级分类的数量，按数量降序、分类ID升序排列
+func rollUpCategoryFacets(counts []model.FacetCount, categories []*model.Category) []model.FacetCount {
+	byID := make(map[string]*model.Category, len(categories))
+	for _, category := range categories {
+		byID[strconv.Itoa(category.ID)] = category
+	}
+
+	totals := make(map[string]int64)
+	for _, count := range counts {
+		category, ok := byID[count.Value]
+		if !ok {
+			// 分类已不存在时保留原始统计
+			totals[count.Value] += count.Count
+			continue
+		}
+		for _, id := range strings.Split(strings.Trim(category.Path, "/"), "/") {
+			if id != "" {
+				totals[id] += count.Count
+			}
+		}
+	}
+
+	result := make([]model.FacetCount, 0, len(totals))
+	for value, count := range totals {
+		facet := model.FacetCount{Value: value, Count: count}
+		if category, ok := byID[value]; ok {
+			facet.Label = category.Name
+		}
+		result = append(result, facet)
+	}
+	slices.SortFunc(result, func(a, b model.FacetCount) int {
+		if a.Count != b.Count {
+			return cmp.Compare(b.Count, a.Count)
+		}
+		x, _ := strconv.Atoi(a.Value)
+		y, _ := strconv.Atoi(b.Value)
+		return x - y
+	})
+	return result
 }
 
 // CreateBook 创建书籍
+// 在同一事务中更新所属分类的图书数量
 // 参数:
 //
 //	book - 书籍对象指针
@@ -150,22 +487,81 @@ func (b *BookService) SearchBooksWithPagination(keyword string, page, pageSize i
 //
 //	error - 如果创建过程中出现错误则返回错误
 func (b *BookService) CreateBook(book *model.Book) error {
-	return b.BookDB.CreateBook(book)
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		// 先更新分类数量再写入图书，分类行的排他锁先于外键检查获得，避免并发创建时死锁
+		if err := b.adjustCategoryBookCounts(tx, book, 1); err != nil {
+			return err
+		}
+		if err := b.BookDB.WithTx(tx).CreateBook(book); err != nil {
+			return err
+		}
+		return b.ContributorService.LinkBook(tx, book, true, true)
+	})
+	if err != nil {
+		return err
+	}
+	b.syncBookIndexes(book)
+	return nil
 }
 
 // UpdateBook 更新书籍
+// 在事务中锁定图书行后再应用修改，库存校验基于加锁后的预留数量，避免覆盖订单流程并发写入的库存；
+// 分类或上下架状态变化时，在同一事务中更新新旧分类的图书数量
 // 参数:
 //
-//	book - 书籍对象指针
+//	id - 书籍ID
+//	apply - 对加锁后读取的图书应用修改，返回错误时放弃更新
 //
 // 返回:
 //
 //	error - 如果更新过程中出现错误则返回错误
-func (b *BookService) UpdateBook(book *model.Book) error {
-	return b.BookDB.UpdateBook(book)
+func (b *BookService) UpdateBook(id int, apply func(book *model.Book) error) error {
+	var book *model.Book
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		bookDAO := b.BookDB.WithTx(tx)
+
+		// 锁定图书，读取修改前的分类和状态
+		locked, err := bookDAO.GetBooksForUpdate([]int{id})
+		if err != nil {
+			return err
+		}
+		if len(locked) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		old := *locked[0]
+		book = locked[0]
+		if err := apply(book); err != nil {
+			return err
+		}
+
+		// 库存不能低于待支付订单已预留的数量
+		if book.Stock < old.Reserved {
+			return fmt.Errorf("库存不能小于已预留数量%d", old.Reserved)
+		}
+		// 出版社关联只由出版社文字决定，保留数据库中的值，避免覆盖合并出版社的结果
+		book.PublisherID = old.PublisherID
+		if old.CategoryID != book.CategoryID || old.Status != book.Status {
+			if err := b.adjustCategoryBookCounts(tx, &old, -1); err != nil {
+				return err
+			}
+			if err := b.adjustCategoryBookCounts(tx, book, 1); err != nil {
+				return err
+			}
+		}
+		if err := bookDAO.UpdateBook(book); err != nil {
+			return err
+		}
+		return b.ContributorService.LinkBook(tx, book, old.Author != book.Author, old.Publisher != book.Publisher)
+	})
+	if err != nil {
+		return err
+	}
+	b.syncBookIndexes(book)
+	return nil
 }
 
 // DeleteBook 删除书籍
+// 在同一事务中更新所属分类的图书数量
 // 参数:
 //
 //	id - 书籍ID
@@ -174,7 +570,62 @@ func (b *BookService) UpdateBook(book *model.Book) error {
 //
 //	error - 如果删除过程中出现错误则返回错误
 func (b *BookService) DeleteBook(id int) error {
-	return b.BookDB.DeleteBook(id)
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		bookDAO := b.BookDB.WithTx(tx)
+
+		locked, err := bookDAO.GetBooksForUpdate([]int{id})
+		if err != nil {
+			return err
+		}
+		if err := bookDAO.DeleteBook(id); err != nil {
+			return err
+		}
+		if len(locked) == 0 {
+			return nil
+		}
+		return b.adjustCategoryBookCounts(tx, locked[0], -1)
+	})
+	if err != nil {
+		return err
+	}
+	if err := b.SearchEngine.Remove(id); err != nil {
+		log.Printf("从搜索索引移除图书失败，图书ID: %d，错误: %v", id, err)
+	}
+	b.SuggestIndex.RemoveBook(id)
+	return nil
+}
+
+// adjustCategoryBookCounts 按图书的分类和状态增减分类的图书数量（需在事务中调用）
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	book - 图书信息
+//	delta - 变化量，新增图书为1，移除图书为-1
+//
+// 返回:
+//
+//	error - 如果更新过程中出现错误则返回错误
+func (b *BookService) adjustCategoryBookCounts(tx *gorm.DB, book *model.Book, delta int) error {
+	if book.CategoryID == 0 {
+		return nil
+	}
+	categoryDAO := b.CategoryDB.WithTx(tx)
+	if book.Status == 1 {
+		return categoryDAO.AdjustBookCounts(int(book.CategoryID), delta, 0)
+	}
+	return categoryDAO.AdjustBookCounts(int(book.CategoryID), 0, delta)
+}
+
+// syncBookIndexes 将图书的最新内容同步到搜索索引和搜索建议索引
+// 索引同步失败不影响图书的保存，只记录日志，下次启动时会重建索引
+// 参数:
+//
+//	book - 已保存的图书
+func (b *BookService) syncBookIndexes(book *model.Book) {
+	if err := b.SearchEngine.Index(book); err != nil {
+		log.Printf("同步搜索索引失败，图书ID: %d，错误: %v", book.ID, err)
+	}
+	b.SuggestIndex.IndexBook(book)
 }
 
 // CreateBookFromRequest 从请求创建图书
@@ -210,9 +661,10 @@ func (b *BookService) CreateBookFromRequest(req *model.BookCreateRequest) error
 		Format:      req.Format,
 		CategoryID:  categoryID,
 		Sale:        req.Sale,
+		Rating:      req.Rating,
 	}
 
-	return b.BookDB.CreateBook(book)
+	return b.CreateBook(book)
 }
 
 // UpdateBookFromRequest 从请求更新图书
@@ -225,11 +677,19 @@ func (b *BookService) CreateBookFromRequest(req *model.BookCreateRequest) error
 //
 //	error - 如果更新过程中出现错误则返回错误
 func (b *BookService) UpdateBookFromRequest(id uint, req *model.BookUpdateRequest) error {
-	book, err := b.BookDB.GetBookByIDForAdmin(int(id))
-	if err != nil {
-		return err
-	}
+	return b.UpdateBook(int(id), func(book *model.Book) error {
+		applyBookUpdateRequest(book, req)
+		return nil
+	})
+}
 
+// applyBookUpdateRequest 将图书更新请求中填写的字段应用到图书
+// 销售量由订单流程维护，不随编辑修改
+// 参数:
+//
+//	book - 加锁后读取的图书
+//	req - 图书更新请求对象指针
+func applyBookUpdateRequest(book *model.Book, req *model.BookUpdateRequest) {
 	// 更新字段（排除状态字段，避免意外修改状态）
 	if req.Title != "" {
 		book.Title = req.Title
@@ -280,11 +740,9 @@ func (b *BookService) UpdateBookFromRequest(id uint, req *model.BookUpdateReques
 	if req.CategoryID > 0 {
 		book.CategoryID = req.CategoryID
 	}
-	if req.Sale >= 0 {
-		book.Sale = req.Sale
+	if req.Rating > 0 {
+		book.Rating = req.Rating
 	}
-
-	return b.BookDB.UpdateBook(book)
 }
 
 // GetBookList 获取图书列表（管理员）
@@ -297,7 +755,7 @@ func (b *BookService) UpdateBookFromRequest(id uint, req *model.BookUpdateReques
 //	*model.BookListResponse - 图书列表响应对象指针
 //	error - 如果查询过程中出现错误则返回错误
 func (b *BookService) GetBookList(req *model.BookListRequest) (*model.BookListResponse, error) {
-	books, total, err := b.BookDB.GetBooksByPageForAdminWithSearch(req.Page, req.PageSize, req.Title, req.Author, req.Type, req.Status)
+	books, total, err := b.BookDB.GetBooksByPageForAdminWithSearch(req.Page, req.PageSize, req.Title, req.Author, req.Type, req.Status, req.TagID)
 	if err != nil {
 		return nil, err
 	}
@@ -328,13 +786,45 @@ func (b *BookService) GetBookList(req *model.BookListRequest) (*model.BookListRe
 //
 //	error - 如果更新过程中出现错误则返回错误
 func (b *BookService) UpdateBookStatus(id uint, status int) error {
-	book, err := b.BookDB.GetBookByIDForAdmin(int(id))
-	if err != nil {
+	return b.UpdateBook(int(id), func(book *model.Book) error {
+		book.Status = status
+		return nil
+	})
+}
+
+// SetBookGrouping 设置图书所属的作品和丛书
+// 参数:
+//
+//	id - 书籍ID
+//	req - 作品和丛书设置，各字段整体覆盖图书当前的设置
+//
+// 返回:
+//
+//	error - 图书、作品或丛书不存在时返回错误
+func (b *BookService) SetBookGrouping(id int, req *model.BookGroupingRequest) error {
+	if req.SeriesID == nil && req.Volume > 0 {
+		return ErrVolumeWithoutSeries
+	}
+	if _, err := b.BookDB.GetBookByIDForAdmin(id); err != nil {
 		return err
 	}
-
-	book.Status = status
-	return b.BookDB.UpdateBook(book)
+	if req.WorkID != nil {
+		if _, err := b.WorkDB.GetWorkByID(*req.WorkID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrWorkNotFound
+			}
+			return err
+		}
+	}
+	if req.SeriesID != nil {
+		if _, err := b.SeriesDB.GetSeriesByID(*req.SeriesID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSeriesNotFound
+			}
+			return err
+		}
+	}
+	return b.BookDB.SetGrouping(id, req)
 }
 
 // GetCategories 获取所有分类
@@ -357,19 +847,8 @@ func (b *BookService) GetCategories() ([]model.Category, error) {
 	return categoryList, nil
 }
 
-// CreateCategory 创建分类
-// 参数:
-//
-//	category - 分类对象指针
-//
-// 返回:
-//
-//	error - 如果创建过程中出现错误则返回错误
-func (b *BookService) CreateCategory(category *model.Category) error {
-	return b.CategoryDB.CreateCategory(category)
-}
-
 // UpdateCategory 更新分类
+// 不修改父分类，移动分类使用CategoryService.MoveCategory
 // 参数:
 //
 //	id - 分类ID
@@ -407,17 +886,9 @@ func (b *BookService) UpdateCategory(id uint, updates map[string]any) error {
 		category.IsActive = isActive
 	}
 
-	return b.CategoryDB.UpdateCategory(category)
-}
-
-// DeleteCategory 删除分类
-// 参数:
-//
-//	id - 分类ID
-//
-// 返回:
-//
-//	error - 如果删除过程中出现错误则返回错误
-func (b *BookService) DeleteCategory(id uint) error {
-	return b.CategoryDB.DeleteCategory(int(id))
+	if err := b.CategoryDB.UpdateCategory(category); err != nil {
+		return err
+	}
+	b.SuggestIndex.IndexCategory(category)
+	return nil
 }
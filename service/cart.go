@@ -0,0 +1,631 @@
+package service
+
+import (
+	"bookstore/config"
+	"bookstore/model"
+	"bookstore/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// userCartTTL 登录用户购物车的过期时间，每次修改后重新计时
+	userCartTTL = 30 * 24 * time.Hour
+	// maxCartItems 购物车中最多可放入的图书种类数
+	maxCartItems = 100
+	// maxCartQuantity 购物车中单本图书的最大数量
+	maxCartQuantity = 99
+)
+
+// CartService 购物车服务
+// 负责购物车的增删改查、按图书当前状态和库存校验购物车，以及将购物车结算为订单
+type CartService struct {
+	CartDAO      *repository.CartDAO // 购物车数据访问对象
+	BookDAO      *repository.BookDAO // 图书数据访问对象
+	orderService *OrderService       // 订单服务，用于结算下单
+	guestTTL     time.Duration       // 访客购物车的保留时间
+}
+
+// CartItem 购物车商品
+type CartItem struct {
+	BookID    int         `json:"book_id"`        // 图书ID
+	Quantity  int         `json:"quantity"`       // 数量
+	UnitPrice int         `json:"unit_price"`     // 当前折后单价（元）
+	Subtotal  int         `json:"subtotal"`       // 小计（元）
+	Available bool        `json:"available"`      // 当前是否可以购买
+	Message   string      `json:"message"`        // 不可购买的原因
+	Book      *model.Book `json:"book,omitempty"` // 图书信息，图书已删除时为空
+}
+
+// CartResponse 购物车内容
+type CartResponse struct {
+	CartToken        string     `json:"cart_token,omitempty"` // 访客购物车令牌，仅访客购物车返回
+	Items            []CartItem `json:"items"`                // 购物车商品，按图书ID倒序
+	TotalQuantity    int        `json:"total_quantity"`       // 可购买商品的总数量
+	TotalAmount      int        `json:"total_amount"`         // 可购买商品的总金额（元）
+	UnavailableCount int        `json:"unavailable_count"`    // 不可购买的商品种类数
+}
+
+// CheckoutCartRequest 购物车结算请求
+type CheckoutCartRequest struct {
+	AddressID  int    `json:"address_id"`  // 收货地址ID，0表示使用默认地址
+	CouponCode string `json:"coupon_code"` // 优惠码（可选）
+	BookIDs    []int  `json:"book_ids"`    // 要结算的图书ID，为空表示结算全部可购买的商品
+}
+
+// NewCartService 创建新的购物车服务实例
+// 返回:
+//
+//	*CartService - 初始化好的购物车服务
+func NewCartService() *CartService {
+	return &CartService{
+		CartDAO:      repository.NewCartDAO(),
+		BookDAO:      repository.NewBookDAO(),
+		orderService: NewOrderService(),
+		guestTTL:     config.AppConfig.Cart.GuestTTL,
+	}
+}
+
+// userCartOwner 生成登录用户的购物车标识
+func userCartOwner(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// guestCartOwner 生成访客购物车标识
+func guestCartOwner(token string) string {
+	return "guest:" + token
+}
+
+// GetCart 获取用户的购物车
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	*CartResponse - 购物车内容，包含每件商品当前是否可以购买
+//	error - 错误信息
+func (c *CartService) GetCart(userID int) (*CartResponse, error) {
+	return c.getCart(userCartOwner(userID))
+}
+
+// AddItem 向购物车添加图书，已在购物车中时累加数量
+// 参数:
+//
+//	userID - 用户ID
+//	bookID - 图书ID
+//	quantity - 添加数量
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 图书不可购买或库存不足时返回错误
+func (c *CartService) AddItem(userID, bookID, quantity int) (*CartResponse, error) {
+	return c.addItem(userCartOwner(userID), userCartTTL, bookID, quantity)
+}
+
+// UpdateQuantity 修改购物车中图书的数量，数量为0时移除
+// 参数:
+//
+//	userID - 用户ID
+//	bookID - 图书ID
+//	quantity - 新的数量
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 图书不在购物车中、不可购买或库存不足时返回错误
+func (c *CartService) UpdateQuantity(userID, bookID, quantity int) (*CartResponse, error) {
+	return c.updateQuantity(userCartOwner(userID), userCartTTL, bookID, quantity)
+}
+
+// RemoveItem 从购物车移除图书
+// 参数:
+//
+//	userID - 用户ID
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 错误信息
+func (c *CartService) RemoveItem(userID, bookID int) (*CartResponse, error) {
+	owner := userCartOwner(userID)
+	if err := c.CartDAO.RemoveItems(owner, bookID); err != nil {
+		return nil, err
+	}
+	return c.getCart(owner)
+}
+
+// ClearCart 清空购物车
+// 参数:
+//
+//	userID - 用户ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (c *CartService) ClearCart(userID int) error {
+	return c.CartDAO.Clear(userCartOwner(userID))
+}
+
+// Checkout 将购物车结算为订单
+// 通过OrderService.CreateOrder下单，价格、库存和优惠券以下单时的校验为准；
+// 下单成功后从购物车移除已结算的商品
+// 参数:
+//
+//	userID - 用户ID
+//	req - 结算请求
+//
+// 返回:
+//
+//	*model.Order - 创建的订单
+//	error - 错误信息
+func (c *CartService) Checkout(userID int, req *CheckoutCartRequest) (*model.Order, error) {
+	owner := userCartOwner(userID)
+	cart, err := c.getCart(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[int]bool, len(req.BookIDs))
+	for _, id := range req.BookIDs {
+		selected[id] = true
+	}
+
+	var items []CreateOrderItemRequest
+	var bookIDs []int
+	for _, item := range cart.Items {
+		if len(selected) > 0 && !selected[item.BookID] {
+			continue
+		}
+		if !item.Available {
+			// 明确选择了不可购买的商品时提示用户，未选择时跳过
+			if len(selected) > 0 {
+				return nil, fmt.Errorf("图书%d不可购买: %s", item.BookID, item.Message)
+			}
+			continue
+		}
+		items = append(items, CreateOrderItemRequest{
+			BookID:   item.BookID,
+			Quantity: item.Quantity,
+			Price:    item.UnitPrice,
+		})
+		bookIDs = append(bookIDs, item.BookID)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("购物车中没有可结算的商品")
+	}
+
+	order, err := c.orderService.CreateOrder(&CreateOrderRequest{
+		UserID:     userID,
+		AddressID:  req.AddressID,
+		CouponCode: req.CouponCode,
+		Items:      items,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 订单已创建，移除购物车商品失败不影响下单结果
+	_ = c.CartDAO.RemoveItems(owner, bookIDs...)
+	return order, nil
+}
+
+// getCart 读取购物车并按图书当前状态和库存校验每件商品
+// 参数:
+//
+//	owner - 购物车标识
+//
+// 返回:
+//
+//	*CartResponse - 购物车内容
+//	error - 错误信息
+func (c *CartService) getCart(owner string) (*CartResponse, error) {
+	quantities, err := c.CartDAO.GetItems(owner)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := c.loadBooks(quantities)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &CartResponse{Items: make([]CartItem, 0, len(quantities))}
+	for bookID, quantity := range quantities {
+		item := CartItem{BookID: bookID, Quantity: quantity, Book: books[bookID]}
+		if message := checkCartBook(item.Book, quantity); message != "" {
+			item.Message = message
+			cart.UnavailableCount++
+		} else {
+			item.Available = true
+			item.UnitPrice = item.Book.FinalPrice()
+			item.Subtotal = item.UnitPrice * quantity
+			cart.TotalQuantity += quantity
+			cart.TotalAmount += item.Subtotal
+		}
+		cart.Items = append(cart.Items, item)
+	}
+
+	// 最近加入的图书ID通常更大，倒序排列更接近加入顺序
+	sort.Slice(cart.Items, func(i, j int) bool {
+		return cart.Items[i].BookID > cart.Items[j].BookID
+	})
+	return cart, nil
+}
+
+// loadBooks 批量加载购物车中的图书
+// 参数:
+//
+//	quantities - 以图书ID为键的数量
+//
+// 返回:
+//
+//	map[int]*model.Book - 以图书ID为键的图书信息，已删除的图书不在其中
+//	error - 错误信息
+func (c *CartService) loadBooks(quantities map[int]int) (map[int]*model.Book, error) {
+	ids := make([]int, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+
+	list, err := c.BookDAO.GetBooksByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make(map[int]*model.Book, len(list))
+	for _, book := range list {
+		books[book.ID] = book
+	}
+	return books, nil
+}
+
+// checkCartBook 检查图书当前是否可以按指定数量购买
+// 参数:
+//
+//	book - 图书信息，已删除时为nil
+//	quantity - 购买数量
+//
+// 返回:
+//
+//	string - 不可购买的原因，可以购买时为空
+func checkCartBook(book *model.Book, quantity int) string {
+	switch {
+	case book == nil:
+		return "图书不存在"
+	case book.Status != 1:
+		return "图书已下架"
+	case book.AvailableStock() <= 0:
+		return "图书已售罄"
+	case book.AvailableStock() < quantity:
+		return fmt.Sprintf("库存不足，仅剩%d件", book.AvailableStock())
+	default:
+		return ""
+	}
+}
+
+// addItem 向指定购物车添加图书
+// 参数:
+//
+//	owner - 购物车标识
+//	ttl - 购物车过期时间
+//	bookID - 图书ID
+//	quantity - 添加数量
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 错误信息
+func (c *CartService) addItem(owner string, ttl time.Duration, bookID, quantity int) (*CartResponse, error) {
+	if quantity <= 0 {
+		return nil, errors.New("数量必须大于0")
+	}
+
+	items, err := c.CartDAO.GetItems(owner)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := items[bookID]; !ok && len(items) >= maxCartItems {
+		return nil, fmt.Errorf("购物车最多只能放入%d种图书", maxCartItems)
+	}
+
+	if err := c.setItem(owner, ttl, bookID, items[bookID]+quantity); err != nil {
+		return nil, err
+	}
+	return c.getCart(owner)
+}
+
+// updateQuantity 修改指定购物车中图书的数量
+// 参数:
+//
+//	owner - 购物车标识
+//	ttl - 购物车过期时间
+//	bookID - 图书ID
+//	quantity - 新的数量，0表示移除
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 错误信息
+func (c *CartService) updateQuantity(owner string, ttl time.Duration, bookID, quantity int) (*CartResponse, error) {
+	if quantity < 0 {
+		return nil, errors.New("数量不能小于0")
+	}
+
+	current, err := c.CartDAO.GetQuantity(owner, bookID)
+	if err != nil {
+		return nil, err
+	}
+	if current == 0 {
+		return nil, errors.New("图书不在购物车中")
+	}
+
+	if quantity == 0 {
+		err = c.CartDAO.RemoveItems(owner, bookID)
+	} else {
+		err = c.setItem(owner, ttl, bookID, quantity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.getCart(owner)
+}
+
+// setItem 校验图书状态和库存后写入购物车
+// 参数:
+//
+//	owner - 购物车标识
+//	ttl - 购物车过期时间
+//	bookID - 图书ID
+//	quantity - 写入后的数量
+//
+// 返回:
+//
+//	error - 图书不可购买或库存不足时返回错误
+func (c *CartService) setItem(owner string, ttl time.Duration, bookID, quantity int) error {
+	if quantity > maxCartQuantity {
+		return fmt.Errorf("单本图书最多购买%d件", maxCartQuantity)
+	}
+
+	book, err := c.BookDAO.GetBookByIDForAdmin(bookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		book = nil
+	} else if err != nil {
+		return err
+	}
+	if message := checkCartBook(book, quantity); message != "" {
+		return errors.New(message)
+	}
+	return c.CartDAO.SetQuantity(owner, bookID, quantity, ttl)
+}
+
+// CartMergeNotice 合并访客购物车时被调整或丢弃的商品
+type CartMergeNotice struct {
+	BookID    int    `json:"book_id"`   // 图书ID
+	Title     string `json:"title"`     // 图书标题，图书已删除时为空
+	Requested int    `json:"requested"` // 合并前两个购物车的数量之和
+	Merged    int    `json:"merged"`    // 合并后的数量，0表示已丢弃
+	Message   string `json:"message"`   // 调整或丢弃的原因
+}
+
+// CartMergeResult 访客购物车合并结果
+type CartMergeResult struct {
+	MergedCount int               `json:"merged_count"` // 成功合并的图书种类数
+	Notices     []CartMergeNotice `json:"notices"`      // 被调整数量或丢弃的商品
+}
+
+// guestTokenLength 访客购物车令牌的长度（十六进制字符数）
+const guestTokenLength = 32
+
+// NewGuestCartToken 生成新的访客购物车令牌
+// 令牌为随机生成的不透明字符串，不包含任何用户信息
+// 返回:
+//
+//	string - 访客购物车令牌
+//	error - 错误信息
+func NewGuestCartToken() (string, error) {
+	buf := make([]byte, guestTokenLength/2)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ValidGuestCartToken 检查访客购物车令牌格式是否有效
+// 参数:
+//
+//	token - 访客购物车令牌
+//
+// 返回:
+//
+//	bool - 令牌格式有效时返回true
+func ValidGuestCartToken(token string) bool {
+	if len(token) != guestTokenLength {
+		return false
+	}
+	_, err := hex.DecodeString(token)
+	return err == nil
+}
+
+// GetGuestCart 获取访客购物车
+// 参数:
+//
+//	token - 访客购物车令牌
+//
+// 返回:
+//
+//	*CartResponse - 购物车内容，令牌不存在或已过期时为空购物车
+//	error - 错误信息
+func (c *CartService) GetGuestCart(token string) (*CartResponse, error) {
+	cart, err := c.getCart(guestCartOwner(token))
+	if err != nil {
+		return nil, err
+	}
+	cart.CartToken = token
+	return cart, nil
+}
+
+// AddGuestItem 向访客购物车添加图书，已在购物车中时累加数量
+// 参数:
+//
+//	token - 访客购物车令牌
+//	bookID - 图书ID
+//	quantity - 添加数量
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 图书不可购买或库存不足时返回错误
+func (c *CartService) AddGuestItem(token string, bookID, quantity int) (*CartResponse, error) {
+	cart, err := c.addItem(guestCartOwner(token), c.guestTTL, bookID, quantity)
+	if err != nil {
+		return nil, err
+	}
+	cart.CartToken = token
+	return cart, nil
+}
+
+// UpdateGuestQuantity 修改访客购物车中图书的数量，数量为0时移除
+// 参数:
+//
+//	token - 访客购物车令牌
+//	bookID - 图书ID
+//	quantity - 新的数量
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 图书不在购物车中、不可购买或库存不足时返回错误
+func (c *CartService) UpdateGuestQuantity(token string, bookID, quantity int) (*CartResponse, error) {
+	cart, err := c.updateQuantity(guestCartOwner(token), c.guestTTL, bookID, quantity)
+	if err != nil {
+		return nil, err
+	}
+	cart.CartToken = token
+	return cart, nil
+}
+
+// RemoveGuestItem 从访客购物车移除图书
+// 参数:
+//
+//	token - 访客购物车令牌
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	*CartResponse - 更新后的购物车
+//	error - 错误信息
+func (c *CartService) RemoveGuestItem(token string, bookID int) (*CartResponse, error) {
+	if err := c.CartDAO.RemoveItems(guestCartOwner(token), bookID); err != nil {
+		return nil, err
+	}
+	return c.GetGuestCart(token)
+}
+
+// ClearGuestCart 清空访客购物车
+// 参数:
+//
+//	token - 访客购物车令牌
+//
+// 返回:
+//
+//	error - 错误信息
+func (c *CartService) ClearGuestCart(token string) error {
+	return c.CartDAO.Clear(guestCartOwner(token))
+}
+
+// MergeGuestCart 将访客购物车合并到用户购物车，合并后删除访客购物车
+// 同一本图书的数量相加，超过可用库存或单本上限时按上限保留；
+// 已删除、已下架或已售罄的图书被丢弃，调整和丢弃的商品在结果中说明
+// 参数:
+//
+//	userID - 用户ID
+//	token - 访客购物车令牌
+//
+// 返回:
+//
+//	*CartMergeResult - 合并结果，访客购物车为空时为nil
+//	error - 错误信息
+func (c *CartService) MergeGuestCart(userID int, token string) (*CartMergeResult, error) {
+	guestOwner := guestCartOwner(token)
+	guestItems, err := c.CartDAO.GetItems(guestOwner)
+	if err != nil {
+		return nil, err
+	}
+	if len(guestItems) == 0 {
+		return nil, nil
+	}
+
+	userOwner := userCartOwner(userID)
+	userItems, err := c.CartDAO.GetItems(userOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := c.loadBooks(guestItems)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按图书ID顺序合并，保证购物车种类数达到上限时结果稳定
+	bookIDs := make([]int, 0, len(guestItems))
+	for bookID := range guestItems {
+		bookIDs = append(bookIDs, bookID)
+	}
+	sort.Ints(bookIDs)
+
+	result := &CartMergeResult{Notices: []CartMergeNotice{}}
+	merged := make(map[int]int, len(guestItems))
+	itemCount := len(userItems)
+	for _, bookID := range bookIDs {
+		book := books[bookID]
+		notice := CartMergeNotice{
+			BookID:    bookID,
+			Requested: userItems[bookID] + guestItems[bookID],
+		}
+		if book != nil {
+			notice.Title = book.Title
+		}
+
+		// 仅因数量超出库存而不可购买的图书按上限保留，其余情况丢弃
+		if message := checkCartBook(book, 1); message != "" {
+			notice.Message = message
+			result.Notices = append(result.Notices, notice)
+			continue
+		}
+		if _, ok := userItems[bookID]; !ok {
+			if itemCount >= maxCartItems {
+				notice.Message = fmt.Sprintf("购物车最多只能放入%d种图书", maxCartItems)
+				result.Notices = append(result.Notices, notice)
+				continue
+			}
+			itemCount++
+		}
+
+		quantity := min(notice.Requested, book.AvailableStock(), maxCartQuantity)
+		merged[bookID] = quantity
+		result.MergedCount++
+		if quantity < notice.Requested {
+			notice.Merged = quantity
+			notice.Message = fmt.Sprintf("数量超出可购买上限，已调整为%d件", quantity)
+			result.Notices = append(result.Notices, notice)
+		}
+	}
+
+	if err := c.CartDAO.SetItems(userOwner, merged, userCartTTL); err != nil {
+		return nil, err
+	}
+	if err := c.CartDAO.Clear(guestOwner); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
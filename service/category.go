@@ -1,14 +1,34 @@
 package service
 
 import (
+	"bookstore/global"
 	"bookstore/model"
 	"bookstore/repository"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// categoryMaxDepth 分类树的最大层级数，顶级分类为第1层
+const categoryMaxDepth = 10
+
+var (
+	ErrCategoryNotFound       = errors.New("分类不存在")
+	ErrCategoryParentNotFound = errors.New("父分类不存在")
+	ErrCategoryCycle          = errors.New("不能将分类移动到自身或其子分类下")
+	ErrCategoryTooDeep        = errors.New("分类层级不能超过" + strconv.Itoa(categoryMaxDepth) + "层")
+	ErrCategoryNotEmpty       = errors.New("分类下还有子分类或图书，不能删除")
 )
 
 // CategoryService 分类服务
 // 负责分类相关的业务逻辑处理
 type CategoryService struct {
-	CategoryDAO *repository.CategoryDAO // 分类数据访问对象
+	CategoryDAO  *repository.CategoryDAO // 分类数据访问对象
+	BookDAO      *repository.BookDAO     // 书籍数据访问对象，用于删除前检查分类下的图书
+	SuggestIndex *SuggestIndex           // 搜索建议索引，分类名称变化时同步
 }
 
 // NewCategoryService 创建新的分类服务实例
@@ -17,7 +37,9 @@ type CategoryService struct {
 //	*CategoryService - 初始化好的分类服务
 func NewCategoryService() *CategoryService {
 	return &CategoryService{
-		CategoryDAO: repository.NewCategoryDAO(),
+		CategoryDAO:  repository.NewCategoryDAO(),
+		BookDAO:      repository.NewBookDAO(),
+		SuggestIndex: getSuggestIndex(),
 	}
 }
 
@@ -30,6 +52,63 @@ func (c *CategoryService) GetAllCategories() ([]*model.Category, error) {
 	return c.CategoryDAO.GetAllCategories()
 }
 
+// GetCategoryTree 获取嵌套的分类树
+// 同一层级的分类按排序权重降序、ID升序排列
+// 返回:
+//
+//	[]*model.Category - 顶级分类列表，子分类保存在Children中
+//	error - 错误信息
+func (c *CategoryService) GetCategoryTree() ([]*model.Category, error) {
+	categories, err := c.CategoryDAO.GetAllCategories()
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories), nil
+}
+
+// buildCategoryTree 按父分类ID将分类组装成树
+// 父分类不存在的分类作为顶级分类返回
+// 参数:
+//
+//	categories - 全部分类
+//
+// 返回:
+//
+//	[]*model.Category - 顶级分类列表
+func buildCategoryTree(categories []*model.Category) []*model.Category {
+	byID := make(map[int]*model.Category, len(categories))
+	for _, category := range categories {
+		category.Children = nil
+		byID[category.ID] = category
+	}
+
+	roots := make([]*model.Category, 0)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+
+	var sortTree func(nodes []*model.Category)
+	sortTree = func(nodes []*model.Category) {
+		slices.SortFunc(nodes, func(a, b *model.Category) int {
+			if a.Sort != b.Sort {
+				return b.Sort - a.Sort
+			}
+			return a.ID - b.ID
+		})
+		for _, node := range nodes {
+			sortTree(node.Children)
+		}
+	}
+	sortTree(roots)
+	return roots
+}
+
 // GetCategoryByID 根据ID获取分类
 // 参数:
 //
@@ -52,10 +131,42 @@ func (c *CategoryService) GetCategoryByID(id int) (*model.Category, error) {
 //
 //	error - 错误信息
 func (c *CategoryService) CreateCategory(category *model.Category) error {
-	return c.CategoryDAO.CreateCategory(category)
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		categoryDAO := c.CategoryDAO.WithTx(tx)
+
+		// 锁定父分类，避免创建过程中父分类被移动导致路径失效
+		parentPath, depth := "/", 0
+		if category.ParentID != nil {
+			parents, err := categoryDAO.GetCategoriesForUpdate([]int{*category.ParentID})
+			if err != nil {
+				return err
+			}
+			if len(parents) == 0 {
+				return ErrCategoryParentNotFound
+			}
+			parentPath, depth = parents[0].Path, parents[0].Depth+1
+			if depth >= categoryMaxDepth {
+				return ErrCategoryTooDeep
+			}
+		}
+
+		category.Path, category.Depth = "", depth
+		category.BookCount, category.OffShelfBookCount = 0, 0
+		if err := categoryDAO.CreateCategory(category); err != nil {
+			return err
+		}
+		category.Path = parentPath + strconv.Itoa(category.ID) + "/"
+		return categoryDAO.SetPath(category.ID, category.Path, category.Depth)
+	})
+	if err != nil {
+		return err
+	}
+	c.SuggestIndex.IndexCategory(category)
+	return nil
 }
 
 // UpdateCategory 更新分类
+// 不修改父分类，移动分类使用MoveCategory
 // 参数:
 //
 //	category - 分类对象指针
@@ -64,10 +175,96 @@ func (c *CategoryService) CreateCategory(category *model.Category) error {
 //
 //	error - 错误信息
 func (c *CategoryService) UpdateCategory(category *model.Category) error {
-	return c.CategoryDAO.UpdateCategory(category)
+	// 分类不存在时Save会插入新记录，新记录没有物化路径
+	if _, err := c.CategoryDAO.GetCategoryByID(category.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+	if err := c.CategoryDAO.UpdateCategory(category); err != nil {
+		return err
+	}
+	c.SuggestIndex.IndexCategory(category)
+	return nil
+}
+
+// MoveCategory 将分类连同其全部子孙分类移动到新的父分类下
+// 参数:
+//
+//	id - 分类ID
+//	parentID - 新的父分类ID，nil表示移动为顶级分类
+//
+// 返回:
+//
+//	*model.Category - 移动后的分类
+//	error - 分类不存在、移动到自身或子分类下、超出最大层级时返回错误
+func (c *CategoryService) MoveCategory(id int, parentID *int) (*model.Category, error) {
+	var category *model.Category
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		categoryDAO := c.CategoryDAO.WithTx(tx)
+
+		ids := []int{id}
+		if parentID != nil {
+			if *parentID == id {
+				return ErrCategoryCycle
+			}
+			ids = append(ids, *parentID)
+		}
+		locked, err := categoryDAO.GetCategoriesForUpdate(ids)
+		if err != nil {
+			return err
+		}
+		var parent *model.Category
+		for _, item := range locked {
+			if item.ID == id {
+				category = item
+			} else {
+				parent = item
+			}
+		}
+		if category == nil {
+			return ErrCategoryNotFound
+		}
+		if parentID != nil && parent == nil {
+			return ErrCategoryParentNotFound
+		}
+
+		newPath, newDepth := "/"+strconv.Itoa(id)+"/", 0
+		if parent != nil {
+			// 新的父分类位于被移动分类的子树中时会形成环
+			if strings.HasPrefix(parent.Path, category.Path) {
+				return ErrCategoryCycle
+			}
+			newPath, newDepth = parent.Path+strconv.Itoa(id)+"/", parent.Depth+1
+		}
+		if newPath == category.Path {
+			return nil
+		}
+
+		depthDelta := newDepth - category.Depth
+		maxDepth, err := categoryDAO.GetSubtreeMaxDepth(category.Path)
+		if err != nil {
+			return err
+		}
+		if maxDepth+depthDelta >= categoryMaxDepth {
+			return ErrCategoryTooDeep
+		}
+
+		if err := categoryDAO.MoveSubtree(id, parentID, category.Path, newPath, depthDelta); err != nil {
+			return err
+		}
+		category.ParentID, category.Path, category.Depth = parentID, newPath, newDepth
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
 }
 
 // DeleteCategory 删除分类
+// 只能删除没有子分类且没有图书（含下架图书）的分类
 // 参数:
 //
 //	id - 分类ID
@@ -76,5 +273,100 @@ func (c *CategoryService) UpdateCategory(category *model.Category) error {
 //
 //	error - 错误信息
 func (c *CategoryService) DeleteCategory(id int) error {
-	return c.CategoryDAO.DeleteCategory(id)
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		categoryDAO := c.CategoryDAO.WithTx(tx)
+
+		// 锁定分类，避免检查后又在其下创建子分类
+		locked, err := categoryDAO.GetCategoriesForUpdate([]int{id})
+		if err != nil {
+			return err
+		}
+		if len(locked) == 0 {
+			return ErrCategoryNotFound
+		}
+
+		children, err := categoryDAO.CountChildren(id)
+		if err != nil {
+			return err
+		}
+		books, err := c.BookDAO.WithTx(tx).CountBooksByCategory(id)
+		if err != nil {
+			return err
+		}
+		if children > 0 || books > 0 {
+			return ErrCategoryNotEmpty
+		}
+		return categoryDAO.DeleteCategory(id)
+	})
+	if err != nil {
+		return err
+	}
+	c.SuggestIndex.RemoveCategory(id)
+	return nil
+}
+
+// CategoryCountFix 重新统计时修正的分类图书数量
+type CategoryCountFix struct {
+	CategoryID           int    `json:"category_id"`              // 分类ID
+	Name                 string `json:"name"`                     // 分类名称
+	OldBookCount         int    `json:"old_book_count"`           // 修正前的上架图书数量
+	BookCount            int    `json:"book_count"`               // 修正后的上架图书数量
+	OldOffShelfBookCount int    `json:"old_off_shelf_book_count"` // 修正前的下架图书数量
+	OffShelfBookCount    int    `json:"off_shelf_book_count"`     // 修正后的下架图书数量
+}
+
+// CategoryRecountResult 重新统计分类图书数量的结果
+type CategoryRecountResult struct {
+	Checked int                `json:"checked"` // 检查的分类数量
+	Fixed   []CategoryCountFix `json:"fixed"`   // 统计值有偏差并已修正的分类
+}
+
+// RecountBookCounts 按图书表重新统计所有分类的上架和下架图书数量，修正统计值的偏差
+// 统计期间锁定全部分类，图书的增删改会等待统计完成后再更新分类数量，避免修正结果被覆盖
+// 返回:
+//
+//	*CategoryRecountResult - 检查的分类数量和修正明细
+//	error - 错误信息
+func (c *CategoryService) RecountBookCounts() (*CategoryRecountResult, error) {
+	result := &CategoryRecountResult{Fixed: make([]CategoryCountFix, 0)}
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		categoryDAO := c.CategoryDAO.WithTx(tx)
+
+		categories, err := categoryDAO.GetAllCategoriesForUpdate()
+		if err != nil {
+			return err
+		}
+		counts, err := c.BookDAO.WithTx(tx).CountBooksGroupByCategory()
+		if err != nil {
+			return err
+		}
+		actual := make(map[int]repository.CategoryBookCount, len(counts))
+		for _, count := range counts {
+			actual[count.CategoryID] = count
+		}
+
+		result.Checked = len(categories)
+		for _, category := range categories {
+			count := actual[category.ID]
+			if category.BookCount == count.OnSale && category.OffShelfBookCount == count.OffShelf {
+				continue
+			}
+			if err := categoryDAO.SetBookCounts(category.ID, count.OnSale, count.OffShelf); err != nil {
+				return err
+			}
+			result.Fixed = append(result.Fixed, CategoryCountFix{
+				CategoryID:           category.ID,
+				Name:                 category.Name,
+				OldBookCount:         category.BookCount,
+				BookCount:            count.OnSale,
+				OldOffShelfBookCount: category.OffShelfBookCount,
+				OffShelfBookCount:    count.OffShelf,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// contributorMigrateBatchSize 迁移署名文字时每批读取的图书数量
+const contributorMigrateBatchSize = 200
+
+// contributorRoleSuffixes 署名后缀与角色的对应关系，较长的后缀在前
+var contributorRoleSuffixes = []struct {
+	suffix string
+	role   string
+}{
+	{"编著", model.ContributorRoleAuthor},
+	{"主编", model.ContributorRoleEditor},
+	{"著", model.ContributorRoleAuthor},
+	{"译", model.ContributorRoleTranslator},
+	{"编", model.ContributorRoleEditor},
+}
+
+// contributorBrackets 国籍等标注使用的括号，键为左括号，值为对应的右括号
+var contributorBrackets = map[rune]rune{
+	'[': ']', '［': '］', '(': ')', '（': '）', '【': '】', '〔': '〕',
+}
+
+// parsedContributor 从署名文字中解析出的一位作者
+type parsedContributor struct {
+	Name string // 作者姓名
+	Role string // 署名角色
+}
+
+// parseContributors 解析图书的作者署名文字
+// 多人以逗号、顿号、分号、斜杠或&分隔，姓名前的国籍标注（如"[美]"、"（英）"）会被去除；
+// 以"译"结尾的为译者，以"编"或"主编"结尾的为编者，以"著"、"编著"结尾或没有后缀的为著者
+// 参数:
+//
+//	text - 署名文字，如"[美] 艾萨克·阿西莫夫 著、叶李华 译"
+//
+// 返回:
+//
+//	[]parsedContributor - 按署名顺序排列的作者，同一姓名和角色只保留一次
+func parseContributors(text string) []parsedContributor {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		switch r {
+		case ',', '，', '、', ';', '；', '/', '／', '&', '＆':
+			return true
+		}
+		return false
+	})
+
+	contributors := make([]parsedContributor, 0, len(fields))
+	seen := make(map[parsedContributor]bool)
+	for _, field := range fields {
+		name := stripContributorPrefix(strings.TrimSpace(field))
+		role := model.ContributorRoleAuthor
+		for _, item := range contributorRoleSuffixes {
+			if trimmed, ok := strings.CutSuffix(name, item.suffix); ok {
+				name, role = strings.TrimSpace(trimmed), item.role
+				break
+			}
+		}
+		// "张三等著"中的"等"表示还有其他作者，不属于姓名
+		name = strings.TrimSpace(strings.TrimSuffix(name, "等"))
+		if name == "" {
+			continue
+		}
+
+		contributor := parsedContributor{Name: name, Role: role}
+		if !seen[contributor] {
+			seen[contributor] = true
+			contributors = append(contributors, contributor)
+		}
+	}
+	return contributors
+}
+
+// stripContributorPrefix 去除姓名前用括号标注的国籍或朝代，如"[美]"、"（清）"
+// 参数:
+//
+//	name - 去除首尾空白后的姓名
+//
+// 返回:
+//
+//	string - 去除标注后的姓名
+func stripContributorPrefix(name string) string {
+	for name != "" {
+		runes := []rune(name)
+		closing, ok := contributorBrackets[runes[0]]
+		if !ok {
+			return name
+		}
+		end := strings.IndexRune(name, closing)
+		if end < 0 {
+			return name
+		}
+		name = strings.TrimSpace(name[end+len(string(closing)):])
+	}
+	return name
+}
+
+// ContributorService 图书署名服务
+// 根据图书的作者和出版社文字维护图书与作者、出版社实体的关联
+type ContributorService struct {
+	AuthorDAO    *repository.AuthorDAO    // 作者数据访问对象
+	PublisherDAO *repository.PublisherDAO // 出版社数据访问对象
+	BookDAO      *repository.BookDAO      // 书籍数据访问对象
+}
+
+// NewContributorService 创建新的图书署名服务实例
+// 返回:
+//
+//	*ContributorService - 初始化好的图书署名服务
+func NewContributorService() *ContributorService {
+	return &ContributorService{
+		AuthorDAO:    repository.NewAuthorDAO(),
+		PublisherDAO: repository.NewPublisherDAO(),
+		BookDAO:      repository.NewBookDAO(),
+	}
+}
+
+// GetBookContributors 获取图书的署名信息
+// 参数:
+//
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	[]model.BookContributor - 按署名顺序排列的作者
+//	error - 错误信息
+func (s *ContributorService) GetBookContributors(bookID int) ([]model.BookContributor, error) {
+	return s.AuthorDAO.GetBookContributors(bookID)
+}
+
+// LinkBook 按图书的作者和出版社文字关联作者和出版社实体（需在事务中调用）
+// 姓名或名称先按合并后保留的别名查找，找不到时创建新的作者或出版社
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	book - 已保存的图书，关联出版社后更新其PublisherID
+//	authors - 是否重新关联作者
+//	publisher - 是否重新关联出版社
+//
+// 返回:
+//
+//	error - 错误信息
+func (s *ContributorService) LinkBook(tx *gorm.DB, book *model.Book, authors, publisher bool) error {
+	if authors {
+		authorDAO := s.AuthorDAO.WithTx(tx)
+		links := make([]model.BookAuthor, 0)
+		seen := make(map[model.BookAuthor]bool)
+		for _, contributor := range parseContributors(book.Author) {
+			author, err := authorDAO.FindOrCreateAuthor(contributor.Name)
+			if err != nil {
+				return err
+			}
+			// 不同的署名可能是同一作者的别名
+			key := model.BookAuthor{AuthorID: author.ID, Role: contributor.Role}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			links = append(links, model.BookAuthor{
+				BookID:   book.ID,
+				AuthorID: author.ID,
+				Role:     contributor.Role,
+				Sort:     len(links),
+			})
+		}
+		if err := authorDAO.ReplaceBookAuthors(book.ID, links); err != nil {
+			return err
+		}
+	}
+
+	if publisher {
+		var publisherID *int
+		if name := strings.TrimSpace(book.Publisher); name != "" {
+			found, err := s.PublisherDAO.WithTx(tx).FindOrCreatePublisher(name)
+			if err != nil {
+				return err
+			}
+			publisherID = &found.ID
+		}
+		if err := s.BookDAO.WithTx(tx).SetPublisherID(book.ID, publisherID); err != nil {
+			return err
+		}
+		book.PublisherID = publisherID
+	}
+	return nil
+}
+
+// ContributorMigrateResult 迁移署名文字的结果
+type ContributorMigrateResult struct {
+	Migrated int `json:"migrated"` // 完成关联的图书数量
+	Failed   int `json:"failed"`   // 关联失败的图书数量，详见日志
+}
+
+// MigrateBooks 为尚未关联作者或出版社的图书按署名文字建立关联
+// 已有关联的部分保持不变，可以重复执行；每本图书在单独的事务中处理，失败的图书记录日志后继续
+// 返回:
+//
+//	*ContributorMigrateResult - 迁移结果
+//	error - 读取图书失败时返回错误
+func (s *ContributorService) MigrateBooks() (*ContributorMigrateResult, error) {
+	result := &ContributorMigrateResult{}
+	afterID := 0
+	for {
+		books, err := s.BookDAO.GetBooksWithoutContributors(afterID, contributorMigrateBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(books) == 0 {
+			break
+		}
+
+		for _, book := range books {
+			afterID = book.ID
+			if err := s.migrateBook(book.ID); err != nil {
+				log.Printf("关联图书作者和出版社失败，图书ID: %d，错误: %v", book.ID, err)
+				result.Failed++
+				continue
+			}
+			result.Migrated++
+		}
+	}
+	log.Printf("图书署名迁移完成，关联: %d，失败: %d", result.Migrated, result.Failed)
+	return result, nil
+}
+
+// migrateBook 为一本图书关联尚未关联的作者和出版社
+// 参数:
+//
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (s *ContributorService) migrateBook(bookID int) error {
+	return global.DBClient.Transaction(func(tx *gorm.DB) error {
+		// 锁定图书，避免与后台修改图书同时进行
+		locked, err := s.BookDAO.WithTx(tx).GetBooksForUpdate([]int{bookID})
+		if err != nil || len(locked) == 0 {
+			return err
+		}
+		book := locked[0]
+
+		count, err := s.AuthorDAO.WithTx(tx).CountBookAuthors(book.ID)
+		if err != nil {
+			return err
+		}
+		return s.LinkBook(tx, book, count == 0, book.PublisherID == nil)
+	})
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CouponService 优惠券服务
+// 负责优惠券的管理、下单时的校验与优惠计算，以及订单取消后的优惠券退回
+type CouponService struct {
+	CouponDAO   *repository.CouponDAO   // 优惠券数据访问对象
+	CategoryDAO *repository.CategoryDAO // 分类数据访问对象，用于判断图书是否属于指定分类的子树
+}
+
+// CouponRequest 创建或更新优惠券请求
+type CouponRequest struct {
+	Code         string    `json:"code" binding:"required,max=50"`                   // 优惠码（必填，不区分大小写）
+	Name         string    `json:"name" binding:"required,max=100"`                  // 优惠券名称（必填）
+	Description  string    `json:"description" binding:"max=255"`                    // 使用说明
+	Type         string    `json:"type" binding:"required,oneof=fixed percent"`      // 优惠类型：fixed-满减，percent-折扣
+	Value        int       `json:"value" binding:"required,min=1"`                   // 减免金额（元）或减免百分比
+	MaxDiscount  int       `json:"max_discount" binding:"min=0"`                     // 折扣券最高减免金额（元），0表示不限
+	MinSpend     int       `json:"min_spend" binding:"min=0"`                        // 适用商品金额门槛（元），0表示无门槛
+	Scope        string    `json:"scope" binding:"required,oneof=all category book"` // 适用范围：all-全场，category-指定分类，book-指定图书
+	ScopeIDs     []int     `json:"scope_ids"`                                        // 适用的分类ID或图书ID
+	TotalLimit   int       `json:"total_limit" binding:"min=0"`                      // 总使用次数上限，0表示不限
+	PerUserLimit int       `json:"per_user_limit" binding:"min=0"`                   // 每个用户使用次数上限，0表示不限
+	StartAt      time.Time `json:"start_at" binding:"required"`                      // 生效时间
+	EndAt        time.Time `json:"end_at" binding:"required"`                        // 失效时间
+	IsActive     *bool     `json:"is_active"`                                        // 是否启用，默认启用
+}
+
+// validate 校验优惠券请求中各字段之间的约束
+func (r *CouponRequest) validate() error {
+	if r.Type == model.CouponTypePercent && r.Value > 100 {
+		return errors.New("折扣券的减免百分比不能超过100")
+	}
+	if !r.EndAt.After(r.StartAt) {
+		return errors.New("失效时间必须晚于生效时间")
+	}
+	if r.Scope != model.CouponScopeAll && len(r.ScopeIDs) == 0 {
+		return errors.New("指定分类或图书的优惠券必须设置适用范围")
+	}
+	return nil
+}
+
+// applyTo 将请求内容写入优惠券
+func (r *CouponRequest) applyTo(coupon *model.Coupon) {
+	coupon.Code = normalizeCouponCode(r.Code)
+	coupon.Name = r.Name
+	coupon.Description = r.Description
+	coupon.Type = r.Type
+	coupon.Value = r.Value
+	coupon.MaxDiscount = r.MaxDiscount
+	coupon.MinSpend = r.MinSpend
+	coupon.Scope = r.Scope
+	coupon.ScopeIDs = r.ScopeIDs
+	if r.Scope == model.CouponScopeAll {
+		coupon.ScopeIDs = nil
+	}
+	coupon.TotalLimit = r.TotalLimit
+	coupon.PerUserLimit = r.PerUserLimit
+	coupon.StartAt = r.StartAt
+	coupon.EndAt = r.EndAt
+	if r.IsActive != nil {
+		coupon.IsActive = *r.IsActive
+	}
+}
+
+// NewCouponService 创建新的优惠券服务实例
+// 返回:
+//
+//	*CouponService - 初始化好的优惠券服务
+func NewCouponService() *CouponService {
+	return &CouponService{
+		CouponDAO:   repository.NewCouponDAO(),
+		CategoryDAO: repository.NewCategoryDAO(),
+	}
+}
+
+// normalizeCouponCode 规范化优惠码，去除首尾空白并转为大写
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CreateCoupon 创建优惠券
+// 参数:
+//
+//	req - 优惠券请求
+//
+// 返回:
+//
+//	*model.Coupon - 创建的优惠券
+//	error - 错误信息
+func (c *CouponService) CreateCoupon(req *CouponRequest) (*model.Coupon, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	coupon := &model.Coupon{IsActive: true}
+	req.applyTo(coupon)
+	if err := c.CouponDAO.CreateCoupon(coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+// UpdateCoupon 更新优惠券
+// 已使用次数不受影响，修改规则只对之后的订单生效
+// 参数:
+//
+//	id - 优惠券ID
+//	req - 优惠券请求
+//
+// 返回:
+//
+//	*model.Coupon - 更新后的优惠券
+//	error - 错误信息
+func (c *CouponService) UpdateCoupon(id int, req *CouponRequest) (*model.Coupon, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	coupon, err := c.CouponDAO.GetCouponByID(id)
+	if err != nil {
+		return nil, errors.New("优惠券不存在")
+	}
+	req.applyTo(coupon)
+	if err := c.CouponDAO.UpdateCoupon(coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+// DeleteCoupon 删除优惠券
+// 已被使用过的优惠券需要保留核销记录，只能停用不能删除
+// 参数:
+//
+//	id - 优惠券ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (c *CouponService) DeleteCoupon(id int) error {
+	if _, err := c.CouponDAO.GetCouponByID(id); err != nil {
+		return errors.New("优惠券不存在")
+	}
+
+	count, err := c.CouponDAO.CountRedemptions(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("优惠券已被使用，只能停用不能删除")
+	}
+	return c.CouponDAO.DeleteCoupon(id)
+}
+
+// GetCouponByID 根据ID获取优惠券
+// 参数:
+//
+//	id - 优惠券ID
+//
+// 返回:
+//
+//	*model.Coupon - 优惠券
+//	error - 错误信息
+func (c *CouponService) GetCouponByID(id int) (*model.Coupon, error) {
+	return c.CouponDAO.GetCouponByID(id)
+}
+
+// GetCouponList 获取优惠券列表
+// 参数:
+//
+//	keyword - 按优惠码或名称模糊搜索
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*model.Coupon - 优惠券列表
+//	int64 - 总记录数
+//	error - 错误信息
+func (c *CouponService) GetCouponList(keyword string, page, pageSize int) ([]*model.Coupon, int64, error) {
+	return c.CouponDAO.GetCoupons(keyword, page, pageSize)
+}
+
+// GetCouponStats 获取优惠券核销统计
+// 参数:
+//
+//	id - 优惠券ID
+//
+// 返回:
+//
+//	*model.CouponStats - 核销统计
+//	error - 错误信息
+func (c *CouponService) GetCouponStats(id int) (*model.CouponStats, error) {
+	if _, err := c.CouponDAO.GetCouponByID(id); err != nil {
+		return nil, errors.New("优惠券不存在")
+	}
+	return c.CouponDAO.GetCouponStats(id)
+}
+
+// GetRedemptions 获取优惠券使用记录
+// 参数:
+//
+//	id - 优惠券ID
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*model.CouponRedemption - 使用记录列表
+//	int64 - 总记录数
+//	error - 错误信息
+func (c *CouponService) GetRedemptions(id, page, pageSize int) ([]*model.CouponRedemption, int64, error) {
+	return c.CouponDAO.GetRedemptions(id, page, pageSize)
+}
+
+// minPayableAmount 使用优惠券后订单的最低实付金额（元）
+const minPayableAmount = 1
+
+// applyCoupon 校验优惠券并计算订单优惠（需在事务中调用）
+// 锁定优惠券行，校验启用状态、有效期、总使用次数和用户使用次数，
+// 按适用范围计算优惠金额并按小计比例分摊到各订单项，同时占用一次使用次数；
+// 优惠金额不会使订单实付金额低于minPayableAmount
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	code - 优惠码
+//	userID - 下单用户ID
+//	items - 订单项，分摊的优惠金额写入DiscountAmount
+//	books - 以图书ID为键的图书信息，用于判断适用范围
+//
+// 返回:
+//
+//	*model.OrderDiscount - 订单优惠明细
+//	error - 优惠券不可用时返回错误
+func (c *CouponService) applyCoupon(tx *gorm.DB, code string, userID int, items []*model.OrderItem, books map[int]*model.Book) (*model.OrderDiscount, error) {
+	couponDAO := c.CouponDAO.WithTx(tx)
+
+	coupon, err := couponDAO.GetCouponByCodeForUpdate(normalizeCouponCode(code))
+	if err != nil {
+		return nil, errors.New("优惠券不存在")
+	}
+
+	now := time.Now()
+	if !coupon.IsActive || now.Before(coupon.StartAt) || !now.Before(coupon.EndAt) {
+		return nil, errors.New("优惠券不在有效期内")
+	}
+	if coupon.TotalLimit > 0 && coupon.UsedCount >= coupon.TotalLimit {
+		return nil, errors.New("优惠券已被领完")
+	}
+	if coupon.PerUserLimit > 0 {
+		used, err := couponDAO.CountUserRedemptions(coupon.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(coupon.PerUserLimit) {
+			return nil, errors.New("已达到该优惠券的使用次数上限")
+		}
+	}
+
+	// 分类券适用于指定分类及其所有子孙分类，需要图书所属分类的物化路径
+	var categoryPaths map[uint]string
+	if coupon.Scope == model.CouponScopeCategory {
+		categoryPaths, err = c.bookCategoryPaths(tx, books)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 筛选适用的订单项
+	var eligible []*model.OrderItem
+	var eligibleSubtotal int
+	for _, item := range items {
+		if couponApplies(coupon, books[item.BookID], categoryPaths) {
+			eligible = append(eligible, item)
+			eligibleSubtotal += item.Subtotal
+		}
+	}
+	if len(eligible) == 0 {
+		return nil, errors.New("优惠券不适用于订单中的商品")
+	}
+	if eligibleSubtotal < coupon.MinSpend {
+		return nil, fmt.Errorf("适用商品金额未满%d元，无法使用该优惠券", coupon.MinSpend)
+	}
+
+	// 优惠后订单至少需要支付minPayableAmount元，支付渠道不支持0元订单的支付和退款
+	var orderSubtotal int
+	for _, item := range items {
+		orderSubtotal += item.Subtotal
+	}
+	discount := min(couponDiscount(coupon, eligibleSubtotal), max(orderSubtotal-minPayableAmount, 0))
+	allocateDiscount(eligible, eligibleSubtotal, discount)
+
+	if err := couponDAO.AddUsedCount(coupon.ID, 1); err != nil {
+		return nil, err
+	}
+
+	return &model.OrderDiscount{
+		CouponID:    coupon.ID,
+		CouponCode:  coupon.Code,
+		Description: describeCoupon(coupon),
+		Amount:      discount,
+	}, nil
+}
+
+// bookCategoryPaths 获取图书所属分类的物化路径（需在事务中调用）
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	books - 以图书ID为键的图书信息
+//
+// 返回:
+//
+//	map[uint]string - 以分类ID为键的物化路径
+//	error - 错误信息
+func (c *CouponService) bookCategoryPaths(tx *gorm.DB, books map[int]*model.Book) (map[uint]string, error) {
+	ids := make([]int, 0, len(books))
+	for _, book := range books {
+		if book.CategoryID != 0 {
+			ids = append(ids, int(book.CategoryID))
+		}
+	}
+
+	categories, err := c.CategoryDAO.WithTx(tx).GetCategoriesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		paths[uint(category.ID)] = category.Path
+	}
+	return paths, nil
+}
+
+// couponApplies 判断优惠券是否适用于指定图书
+// 分类券按图书所属分类的物化路径匹配，指定分类的子孙分类下的图书同样适用
+// 参数:
+//
+//	coupon - 优惠券
+//	book - 图书
+//	categoryPaths - 以分类ID为键的物化路径，仅分类券需要
+//
+// 返回:
+//
+//	bool - 是否适用
+func couponApplies(coupon *model.Coupon, book *model.Book, categoryPaths map[uint]string) bool {
+	switch coupon.Scope {
+	case model.CouponScopeAll:
+		return true
+	case model.CouponScopeCategory:
+		path, ok := categoryPaths[book.CategoryID]
+		if !ok || path == "" {
+			return slices.Contains(coupon.ScopeIDs, int(book.CategoryID))
+		}
+		return slices.ContainsFunc(coupon.ScopeIDs, func(id int) bool {
+			return strings.Contains(path, "/"+strconv.Itoa(id)+"/")
+		})
+	case model.CouponScopeBook:
+		return slices.Contains(coupon.ScopeIDs, book.ID)
+	default:
+		return false
+	}
+}
+
+// couponDiscount 计算优惠金额
+// 参数:
+//
+//	coupon - 优惠券
+//	eligibleSubtotal - 适用商品的小计之和
+//
+// 返回:
+//
+//	int - 优惠金额，不超过适用商品的小计之和
+func couponDiscount(coupon *model.Coupon, eligibleSubtotal int) int {
+	var discount int
+	switch coupon.Type {
+	case model.CouponTypeFixed:
+		discount = coupon.Value
+	case model.CouponTypePercent:
+		discount = eligibleSubtotal * coupon.Value / 100
+		if coupon.MaxDiscount > 0 && discount > coupon.MaxDiscount {
+			discount = coupon.MaxDiscount
+		}
+	}
+	return min(discount, eligibleSubtotal)
+}
+
+// allocateDiscount 按小计比例将优惠金额分摊到各订单项
+// 向下取整后的余数依次补到尚未减免到0元的订单项上，保证分摊之和等于优惠金额，
+// 且每项分摊的优惠不超过其小计，退款时按分摊后的金额退回
+// 参数:
+//
+//	items - 适用的订单项
+//	subtotal - 适用订单项的小计之和
+//	discount - 优惠金额，不超过subtotal
+func allocateDiscount(items []*model.OrderItem, subtotal, discount int) {
+	remaining := discount
+	for _, item := range items {
+		item.DiscountAmount = min(discount*item.Subtotal/subtotal, item.Subtotal)
+		remaining -= item.DiscountAmount
+	}
+	// 各项剩余可减免金额之和不小于余数，一轮即可分完
+	for _, item := range items {
+		if remaining <= 0 {
+			break
+		}
+		extra := min(remaining, item.Subtotal-item.DiscountAmount)
+		item.DiscountAmount += extra
+		remaining -= extra
+	}
+}
+
+// describeCoupon 生成优惠说明，如"满100减20"、"减免15%，最高减30元"
+func describeCoupon(coupon *model.Coupon) string {
+	var desc string
+	switch coupon.Type {
+	case model.CouponTypeFixed:
+		desc = fmt.Sprintf("减%d元", coupon.Value)
+	case model.CouponTypePercent:
+		desc = fmt.Sprintf("减免%d%%", coupon.Value)
+		if coupon.MaxDiscount > 0 {
+			desc += fmt.Sprintf("，最高减%d元", coupon.MaxDiscount)
+		}
+	}
+	if coupon.MinSpend > 0 {
+		desc = fmt.Sprintf("满%d元", coupon.MinSpend) + desc
+	}
+	return coupon.Name + "（" + desc + "）"
+}
+
+// recordRedemption 记录优惠券使用（需在事务中调用）
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	order - 已创建的订单
+//	discount - 订单优惠明细
+//
+// 返回:
+//
+//	error - 错误信息
+func (c *CouponService) recordRedemption(tx *gorm.DB, order *model.Order, discount *model.OrderDiscount) error {
+	return c.CouponDAO.WithTx(tx).CreateRedemption(&model.CouponRedemption{
+		CouponID:       discount.CouponID,
+		UserID:         order.UserID,
+		OrderID:        order.ID,
+		DiscountAmount: discount.Amount,
+		Status:         model.CouponRedemptionStatusUsed,
+	})
+}
+
+// releaseCoupon 订单取消后退回优惠券（需在事务中调用）
+// 订单未使用优惠券时不做任何操作
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (c *CouponService) releaseCoupon(tx *gorm.DB, orderID int) error {
+	couponDAO := c.CouponDAO.WithTx(tx)
+
+	redemption, err := couponDAO.GetUsedRedemptionByOrderID(orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := couponDAO.UpdateRedemptionStatus(redemption.ID, model.CouponRedemptionStatusReleased); err != nil {
+		return err
+	}
+	return couponDAO.AddUsedCount(redemption.CouponID, -1)
+}
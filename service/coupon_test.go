@@ -0,0 +1,52 @@
+package service
+
+import (
+	"bookstore/model"
+	"reflect"
+	"testing"
+)
+
+func TestAllocateDiscount(t *testing.T) {
+	tests := []struct {
+		name      string
+		subtotals []int
+		discount  int
+		want      []int
+	}{
+		{"按比例整除", []int{100, 50, 50}, 20, []int{10, 5, 5}},
+		{"余数补到第一项", []int{10, 10, 10}, 10, []int{4, 3, 3}},
+		{"余数超过最后一项小计", []int{10, 10, 1}, 20, []int{10, 10, 0}},
+		{"小额订单项分摊为0", []int{99, 1}, 50, []int{50, 0}},
+		{"全额减免", []int{7, 3, 1}, 11, []int{7, 3, 1}},
+		{"无优惠", []int{30, 20}, 0, []int{0, 0}},
+		{"单项", []int{15}, 14, []int{14}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]*model.OrderItem, len(tt.subtotals))
+			subtotal := 0
+			for i, s := range tt.subtotals {
+				items[i] = &model.OrderItem{Subtotal: s}
+				subtotal += s
+			}
+
+			allocateDiscount(items, subtotal, tt.discount)
+
+			got := make([]int, len(items))
+			sum := 0
+			for i, item := range items {
+				got[i] = item.DiscountAmount
+				sum += item.DiscountAmount
+				if item.DiscountAmount < 0 || item.DiscountAmount > item.Subtotal {
+					t.Errorf("item %d discount %d out of range [0, %d]", i, item.DiscountAmount, item.Subtotal)
+				}
+			}
+			if sum != tt.discount {
+				t.Errorf("allocated %d in total, want %d", sum, tt.discount)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateDiscount = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// invoiceableStatuses 可以开具发票的订单状态
+// 全额退款的订单不再开具新发票，已开具的发票仍可查看
+var invoiceableStatuses = map[int]bool{
+	model.OrderStatusPaid:              true,
+	model.OrderStatusShipped:           true,
+	model.OrderStatusCompleted:         true,
+	model.OrderStatusRefunding:         true,
+	model.OrderStatusPartiallyRefunded: true,
+}
+
+// InvoiceService 发票服务
+// 负责为已支付订单开具、查询、重新开具和作废发票
+type InvoiceService struct {
+	InvoiceDAO *repository.InvoiceDAO // 发票数据访问对象
+	OrderDAO   *repository.OrderDAO   // 订单数据访问对象
+}
+
+// NewInvoiceService 创建新的发票服务实例
+// 返回:
+//
+//	*InvoiceService - 初始化好的发票服务
+func NewInvoiceService() *InvoiceService {
+	return &InvoiceService{
+		InvoiceDAO: repository.NewInvoiceDAO(),
+		OrderDAO:   repository.NewOrderDAO(),
+	}
+}
+
+// GetUserInvoice 获取用户订单的发票
+// 订单尚未开具过发票时自动开具；发票被管理员作废且未重新开具时返回错误
+// 参数:
+//
+//	orderID - 订单ID
+//	userID - 用户ID，只允许订单所有者查看
+//
+// 返回:
+//
+//	*model.Invoice - 发票
+//	error - 订单不存在或不属于该用户时返回ErrOrderNotFound
+func (s *InvoiceService) GetUserInvoice(orderID, userID int) (*model.Invoice, error) {
+	invoice, err := s.InvoiceDAO.GetIssuedInvoiceByOrderID(orderID)
+	if err == nil {
+		if invoice.UserID != userID {
+			return nil, ErrOrderNotFound
+		}
+		return invoice, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	err = global.DBClient.Transaction(func(tx *gorm.DB) error {
+		orderDAO := s.OrderDAO.WithTx(tx)
+		invoiceDAO := s.InvoiceDAO.WithTx(tx)
+
+		// 锁定订单，避免并发请求为同一订单重复开具
+		order, err := orderDAO.GetOrderForUpdate(orderID)
+		if err != nil || order.UserID != userID {
+			return ErrOrderNotFound
+		}
+
+		// 加锁前其他请求可能已开具
+		invoice, err = invoiceDAO.GetIssuedInvoiceByOrderID(orderID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		count, err := invoiceDAO.CountInvoicesByOrderID(orderID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("发票已作废，请联系客服重新开具")
+		}
+
+		invoice, err = s.issueInvoice(tx, orderID, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// GetInvoiceByID 根据ID获取发票（管理员用）
+// 参数:
+//
+//	id - 发票ID
+//
+// 返回:
+//
+//	*model.Invoice - 发票
+//	error - 错误信息
+func (s *InvoiceService) GetInvoiceByID(id int) (*model.Invoice, error) {
+	invoice, err := s.InvoiceDAO.GetInvoiceByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("发票不存在")
+	}
+	return invoice, err
+}
+
+// GetOrderInvoices 获取订单的全部发票，包括已作废的（管理员用）
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	[]*model.Invoice - 发票列表，按开具时间倒序排列
+//	error - 错误信息
+func (s *InvoiceService) GetOrderInvoices(orderID int) ([]*model.Invoice, error) {
+	return s.InvoiceDAO.GetInvoicesByOrderID(orderID)
+}
+
+// RegenerateInvoice 重新开具订单发票（管理员用）
+// 作废订单当前有效的发票，并按订单当前信息开具新发票，新发票使用新的发票号
+// 参数:
+//
+//	orderID - 订单ID
+//	adminID - 操作的管理员ID
+//
+// 返回:
+//
+//	*model.Invoice - 新开具的发票
+//	error - 错误信息
+func (s *InvoiceService) RegenerateInvoice(orderID, adminID int) (*model.Invoice, error) {
+	var invoice *model.Invoice
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		invoiceDAO := s.InvoiceDAO.WithTx(tx)
+
+		if _, err := s.OrderDAO.WithTx(tx).GetOrderForUpdate(orderID); err != nil {
+			return ErrOrderNotFound
+		}
+
+		current, err := invoiceDAO.GetIssuedInvoiceByOrderID(orderID)
+		if err == nil {
+			if _, err := invoiceDAO.VoidInvoice(current.ID, adminID, "重新开具"); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		invoice, err = s.issueInvoice(tx, orderID, adminID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// VoidInvoice 作废发票（管理员用）
+// 作废后用户无法再查看该订单的发票，直到管理员重新开具
+// 参数:
+//
+//	id - 发票ID
+//	adminID - 操作的管理员ID
+//	reason - 作废原因
+//
+// 返回:
+//
+//	error - 发票不存在或已作废时返回错误
+func (s *InvoiceService) VoidInvoice(id, adminID int, reason string) error {
+	voided, err := s.InvoiceDAO.VoidInvoice(id, adminID, reason)
+	if err != nil {
+		return err
+	}
+	if !voided {
+		return errors.New("发票不存在或已作废")
+	}
+	return nil
+}
+
+// issueInvoice 在事务中为订单开具发票
+// 调用方需已锁定订单行；发票号按年份连续编号，格式为 INV + 年份 + 8位序号，如INV202600000123
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	orderID - 订单ID
+//	operatorID - 开具的管理员ID，自动开具为0
+//
+// 返回:
+//
+//	*model.Invoice - 开具的发票
+//	error - 订单不可开具发票时返回错误
+func (s *InvoiceService) issueInvoice(tx *gorm.DB, orderID, operatorID int) (*model.Invoice, error) {
+	order, err := s.OrderDAO.WithTx(tx).GetOrderByIDForAdmin(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if !order.IsPaid || !invoiceableStatuses[order.Status] {
+		if order.Status == model.OrderStatusRefunded {
+			return nil, errors.New("订单已全额退款，无法开具发票")
+		}
+		return nil, errors.New("订单未支付，无法开具发票")
+	}
+
+	now := time.Now()
+	year := now.Format("2006")
+	seq, err := s.InvoiceDAO.WithTx(tx).NextSequence(year)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &model.Invoice{
+		InvoiceNo:      fmt.Sprintf("INV%s%08d", year, seq),
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		OrderNo:        order.OrderNo,
+		Status:         model.InvoiceStatusIssued,
+		BuyerName:      order.ShippingAddress.ReceiverName,
+		BuyerPhone:     order.ShippingAddress.Phone,
+		BuyerAddress:   formatInvoiceAddress(order.ShippingAddress),
+		Items:          make([]model.InvoiceItem, 0, len(order.OrderItems)),
+		SubtotalAmount: order.SubtotalAmount,
+		DiscountAmount: order.DiscountAmount,
+		TotalAmount:    order.TotalAmount,
+		RefundedAmount: order.RefundedAmount,
+		PaidAt:         order.PaymentTime,
+		IssuedAt:       now,
+		IssuedBy:       operatorID,
+	}
+	if order.User != nil {
+		invoice.BuyerAccount = order.User.Username
+		invoice.BuyerEmail = order.User.Email
+		if invoice.BuyerName == "" {
+			invoice.BuyerName = order.User.Username
+		}
+		if invoice.BuyerPhone == "" {
+			invoice.BuyerPhone = order.User.Phone
+		}
+	}
+	// 早期订单没有商品金额字段，以订单项小计之和为准
+	if invoice.SubtotalAmount == 0 {
+		invoice.SubtotalAmount = order.TotalAmount + order.DiscountAmount
+	}
+
+	for _, item := range order.OrderItems {
+		line := model.InvoiceItem{
+			BookID:         item.BookID,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.Price,
+			Subtotal:       item.Subtotal,
+			DiscountAmount: item.DiscountAmount,
+			Amount:         item.Subtotal - item.DiscountAmount,
+		}
+		if item.Book != nil {
+			line.Title = item.Book.Title
+			line.ISBN = item.Book.ISBN
+		} else {
+			line.Title = fmt.Sprintf("图书%d", item.BookID)
+		}
+		invoice.Items = append(invoice.Items, line)
+	}
+
+	if err := s.InvoiceDAO.WithTx(tx).CreateInvoice(invoice); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// formatInvoiceAddress 将收货地址拼接为发票上显示的单行地址
+func formatInvoiceAddress(address model.AddressInfo) string {
+	parts := []string{address.Province, address.City, address.District, address.Detail}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// PDF页面尺寸（A4，单位为点）
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+)
+
+// pdfDocument 最小化的PDF文档生成器
+// 只支持单一字体的文本和直线，字体使用PDF阅读器内置的Adobe中文字体STSong-Light，
+// 不需要嵌入字体文件即可显示中文
+type pdfDocument struct {
+	pages []*bytes.Buffer // 每一页的内容流
+}
+
+// newPDFDocument 创建只包含一个空白页的PDF文档
+func newPDFDocument() *pdfDocument {
+	d := &pdfDocument{}
+	d.addPage()
+	return d
+}
+
+// addPage 新增一页，之后的绘制都在该页上进行
+func (d *pdfDocument) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// current 返回当前页的内容流
+func (d *pdfDocument) current() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// text 在指定位置绘制文本，(x, y)为文本基线左端，原点在页面左下角
+func (d *pdfDocument) text(x, y, size float64, s string) {
+	fmt.Fprintf(d.current(), "BT /F1 %.1f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, y, pdfHexString(s))
+}
+
+// textRight 绘制右对齐的文本，right为文本右端的横坐标
+func (d *pdfDocument) textRight(right, y, size float64, s string) {
+	d.text(right-pdfTextWidth(s, size), y, size, s)
+}
+
+// line 绘制一条直线
+func (d *pdfDocument) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// gray 设置之后绘制的文本和直线的灰度，0为黑色，1为白色
+func (d *pdfDocument) gray(level float64) {
+	fmt.Fprintf(d.current(), "%.2f g %.2f G\n", level, level)
+}
+
+// bytes 生成完整的PDF文件内容
+func (d *pdfDocument) bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 对象1-5依次为目录、页面树、字体、CID字体和字体描述，之后每页占用页面和内容流两个对象
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+i*2)
+	}
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light-UniGB-UCS2-H " +
+		"/Encoding /UniGB-UCS2-H /DescendantFonts [4 0 R] >>")
+	writeObject("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light " +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> " +
+		"/FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>")
+	writeObject("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 " +
+		"/FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 7+i*2))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfHexString 将文本编码为UniGB-UCS2-H编码所需的UCS-2大端十六进制字符串
+// 基本多文种平面以外的字符无法显示，替换为问号
+func pdfHexString(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r > 0xFFFF {
+			r = '?'
+		}
+		for _, u := range utf16.Encode([]rune{r}) {
+			fmt.Fprintf(&sb, "%04X", u)
+		}
+	}
+	return sb.String()
+}
+
+// pdfTextWidth 估算文本宽度，ASCII字符按半角、其余字符按全角计算
+func pdfTextWidth(s string, size float64) float64 {
+	var units float64
+	for _, r := range s {
+		if r < 0x80 {
+			units += 500
+		} else {
+			units += 1000
+		}
+	}
+	return units * size / 1000
+}
+
+// pdfTruncate 截断文本使其宽度不超过maxWidth，被截断时以省略号结尾
+func pdfTruncate(s string, size, maxWidth float64) string {
+	if pdfTextWidth(s, size) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdfTextWidth(string(runes)+"…", size) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
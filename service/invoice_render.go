@@ -0,0 +1,245 @@
+package service
+
+import (
+	"bookstore/model"
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// invoiceSellerName 发票上显示的销售方名称
+const invoiceSellerName = "MZZDX书城"
+
+// invoiceTimeLayout 发票上时间的显示格式
+const invoiceTimeLayout = "2006-01-02 15:04:05"
+
+// invoiceTemplate 可打印的HTML发票模板
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"yuan":      formatInvoiceYuan,
+	"unitPrice": formatInvoiceUnitPrice,
+	"datetime":  formatInvoiceTime,
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>发票 {{.InvoiceNo}}</title>
+<style>
+  body { font-family: "PingFang SC", "Microsoft YaHei", sans-serif; color: #333; margin: 0; background: #f5f5f5; }
+  .invoice { position: relative; width: 800px; margin: 24px auto; padding: 40px; background: #fff; box-sizing: border-box; }
+  h1 { text-align: center; font-size: 26px; letter-spacing: 8px; margin: 0 0 4px; }
+  .seller { text-align: center; color: #666; margin-bottom: 24px; }
+  .meta, .buyer { display: flex; flex-wrap: wrap; font-size: 14px; margin-bottom: 16px; }
+  .meta div, .buyer div { width: 50%; line-height: 26px; }
+  table { width: 100%; border-collapse: collapse; font-size: 14px; }
+  th, td { border: 1px solid #ddd; padding: 8px; }
+  th { background: #fafafa; }
+  td.num { text-align: right; white-space: nowrap; }
+  .totals { margin-top: 16px; text-align: right; font-size: 14px; line-height: 26px; }
+  .totals .total { font-size: 18px; font-weight: bold; }
+  .void { position: absolute; top: 160px; left: 0; right: 0; text-align: center; font-size: 96px; color: rgba(220, 53, 69, 0.25); transform: rotate(-20deg); pointer-events: none; }
+  @media print { body { background: #fff; } .invoice { margin: 0; width: auto; } }
+</style>
+</head>
+<body>
+<div class="invoice">
+  {{if .Voided}}<div class="void">已作废</div>{{end}}
+  <h1>销售发票</h1>
+  <div class="seller">{{.SellerName}}</div>
+  <div class="meta">
+    <div>发票号：{{.InvoiceNo}}</div>
+    <div>开具时间：{{datetime .IssuedAt}}</div>
+    <div>订单号：{{.OrderNo}}</div>
+    <div>支付时间：{{if .PaidAt}}{{datetime .PaidAt}}{{else}}-{{end}}</div>
+  </div>
+  <div class="buyer">
+    <div>买方：{{.BuyerName}}</div>
+    <div>账号：{{.BuyerAccount}}</div>
+    <div>电话：{{.BuyerPhone}}</div>
+    <div>邮箱：{{.BuyerEmail}}</div>
+    <div style="width: 100%">地址：{{.BuyerAddress}}</div>
+  </div>
+  <table>
+    <thead>
+      <tr><th>商品</th><th>ISBN</th><th>数量</th><th>单价</th><th>小计</th><th>优惠</th><th>折后单价</th><th>金额</th></tr>
+    </thead>
+    <tbody>
+      {{range .Items}}
+      <tr>
+        <td>{{.Title}}</td>
+        <td>{{.ISBN}}</td>
+        <td class="num">{{.Quantity}}</td>
+        <td class="num">{{yuan .UnitPrice}}</td>
+        <td class="num">{{yuan .Subtotal}}</td>
+        <td class="num">{{yuan .DiscountAmount}}</td>
+        <td class="num">{{unitPrice .Amount .Quantity}}</td>
+        <td class="num">{{yuan .Amount}}</td>
+      </tr>
+      {{end}}
+    </tbody>
+  </table>
+  <div class="totals">
+    <div>商品金额：¥{{yuan .SubtotalAmount}}</div>
+    <div>优惠金额：-¥{{yuan .DiscountAmount}}</div>
+    {{if .RefundedAmount}}<div>已退款：-¥{{yuan .RefundedAmount}}</div>{{end}}
+    <div class="total">合计：¥{{yuan .TotalAmount}}</div>
+  </div>
+</div>
+</body>
+</html>
+`))
+
+// RenderInvoiceHTML 将发票渲染为可打印的HTML页面
+// 参数:
+//
+//	invoice - 发票
+//
+// 返回:
+//
+//	[]byte - HTML内容
+//	error - 渲染失败时返回错误
+func RenderInvoiceHTML(invoice *model.Invoice) ([]byte, error) {
+	data := struct {
+		*model.Invoice
+		SellerName string
+		Voided     bool
+	}{
+		Invoice:    invoice,
+		SellerName: invoiceSellerName,
+		Voided:     invoice.Status == model.InvoiceStatusVoided,
+	}
+
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderInvoicePDF 将发票渲染为A4大小的PDF文件
+// 明细较多时自动分页，每页重复表头
+// 参数:
+//
+//	invoice - 发票
+//
+// 返回:
+//
+//	[]byte - PDF文件内容
+func RenderInvoicePDF(invoice *model.Invoice) []byte {
+	const (
+		left   = 40.0
+		right  = pdfPageWidth - 40
+		bottom = 60.0
+		row    = 20.0
+	)
+	// 明细表各列的右边界，商品列左对齐，其余列右对齐
+	columns := []struct {
+		title string
+		right float64
+	}{
+		{"商品", 250}, {"数量", 285}, {"单价", 335}, {"小计", 385},
+		{"优惠", 435}, {"折后单价", 495}, {"金额", right},
+	}
+
+	doc := newPDFDocument()
+	y := pdfPageHeight - 60
+
+	doc.text((pdfPageWidth-pdfTextWidth("销售发票", 20))/2, y, 20, "销售发票")
+	y -= 22
+	doc.text((pdfPageWidth-pdfTextWidth(invoiceSellerName, 11))/2, y, 11, invoiceSellerName)
+	if invoice.Status == model.InvoiceStatusVoided {
+		doc.gray(0.6)
+		doc.textRight(right, y+22, 16, "已作废")
+		doc.gray(0)
+	}
+
+	y -= 30
+	paidAt := "-"
+	if invoice.PaidAt != nil {
+		paidAt = formatInvoiceTime(*invoice.PaidAt)
+	}
+	meta := [][2]string{
+		{"发票号：" + invoice.InvoiceNo, "开具时间：" + formatInvoiceTime(invoice.IssuedAt)},
+		{"订单号：" + invoice.OrderNo, "支付时间：" + paidAt},
+		{"买方：" + invoice.BuyerName, "账号：" + invoice.BuyerAccount},
+		{"电话：" + invoice.BuyerPhone, "邮箱：" + invoice.BuyerEmail},
+	}
+	for _, pair := range meta {
+		doc.text(left, y, 10, pair[0])
+		doc.text(pdfPageWidth/2, y, 10, pair[1])
+		y -= 16
+	}
+	doc.text(left, y, 10, pdfTruncate("地址："+invoice.BuyerAddress, 10, right-left))
+	y -= 24
+
+	tableHeader := func() {
+		doc.line(left, y+14, right, y+14, 0.8)
+		doc.text(left, y, 10, columns[0].title)
+		for _, col := range columns[1:] {
+			doc.textRight(col.right, y, 10, col.title)
+		}
+		doc.line(left, y-6, right, y-6, 0.5)
+		y -= row
+	}
+	tableHeader()
+
+	for _, item := range invoice.Items {
+		if y < bottom {
+			doc.addPage()
+			y = pdfPageHeight - 60
+			tableHeader()
+		}
+		doc.text(left, y, 9, pdfTruncate(item.Title, 9, columns[0].right-left-8))
+		values := []string{
+			fmt.Sprint(item.Quantity),
+			formatInvoiceYuan(item.UnitPrice),
+			formatInvoiceYuan(item.Subtotal),
+			formatInvoiceYuan(item.DiscountAmount),
+			formatInvoiceUnitPrice(item.Amount, item.Quantity),
+			formatInvoiceYuan(item.Amount),
+		}
+		for i, value := range values {
+			doc.textRight(columns[i+1].right, y, 9, value)
+		}
+		y -= row
+	}
+	doc.line(left, y+row-6, right, y+row-6, 0.8)
+
+	totals := []string{
+		"商品金额：" + formatInvoiceYuan(invoice.SubtotalAmount),
+		"优惠金额：-" + formatInvoiceYuan(invoice.DiscountAmount),
+	}
+	if invoice.RefundedAmount > 0 {
+		totals = append(totals, "已退款：-"+formatInvoiceYuan(invoice.RefundedAmount))
+	}
+	if y-float64(len(totals)+1)*16 < bottom {
+		doc.addPage()
+		y = pdfPageHeight - 60
+	}
+	y -= 4
+	for _, line := range totals {
+		doc.textRight(right, y, 10, line)
+		y -= 16
+	}
+	doc.textRight(right, y-2, 13, "合计（元）："+formatInvoiceYuan(invoice.TotalAmount))
+
+	return doc.bytes()
+}
+
+// formatInvoiceYuan 将以元为单位的整数金额格式化为两位小数
+func formatInvoiceYuan(amount int) string {
+	return fmt.Sprintf("%d.00", amount)
+}
+
+// formatInvoiceUnitPrice 计算并格式化分摊优惠后的单价，保留两位小数
+func formatInvoiceUnitPrice(amount, quantity int) string {
+	if quantity <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", float64(amount)/float64(quantity))
+}
+
+// formatInvoiceTime 格式化发票上显示的时间
+func formatInvoiceTime(t time.Time) string {
+	return t.Format(invoiceTimeLayout)
+}
@@ -5,6 +5,9 @@ import (
 	"bookstore/model"
 	"bookstore/repository"
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -12,15 +15,20 @@ import (
 // OrderService 订单服务
 // 负责订单相关的业务逻辑处理，包括创建订单、支付订单、查询订单等
 type OrderService struct {
-	OrderDAO *repository.OrderDAO // 订单数据访问对象
-	BookDAO  *repository.BookDAO  // 图书数据访问对象
+	OrderDAO         *repository.OrderDAO // 订单数据访问对象
+	BookDAO          *repository.BookDAO  // 图书数据访问对象
+	addressService   *AddressService      // 收货地址服务，用于获取下单地址
+	couponService    *CouponService       // 优惠券服务，用于计算和退回优惠
+	orderNoGenerator OrderNoGenerator     // 订单号生成器
 }
 
 // CreateOrderRequest 创建订单请求
 // 用于接收创建订单的请求数据
 type CreateOrderRequest struct {
-	UserID int                      `json:"user_id"` // 用户ID
-	Items  []CreateOrderItemRequest `json:"items"`   // 订单项列表
+	UserID     int                      `json:"user_id"`     // 用户ID
+	AddressID  int                      `json:"address_id"`  // 收货地址ID，0表示使用默认地址
+	CouponCode string                   `json:"coupon_code"` // 优惠码（可选）
+	Items      []CreateOrderItemRequest `json:"items"`       // 订单项列表
 }
 
 // CreateOrderItemRequest 创建订单项请求
@@ -28,7 +36,30 @@ type CreateOrderRequest struct {
 type CreateOrderItemRequest struct {
 	BookID   int `json:"book_id"`  // 图书ID
 	Quantity int `json:"quantity"` // 购买数量
-	Price    int `json:"price"`    // 价格（元）
+	Price    int `json:"price"`    // 客户端确认的单价（元），仅用于校验价格是否变动，0表示不校验
+}
+
+// ErrOrderNotFound 订单不存在或不属于当前用户
+var ErrOrderNotFound = errors.New("订单不存在")
+
+// PriceChangedItem 价格变动的订单项
+// 用于告知客户端哪些图书的价格与下单时看到的不一致
+type PriceChangedItem struct {
+	BookID       int    `json:"book_id"`       // 图书ID
+	Title        string `json:"title"`         // 图书标题
+	ClientPrice  int    `json:"client_price"`  // 客户端提交的单价（元）
+	CurrentPrice int    `json:"current_price"` // 服务端计算的当前单价（元）
+}
+
+// PriceChangedError 价格变动错误
+// 当客户端提交的单价与服务端计算的折后价不一致时返回，订单不会被创建
+type PriceChangedError struct {
+	Items []PriceChangedItem // 价格变动的订单项列表
+}
+
+// Error 实现error接口
+func (e *PriceChangedError) Error() string {
+	return "商品价格已变动，请确认后重新下单"
 }
 
 // NewOrderService 创建新的订单服务实例
@@ -37,12 +68,19 @@ type CreateOrderItemRequest struct {
 //	*OrderService - 初始化好的订单服务
 func NewOrderService() *OrderService {
 	return &OrderService{
-		OrderDAO: repository.NewOrderDAO(),
-		BookDAO:  repository.NewBookDAO(),
+		OrderDAO:         repository.NewOrderDAO(),
+		BookDAO:          repository.NewBookDAO(),
+		addressService:   NewAddressService(),
+		couponService:    NewCouponService(),
+		orderNoGenerator: getNoGenerator(orderNoPrefix),
 	}
 }
 
 // CreateOrder 创建订单
+// 订单金额完全由服务端根据图书当前价格和折扣计算，客户端提交的价格仅用于校验
+// 下单时在同一事务中锁定图书行并预留库存，支付时转为实际销售，取消或超时时释放
+// 收货地址以快照形式保存到订单中，之后修改地址簿不影响该订单
+// 填写优惠码时在同一事务中校验并占用优惠券，优惠明细和各订单项的分摊金额随订单保存
 // 参数:
 //
 //	req - 创建订单请求对象指针
@@ -50,48 +88,119 @@ func NewOrderService() *OrderService {
 // 返回:
 //
 //	*model.Order - 创建的订单对象指针
-//	error - 错误信息
+//	error - 错误信息，价格变动时返回*PriceChangedError
 func (o *OrderService) CreateOrder(req *CreateOrderRequest) (*model.Order, error) {
 	if len(req.Items) == 0 {
 		return nil, errors.New("订单项不能为空")
 	}
 
-	// 检查库存
-	err := o.checkStockAvailability(req.Items)
+	// 合并同一图书的购买数量
+	quantities := make(map[int]int, len(req.Items))
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, errors.New("购买数量必须大于0")
+		}
+		quantities[item.BookID] += item.Quantity
+	}
+
+	address, err := o.addressService.resolveOrderAddress(req.UserID, req.AddressID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 订单号在事务外生成，避免事务期间等待Redis；下单失败时该序号作废
+	orderNo, err := o.orderNoGenerator.Generate()
 	if err != nil {
 		return nil, err
 	}
 
-	// 生成订单号
-	orderNo := o.OrderDAO.GenerateOrderNo()
+	var order *model.Order
+	err = global.DBClient.Transaction(func(tx *gorm.DB) error {
+		bookDAO := o.BookDAO.WithTx(tx)
 
-	// 计算总金额
-	var totalAmount int
-	var orderItems []*model.OrderItem
+		// 锁定图书行并检查库存
+		books, err := o.checkStockAvailability(bookDAO, quantities)
+		if err != nil {
+			return err
+		}
 
-	for _, item := range req.Items {
-		subtotal := item.Price * item.Quantity
-		totalAmount += subtotal
-
-		orderItems = append(orderItems, &model.OrderItem{
-			BookID:   item.BookID,
-			Quantity: item.Quantity,
-			Price:    item.Price,
-			Subtotal: subtotal,
-		})
-	}
+		// 使用服务端价格计算商品金额，并收集价格变动的订单项
+		var subtotalAmount int
+		var orderItems []*model.OrderItem
+		var changed []PriceChangedItem
 
-	// 创建订单
-	order := &model.Order{
-		UserID:      req.UserID,
-		OrderNo:     orderNo,
-		TotalAmount: totalAmount,
-		Status:      0, // 待支付
-		IsPaid:      false,
-	}
+		for _, item := range req.Items {
+			book := books[item.BookID]
+			unitPrice := book.FinalPrice()
+			if item.Price != 0 && item.Price != unitPrice {
+				changed = append(changed, PriceChangedItem{
+					BookID:       book.ID,
+					Title:        book.Title,
+					ClientPrice:  item.Price,
+					CurrentPrice: unitPrice,
+				})
+				continue
+			}
+
+			subtotal := unitPrice * item.Quantity
+			subtotalAmount += subtotal
+
+			orderItems = append(orderItems, &model.OrderItem{
+				BookID:   item.BookID,
+				Quantity: item.Quantity,
+				Price:    unitPrice,
+				Subtotal: subtotal,
+			})
+		}
+
+		if len(changed) > 0 {
+			return &PriceChangedError{Items: changed}
+		}
 
-	// 创建订单和订单项
-	err = o.OrderDAO.CreateOrderWithItems(order, orderItems)
+		// 创建订单
+		order = &model.Order{
+			UserID:          req.UserID,
+			OrderNo:         orderNo,
+			TotalAmount:     subtotalAmount,
+			SubtotalAmount:  subtotalAmount,
+			Status:          model.OrderStatusPending,
+			IsPaid:          false,
+			ShippingAddress: address.AddressInfo,
+		}
+
+		// 校验优惠券并计算优惠
+		var discount *model.OrderDiscount
+		if req.CouponCode != "" {
+			discount, err = o.couponService.applyCoupon(tx, req.CouponCode, req.UserID, orderItems, books)
+			if err != nil {
+				return err
+			}
+			order.CouponCode = discount.CouponCode
+			order.DiscountAmount = discount.Amount
+			order.TotalAmount = subtotalAmount - discount.Amount
+			order.Discounts = []model.OrderDiscount{*discount}
+		}
+
+		// 创建订单、优惠明细和订单项
+		if err := o.OrderDAO.WithTx(tx).CreateOrderWithItems(order, orderItems); err != nil {
+			return err
+		}
+
+		// 记录优惠券使用
+		if discount != nil {
+			if err := o.couponService.recordRedemption(tx, order, discount); err != nil {
+				return err
+			}
+		}
+
+		// 预留库存
+		for _, book := range books {
+			if err := bookDAO.ReserveStock(book.ID, quantities[book.ID]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -99,30 +208,47 @@ func (o *OrderService) CreateOrder(req *CreateOrderRequest) (*model.Order, error
 	return order, nil
 }
 
-// checkStockAvailability 检查库存是否充足
+// checkStockAvailability 锁定图书行并检查可售库存是否充足（需在事务中调用）
 // 参数:
 //
-//	items - 订单项请求列表
+//	bookDAO - 绑定到当前事务的图书DAO
+//	quantities - 以图书ID为键的购买数量
 //
 // 返回:
 //
+//	map[int]*model.Book - 以图书ID为键的图书信息
 //	error - 错误信息
-func (o *OrderService) checkStockAvailability(items []CreateOrderItemRequest) error {
-	for _, item := range items {
-		book, err := o.BookDAO.GetBookByIDForAdmin(item.BookID)
-		if err != nil {
-			return errors.New("图书不存在")
+func (o *OrderService) checkStockAvailability(bookDAO *repository.BookDAO, quantities map[int]int) (map[int]*model.Book, error) {
+	ids := make([]int, 0, len(quantities))
+	for id := range quantities {
+		ids = append(ids, id)
+	}
+
+	list, err := bookDAO.GetBooksForUpdate(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make(map[int]*model.Book, len(list))
+	for _, book := range list {
+		books[book.ID] = book
+	}
+
+	for id, quantity := range quantities {
+		book, ok := books[id]
+		if !ok {
+			return nil, errors.New("图书不存在")
 		}
 
 		if book.Status != 1 {
-			return errors.New("图书已下架")
+			return nil, errors.New("图书已下架")
 		}
 
-		if book.Stock < item.Quantity {
-			return errors.New("库存不足")
+		if book.AvailableStock() < quantity {
+			return nil, repository.ErrInsufficientStock
 		}
 	}
-	return nil
+	return books, nil
 }
 
 // GetOrderByID 根据ID获取订单
@@ -167,63 +293,421 @@ func (o *OrderService) GetUserOrders(userID int, page, pageSize int) ([]*model.O
 	return o.OrderDAO.GetUserOrders(userID, page, pageSize)
 }
 
-// PayOrder 支付订单
+// orderTransitions 订单状态机
+// 键为当前状态，值为允许流转到的目标状态
+// 退款中的订单被拒绝后恢复为申请前的状态，因此可以流转回已支付、已发货、已完成或部分退款
+var orderTransitions = map[int][]int{
+	model.OrderStatusPending:           {model.OrderStatusPaid, model.OrderStatusCancelled},
+	model.OrderStatusPaid:              {model.OrderStatusShipped, model.OrderStatusRefunding},
+	model.OrderStatusShipped:           {model.OrderStatusCompleted, model.OrderStatusRefunding},
+	model.OrderStatusCompleted:         {model.OrderStatusRefunding},
+	model.OrderStatusPartiallyRefunded: {model.OrderStatusRefunding},
+	model.OrderStatusRefunding: {
+		model.OrderStatusRefunded, model.OrderStatusPartiallyRefunded,
+		model.OrderStatusPaid, model.OrderStatusShipped, model.OrderStatusCompleted,
+	},
+}
+
+// refundStatuses 只能由售后流程写入的订单状态
+var refundStatuses = map[int]bool{
+	model.OrderStatusRefunding:         true,
+	model.OrderStatusRefunded:          true,
+	model.OrderStatusPartiallyRefunded: true,
+}
+
+// CanTransitOrderStatus 判断订单状态流转是否合法
+// 参数:
+//
+//	from - 当前状态
+//	to - 目标状态
+//
+// 返回:
+//
+//	bool - 是否允许流转
+func CanTransitOrderStatus(from, to int) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderTransitionError 订单状态流转不合法错误
+// 调用方可据此区分订单状态已变化（如支付回调到达前订单已取消）和其他失败
+type OrderTransitionError struct {
+	From int // 订单当前状态
+	To   int // 目标状态
+}
+
+// Error 实现error接口
+func (e *OrderTransitionError) Error() string {
+	return fmt.Sprintf("订单状态不允许从「%s」变更为「%s」", model.OrderStatusText(e.From), model.OrderStatusText(e.To))
+}
+
+// orderEffect 状态流转时在同一事务中执行的附加操作
+// 在状态校验通过之后、状态更新之前执行，返回错误会回滚整个流转
+type orderEffect func(tx *gorm.DB, order *model.Order) error
+
+// transitionOrder 执行订单状态流转
+// 在事务中锁定订单行，校验流转合法性，执行附加操作，更新订单状态并写入状态变更记录
 // 参数:
 //
 //	orderID - 订单ID
+//	to - 目标状态
+//	operator - 操作人类型
+//	operatorID - 操作人ID
+//	reason - 变更原因
+//	effect - 附加操作，可为nil
 //
 // 返回:
 //
 //	error - 错误信息
-func (o *OrderService) PayOrder(orderID int) error {
-	// 检查订单是否存在
+func (o *OrderService) transitionOrder(orderID, to int, operator string, operatorID int, reason string, effect orderEffect) error {
+	return global.DBClient.Transaction(func(tx *gorm.DB) error {
+		orderDAO := o.OrderDAO.WithTx(tx)
+
+		// 锁定订单行，防止并发流转
+		order, err := orderDAO.GetOrderForUpdate(orderID)
+		if err != nil {
+			return ErrOrderNotFound
+		}
+
+		from := order.Status
+		if !CanTransitOrderStatus(from, to) {
+			return &OrderTransitionError{From: from, To: to}
+		}
+
+		if effect != nil {
+			if err := effect(tx, order); err != nil {
+				return err
+			}
+		}
+
+		// 更新订单状态，支付时同时记录支付标记和支付时间
+		if to == model.OrderStatusPaid {
+			err = orderDAO.MarkOrderAsPaid(orderID)
+		} else {
+			err = orderDAO.UpdateOrderStatus(orderID, to)
+		}
+		if err != nil {
+			return err
+		}
+
+		// 记录状态变更
+		return orderDAO.CreateStatusLog(&model.OrderStatusLog{
+			OrderID:    orderID,
+			FromStatus: from,
+			ToStatus:   to,
+			Operator:   operator,
+			OperatorID: operatorID,
+			Reason:     reason,
+		})
+	})
+}
+
+// markOrderPaid 将订单标记为已支付
+// 仅由支付回调在验签通过后调用，同一事务中将预留库存转为实际销售
+// 参数:
+//
+//	orderID - 订单ID
+//	reason - 变更原因
+//	effect - 附加操作（如更新支付单），在库存扣减前执行，可为nil
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) markOrderPaid(orderID int, reason string, effect orderEffect) error {
+	return o.transitionOrder(orderID, model.OrderStatusPaid, model.OrderOperatorSystem, 0, reason,
+		func(tx *gorm.DB, order *model.Order) error {
+			if effect != nil {
+				if err := effect(tx, order); err != nil {
+					return err
+				}
+			}
+
+			// 将下单时预留的库存转为实际销售
+			bookDAO := o.BookDAO.WithTx(tx)
+			for _, item := range sortedOrderItems(order.OrderItems) {
+				if err := bookDAO.ConfirmReservedStock(item.BookID, item.Quantity); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+// CancelOrder 用户取消订单
+// 只有订单所有者可以取消，且仅待支付订单可以取消
+// 参数:
+//
+//	orderID - 订单ID
+//	userID - 当前用户ID
+//	reason - 取消原因
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) CancelOrder(orderID, userID int, reason string) error {
 	order, err := o.OrderDAO.GetOrderByID(orderID)
 	if err != nil {
-		return err
+		return ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return errors.New("无权操作该订单")
 	}
 
-	// 检查订单是否已支付
-	if order.IsPaid {
-		return errors.New("订单已支付")
+	if reason == "" {
+		reason = "用户取消"
 	}
+	return o.cancelOrder(orderID, model.OrderOperatorUser, userID, reason)
+}
 
-	// 使用事务处理支付和库存更新
-	err = global.DBClient.Transaction(func(tx *gorm.DB) error {
-		// 再次检查库存（防止并发问题）
-		for _, item := range order.OrderItems {
-			var book model.Book
-			if err := tx.First(&book, item.BookID).Error; err != nil {
-				return errors.New("图书不存在")
+// ExpireOrder 系统取消超时未支付的订单
+// 与用户取消共用同一取消逻辑，原因记录为timeout
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) ExpireOrder(orderID int) error {
+	return o.cancelOrder(orderID, model.OrderOperatorSystem, 0, model.OrderCancelReasonTimeout)
+}
+
+// cancelOrder 取消订单
+// 所有取消场景（用户取消、超时取消、管理员取消）的统一入口
+// 参数:
+//
+//	orderID - 订单ID
+//	operator - 操作人类型
+//	operatorID - 操作人ID
+//	reason - 取消原因
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) cancelOrder(orderID int, operator string, operatorID int, reason string) error {
+	return o.transitionOrder(orderID, model.OrderStatusCancelled, operator, operatorID, reason,
+		func(tx *gorm.DB, order *model.Order) error {
+			// 释放下单时预留的库存
+			bookDAO := o.BookDAO.WithTx(tx)
+			for _, item := range sortedOrderItems(order.OrderItems) {
+				if err := bookDAO.ReleaseStock(item.BookID, item.Quantity); err != nil {
+					return err
+				}
 			}
-			if book.Stock < item.Quantity {
-				return errors.New("库存不足")
+
+			// 退回下单时使用的优惠券
+			return o.couponService.releaseCoupon(tx, order.ID)
+		})
+}
+
+// ConfirmReceipt 用户确认收货
+// 只有订单所有者可以确认，且仅已发货订单可以确认
+// 参数:
+//
+//	orderID - 订单ID
+//	userID - 当前用户ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) ConfirmReceipt(orderID, userID int) error {
+	order, err := o.OrderDAO.GetOrderByID(orderID)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return errors.New("无权操作该订单")
+	}
+	return o.completeOrder(orderID, model.OrderOperatorUser, userID, "用户确认收货")
+}
+
+// AutoCompleteOrder 系统自动确认收货
+// 发货后超过确认收货期限仍未完成的订单由系统自动完成
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) AutoCompleteOrder(orderID int) error {
+	return o.completeOrder(orderID, model.OrderOperatorSystem, 0, "超时自动确认收货")
+}
+
+// completeOrder 完成订单
+// 所有完成场景（用户确认收货、包裹全部签收、超时自动确认）的统一入口，
+// 只有已发货的订单可以完成，售后处理中的订单需等售后审核结束
+// 参数:
+//
+//	orderID - 订单ID
+//	operator - 操作人类型
+//	operatorID - 操作人ID
+//	reason - 完成原因
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) completeOrder(orderID int, operator string, operatorID int, reason string) error {
+	return o.transitionOrder(orderID, model.OrderStatusCompleted, operator, operatorID, reason,
+		func(tx *gorm.DB, order *model.Order) error {
+			if order.Status == model.OrderStatusRefunding {
+				return errors.New("订单售后处理中，暂不能确认收货")
 			}
-		}
+			if order.Status != model.OrderStatusShipped {
+				return errors.New("仅已发货的订单可以确认收货")
+			}
+			return nil
+		})
+}
 
-		// 标记订单为已支付
-		if err := tx.Model(&model.Order{}).Where("id = ?", orderID).Updates(
-			map[string]any{
-				"status":       1,
-				"is_paid":      true,
-				"payment_time": gorm.Expr("NOW()"),
-			}).Error; err != nil {
-			return err
-		}
+// sortedOrderItems 按图书ID升序返回订单项
+// 多个事务按相同顺序更新图书行，避免死锁
+// 参数:
+//
+//	items - 订单项列表
+//
+// 返回:
+//
+//	[]model.OrderItem - 排序后的订单项副本
+func sortedOrderItems(items []model.OrderItem) []model.OrderItem {
+	sorted := make([]model.OrderItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].BookID < sorted[j].BookID
+	})
+	return sorted
+}
 
-		// 更新图书库存和销售量
-		for _, item := range order.OrderItems {
-			if err := tx.Model(&model.Book{}).
-				Where("id = ?", item.BookID).
-				Updates(map[string]any{
-					"stock": gorm.Expr("stock - ?", item.Quantity),
-					"sale":  gorm.Expr("sale + ?", item.Quantity),
-				}).Error; err != nil {
-				return err
+// ChangeOrderStatus 按状态机变更订单状态
+// 用于管理员等场景的通用状态流转，非法流转会返回错误
+// 参数:
+//
+//	orderID - 订单ID
+//	status - 目标状态
+//	operator - 操作人类型
+//	operatorID - 操作人ID
+//	reason - 变更原因
+//
+// 返回:
+//
+//	error - 错误信息
+func (o *OrderService) ChangeOrderStatus(orderID, status int, operator string, operatorID int, reason string) error {
+	// 支付涉及库存扣减，只能通过支付流程完成
+	if status == model.OrderStatusPaid {
+		return errors.New("订单支付状态只能通过支付流程变更")
+	}
+	// 发货需要记录物流信息，只能通过发货接口完成
+	if status == model.OrderStatusShipped {
+		return errors.New("订单发货请通过发货接口录入物流信息")
+	}
+	// 退款涉及资金和库存，只能通过售后流程完成
+	if refundStatuses[status] {
+		return errors.New("订单退款状态只能通过售后流程变更")
+	}
+	// 取消需要释放预留库存和退回优惠券，与用户取消、超时取消走同一逻辑
+	if status == model.OrderStatusCancelled {
+		if reason == "" {
+			reason = "管理员取消"
+		}
+		return o.cancelOrder(orderID, operator, operatorID, reason)
+	}
+	return o.transitionOrder(orderID, status, operator, operatorID, reason,
+		func(tx *gorm.DB, order *model.Order) error {
+			if order.Status == model.OrderStatusRefunding {
+				return errors.New("订单售后处理中，请先审核售后申请")
 			}
+			return nil
+		})
+}
+
+// GetOrderList 获取订单列表（管理员）
+// 参数:
+//
+//	req - 订单列表请求对象指针
+//
+// 返回:
+//
+//	*model.OrderListResponse - 订单列表响应对象指针
+//	error - 如果参数无效或查询过程中出现错误则返回错误
+func (o *OrderService) GetOrderList(req *model.OrderListRequest) (*model.OrderListResponse, error) {
+	filter := &repository.OrderFilter{
+		Status:    req.Status,
+		UserID:    req.UserID,
+		Username:  req.Username,
+		OrderNo:   req.OrderNo,
+		MinAmount: req.MinAmount,
+		MaxAmount: req.MaxAmount,
+	}
+
+	if err := parseOrderDateRange(req.StartDate, req.EndDate, filter); err != nil {
+		return nil, err
+	}
+
+	orders, total, err := o.OrderDAO.GetOrdersForAdmin(filter, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转换为Order切片
+	orderList := make([]model.Order, 0, len(orders))
+	for _, order := range orders {
+		orderList = append(orderList, *order)
+	}
+
+	totalPage := int((total + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	return &model.OrderListResponse{
+		Orders:      orderList,
+		Total:       total,
+		TotalPage:   totalPage,
+		CurrentPage: req.Page,
+	}, nil
+}
+
+// parseOrderDateRange 解析下单日期范围并写入查询条件，结束日期包含当天
+// 参数:
+//
+//	startDate - 开始日期，格式2006-01-02，为空表示不限
+//	endDate - 结束日期，格式2006-01-02，为空表示不限
+//	filter - 订单查询条件
+//
+// 返回:
+//
+//	error - 日期格式错误时返回错误
+func parseOrderDateRange(startDate, endDate string, filter *repository.OrderFilter) error {
+	if startDate != "" {
+		start, err := time.ParseInLocation(time.DateOnly, startDate, time.Local)
+		if err != nil {
+			return errors.New("开始日期格式错误，应为YYYY-MM-DD")
 		}
-		return nil
-	})
-	return err
+		filter.StartTime = &start
+	}
+	if endDate != "" {
+		end, err := time.ParseInLocation(time.DateOnly, endDate, time.Local)
+		if err != nil {
+			return errors.New("结束日期格式错误，应为YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.EndTime = &end
+	}
+	return nil
+}
+
+// GetOrderByIDForAdmin 根据ID获取订单详情（管理员）
+// 参数:
+//
+//	id - 订单ID
+//
+// 返回:
+//
+//	*model.Order - 订单对象指针（包含用户、订单项、图书和状态变更记录）
+//	error - 错误信息
+func (o *OrderService) GetOrderByIDForAdmin(id int) (*model.Order, error) {
+	return o.OrderDAO.GetOrderByIDForAdmin(id)
 }
 
 // GetOrderStatistics 获取订单统计信息
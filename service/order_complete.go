@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// orderCompleteBatchSize 每轮扫描最多处理的超期订单数量
+const orderCompleteBatchSize = 100
+
+// OrderCompleteWorker 自动确认收货处理器
+// 在后台定期扫描发货后超过确认收货期限的订单并自动完成
+type OrderCompleteWorker struct {
+	orderService *OrderService      // 订单服务
+	after        time.Duration      // 最后一次发货后自动确认收货的期限
+	interval     time.Duration      // 扫描间隔
+	cancel       context.CancelFunc // 用于通知后台协程退出
+	wg           sync.WaitGroup     // 等待后台协程退出
+}
+
+// NewOrderCompleteWorker 创建新的自动确认收货处理器
+// 参数:
+//
+//	after - 最后一次发货后自动确认收货的期限
+//	interval - 扫描间隔
+//
+// 返回:
+//
+//	*OrderCompleteWorker - 初始化好的自动确认收货处理器
+func NewOrderCompleteWorker(after, interval time.Duration) *OrderCompleteWorker {
+	return &OrderCompleteWorker{
+		orderService: NewOrderService(),
+		after:        after,
+		interval:     interval,
+	}
+}
+
+// Start 启动后台扫描协程
+func (w *OrderCompleteWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		log.Printf("自动确认收货处理器已启动，确认期限: %v，扫描间隔: %v", w.after, w.interval)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.completeOrders(ctx)
+			}
+		}
+	}()
+}
+
+// Stop 停止后台扫描协程，并等待正在处理的订单完成
+func (w *OrderCompleteWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+	log.Println("自动确认收货处理器已停止")
+}
+
+// completeOrders 完成一批超过确认收货期限的订单
+// 参数:
+//
+//	ctx - 上下文，取消后不再处理剩余订单
+func (w *OrderCompleteWorker) completeOrders(ctx context.Context) {
+	ids, err := w.orderService.OrderDAO.GetOverdueShippedOrderIDs(time.Now().Add(-w.after), orderCompleteBatchSize)
+	if err != nil {
+		log.Printf("查询待自动确认收货订单失败: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		// 订单可能已被用户确认收货或进入售后，状态机会拒绝非法流转
+		if err := w.orderService.AutoCompleteOrder(id); err != nil {
+			log.Printf("自动确认收货失败，订单ID: %d，错误: %v", id, err)
+			continue
+		}
+		log.Printf("订单超过确认收货期限，已自动完成，订单ID: %d", id)
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// orderExpireBatchSize 每轮扫描最多处理的超时订单数量
+const orderExpireBatchSize = 100
+
+// OrderExpireWorker 超时订单处理器
+// 在后台定期扫描超时未支付的订单并自动取消
+type OrderExpireWorker struct {
+	orderService *OrderService      // 订单服务
+	timeout      time.Duration      // 未支付订单的过期时间
+	interval     time.Duration      // 扫描间隔
+	cancel       context.CancelFunc // 用于通知后台协程退出
+	wg           sync.WaitGroup     // 等待后台协程退出
+}
+
+// NewOrderExpireWorker 创建新的超时订单处理器
+// 参数:
+//
+//	timeout - 未支付订单的过期时间
+//	interval - 扫描间隔
+//
+// 返回:
+//
+//	*OrderExpireWorker - 初始化好的超时订单处理器
+func NewOrderExpireWorker(timeout, interval time.Duration) *OrderExpireWorker {
+	return &OrderExpireWorker{
+		orderService: NewOrderService(),
+		timeout:      timeout,
+		interval:     interval,
+	}
+}
+
+// Start 启动后台扫描协程
+func (w *OrderExpireWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		log.Printf("超时订单处理器已启动，过期时间: %v，扫描间隔: %v", w.timeout, w.interval)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.expireOrders(ctx)
+			}
+		}
+	}()
+}
+
+// Stop 停止后台扫描协程，并等待正在处理的订单完成
+func (w *OrderExpireWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+	log.Println("超时订单处理器已停止")
+}
+
+// expireOrders 取消一批超时未支付的订单
+// 参数:
+//
+//	ctx - 上下文，取消后不再处理剩余订单
+func (w *OrderExpireWorker) expireOrders(ctx context.Context) {
+	ids, err := w.orderService.OrderDAO.GetExpiredPendingOrderIDs(time.Now().Add(-w.timeout), orderExpireBatchSize)
+	if err != nil {
+		log.Printf("查询超时订单失败: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		// 订单可能已被用户支付或取消，状态机会拒绝非法流转
+		if err := w.orderService.ExpireOrder(id); err != nil {
+			log.Printf("取消超时订单失败，订单ID: %d，错误: %v", id, err)
+			continue
+		}
+		log.Printf("订单超时未支付，已自动取消，订单ID: %d", id)
+	}
+}
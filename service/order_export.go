@@ -0,0 +1,341 @@
+package service
+
+import (
+	"archive/zip"
+	"bookstore/model"
+	"bookstore/repository"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// orderExportBatchSize 导出时每批读取的订单数
+	orderExportBatchSize = 500
+	// maxOrderExportDays 单次导出允许的最大日期跨度（天）
+	maxOrderExportDays = 366
+)
+
+// orderExportHeader 导出文件的表头，每个订单项占一行，订单信息在每行重复
+var orderExportHeader = []any{
+	"订单号", "下单时间", "订单状态", "用户ID", "用户名", "邮箱",
+	"收货人", "收货电话", "收货地址", "支付时间",
+	"商品金额", "优惠金额", "优惠码", "实付金额", "已退款金额",
+	"图书ID", "书名", "ISBN", "单价", "数量", "小计", "分摊优惠", "已退数量",
+}
+
+// OrderExport 准备好的订单导出任务
+// 由PrepareOrderExport校验参数后创建，调用Stream时才开始查询并写出数据
+type OrderExport struct {
+	Filename    string // 建议的下载文件名
+	ContentType string // 导出文件的Content-Type
+
+	format   string                  // 导出格式：csv或xlsx
+	filter   *repository.OrderFilter // 订单查询条件
+	orderDAO *repository.OrderDAO    // 订单数据访问对象
+}
+
+// PrepareOrderExport 校验导出参数并创建导出任务（管理员）
+// 参数:
+//
+//	req - 订单导出请求
+//
+// 返回:
+//
+//	*OrderExport - 导出任务
+//	error - 参数无效时返回错误
+func (o *OrderService) PrepareOrderExport(req *model.OrderExportRequest) (*OrderExport, error) {
+	filter := &repository.OrderFilter{Status: req.Status}
+	if err := parseOrderDateRange(req.StartDate, req.EndDate, filter); err != nil {
+		return nil, err
+	}
+	days := int(filter.EndTime.Sub(*filter.StartTime).Hours() / 24)
+	if days <= 0 {
+		return nil, errors.New("结束日期不能早于开始日期")
+	}
+	if days > maxOrderExportDays {
+		return nil, fmt.Errorf("单次最多导出%d天的订单", maxOrderExportDays)
+	}
+
+	export := &OrderExport{
+		format:   req.Format,
+		filter:   filter,
+		orderDAO: o.OrderDAO,
+	}
+	if export.format == "" {
+		export.format = "csv"
+	}
+	name := fmt.Sprintf("orders_%s_%s", req.StartDate, req.EndDate)
+	if export.format == "xlsx" {
+		export.Filename = name + ".xlsx"
+		export.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	} else {
+		export.Filename = name + ".csv"
+		export.ContentType = "text/csv; charset=utf-8"
+	}
+	return export, nil
+}
+
+// Stream 按订单ID游标分批查询订单并写出导出文件
+// 每次只在内存中保留一批订单，每批写完后刷新输出，适合直接写入HTTP响应
+// 参数:
+//
+//	w - 导出文件的写入目标，实现Flush()时每批写完后调用
+//
+// 返回:
+//
+//	error - 查询或写入失败时返回错误，此时已写出的内容不完整
+func (e *OrderExport) Stream(w io.Writer) error {
+	var rows exportRowWriter
+	if e.format == "xlsx" {
+		rows = newXLSXRowWriter(w)
+	} else {
+		rows = newCSVRowWriter(w)
+	}
+	flusher, _ := w.(interface{ Flush() })
+
+	if err := rows.WriteRow(orderExportHeader); err != nil {
+		return err
+	}
+
+	afterID := 0
+	for {
+		orders, err := e.orderDAO.GetOrdersAfterID(e.filter, afterID, orderExportBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		for _, order := range orders {
+			for _, row := range orderExportRows(order) {
+				if err := rows.WriteRow(row); err != nil {
+					return err
+				}
+			}
+		}
+		afterID = orders[len(orders)-1].ID
+
+		if err := rows.Flush(); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if len(orders) < orderExportBatchSize {
+			break
+		}
+	}
+	return rows.Close()
+}
+
+// orderExportRows 将订单展开为导出行，每个订单项一行，没有订单项的订单输出一行订单信息
+// 参数:
+//
+//	order - 订单（包含用户、订单项和图书信息）
+//
+// 返回:
+//
+//	[][]any - 导出行，单元格为string或int
+func orderExportRows(order *model.Order) [][]any {
+	var username, email string
+	if order.User != nil {
+		username, email = order.User.Username, order.User.Email
+	}
+	paidAt := ""
+	if order.PaymentTime != nil {
+		paidAt = order.PaymentTime.Format(time.DateTime)
+	}
+	subtotal := order.SubtotalAmount
+	if subtotal == 0 {
+		// 早期订单没有商品金额字段
+		subtotal = order.TotalAmount + order.DiscountAmount
+	}
+
+	orderCells := []any{
+		order.OrderNo, order.CreatedAt.Format(time.DateTime), model.OrderStatusText(order.Status),
+		order.UserID, username, email,
+		order.ShippingAddress.ReceiverName, order.ShippingAddress.Phone, formatInvoiceAddress(order.ShippingAddress), paidAt,
+		subtotal, order.DiscountAmount, order.CouponCode, order.TotalAmount, order.RefundedAmount,
+	}
+	if len(order.OrderItems) == 0 {
+		return [][]any{append(orderCells, "", "", "", "", "", "", "", "")}
+	}
+
+	rows := make([][]any, 0, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		var title, isbn string
+		if item.Book != nil {
+			title, isbn = item.Book.Title, item.Book.ISBN
+		}
+		row := make([]any, 0, len(orderExportHeader))
+		row = append(row, orderCells...)
+		row = append(row, item.BookID, title, isbn, item.Price, item.Quantity, item.Subtotal, item.DiscountAmount, item.RefundedQuantity)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// exportRowWriter 导出文件的逐行写入器
+type exportRowWriter interface {
+	// WriteRow 写入一行，单元格为string或int
+	WriteRow(cells []any) error
+	// Flush 将已缓冲的行写出
+	Flush() error
+	// Close 写出文件结尾，之后不能再写入
+	Close() error
+}
+
+// csvRowWriter CSV格式的逐行写入器
+type csvRowWriter struct {
+	w      *csv.Writer
+	bomErr error // 写入BOM时的错误，在第一次写入行时返回
+}
+
+// newCSVRowWriter 创建CSV写入器，文件以UTF-8 BOM开头，便于Excel正确识别中文
+func newCSVRowWriter(w io.Writer) *csvRowWriter {
+	_, err := w.Write([]byte("\xEF\xBB\xBF"))
+	return &csvRowWriter{w: csv.NewWriter(w), bomErr: err}
+}
+
+// WriteRow 写入一行
+func (c *csvRowWriter) WriteRow(cells []any) error {
+	if c.bomErr != nil {
+		return c.bomErr
+	}
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = fmt.Sprint(cell)
+	}
+	return c.w.Write(record)
+}
+
+// Flush 将已缓冲的行写出
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Close 写出剩余的行
+func (c *csvRowWriter) Close() error {
+	return c.Flush()
+}
+
+// xlsxRowWriter XLSX格式的逐行写入器
+// 直接以流的方式写出只包含一个工作表的最小XLSX文件（ZIP压缩的SpreadsheetML），
+// 字符串使用内联字符串存储，不需要在内存中维护共享字符串表
+type xlsxRowWriter struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+	err   error // 创建文件结构时的错误，在第一次写入行时返回
+	row   int   // 已写入的行数
+	buf   bytes.Buffer
+}
+
+// xlsxStaticParts XLSX文件中除工作表以外的固定部分
+var xlsxStaticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="订单" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// newXLSXRowWriter 创建XLSX写入器，并写出固定部分和工作表的开头
+func newXLSXRowWriter(w io.Writer) *xlsxRowWriter {
+	x := &xlsxRowWriter{zw: zip.NewWriter(w)}
+	for _, part := range xlsxStaticParts {
+		f, err := x.zw.Create(part.name)
+		if err == nil {
+			_, err = io.WriteString(f, part.content)
+		}
+		if err != nil {
+			x.err = err
+			return x
+		}
+	}
+
+	f, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		x.err = err
+		return x
+	}
+	x.sheet = bufio.NewWriter(f)
+	_, x.err = x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x
+}
+
+// WriteRow 写入一行，int写为数值单元格，其余写为字符串单元格
+func (x *xlsxRowWriter) WriteRow(cells []any) error {
+	if x.err != nil {
+		return x.err
+	}
+	x.row++
+	x.buf.Reset()
+	x.buf.WriteString(`<row r="` + strconv.Itoa(x.row) + `">`)
+	for _, cell := range cells {
+		switch v := cell.(type) {
+		case int:
+			x.buf.WriteString(`<c><v>` + strconv.Itoa(v) + `</v></c>`)
+		default:
+			x.buf.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(&x.buf, []byte(fmt.Sprint(v))); err != nil {
+				return err
+			}
+			x.buf.WriteString(`</t></is></c>`)
+		}
+	}
+	x.buf.WriteString(`</row>`)
+	_, err := x.sheet.Write(x.buf.Bytes())
+	return err
+}
+
+// Flush 将已缓冲的行压缩后写出
+func (x *xlsxRowWriter) Flush() error {
+	if x.err != nil {
+		return x.err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Flush()
+}
+
+// Close 写出工作表结尾并结束ZIP文件
+func (x *xlsxRowWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+	if _, err := x.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"bookstore/config"
+	"bookstore/repository"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	orderNoPrefix   = "ORD" // 订单号前缀
+	paymentNoPrefix = "PAY" // 商户支付单号前缀
+	refundNoPrefix  = "RF"  // 售后单号前缀
+)
+
+// noSequences 各类单号按日递增序号使用的序列名，以单号前缀为键
+var noSequences = map[string]string{
+	orderNoPrefix:   "order_no",
+	paymentNoPrefix: "payment_no",
+	refundNoPrefix:  "refund_no",
+}
+
+// OrderNoGenerator 订单号生成器接口
+// 生成的单号由前缀、数字主体和一位校验位组成，在多进程、多实例间唯一且按生成时间递增；
+// 订单号、支付单号和售后单号使用相同的生成方式，仅前缀和序列不同
+type OrderNoGenerator interface {
+	// Generate 生成一个新的单号
+	Generate() (string, error)
+}
+
+var (
+	noGenerators   = make(map[string]OrderNoGenerator) // 进程内共享的单号生成器，以单号前缀为键
+	noGeneratorsMu sync.Mutex                          // 保护noGenerators
+)
+
+// getNoGenerator 根据配置获取指定前缀的单号生成器
+// 雪花算法生成器持有进程内的序号状态，因此每种单号在进程内只创建一次
+// 参数:
+//
+//	prefix - 单号前缀，须在noSequences中登记
+//
+// 返回:
+//
+//	OrderNoGenerator - 单号生成器
+func getNoGenerator(prefix string) OrderNoGenerator {
+	noGeneratorsMu.Lock()
+	defer noGeneratorsMu.Unlock()
+
+	if generator, ok := noGenerators[prefix]; ok {
+		return generator
+	}
+	var generator OrderNoGenerator
+	cfg := config.AppConfig.Order
+	switch cfg.NoGenerator {
+	case "snowflake":
+		generator = NewSnowflakeOrderNoGenerator(prefix, cfg.NodeID)
+	default:
+		generator = NewDailySequenceOrderNoGenerator(prefix, noSequences[prefix], repository.NewSequenceDAO())
+	}
+	noGenerators[prefix] = generator
+	return generator
+}
+
+// OrderSequencer 全局递增序号来源
+// 由repository.SequenceDAO基于Redis实现
+type OrderSequencer interface {
+	// Next 获取指定序列的下一个序号，从1开始
+	Next(name string, ttl time.Duration) (int64, error)
+}
+
+// DailySequenceOrderNoGenerator 按日递增序号的订单号生成器
+// 订单号格式为 ORD + 日期(yyyyMMdd) + 6位当日序号 + 校验位，如ORD202610160001238；
+// 序号由Redis全局递增，当日超过999999单时序号自动加长
+type DailySequenceOrderNoGenerator struct {
+	prefix    string           // 单号前缀
+	sequence  string           // 序列名，实际序列按日期区分
+	sequencer OrderSequencer   // 序号来源
+	now       func() time.Time // 当前时间，便于替换时钟
+}
+
+// NewDailySequenceOrderNoGenerator 创建按日递增序号的订单号生成器
+// 参数:
+//
+//	prefix - 单号前缀，如ORD
+//	sequence - 序列名，如order_no，不同单号必须使用不同的序列
+//	sequencer - 序号来源，通常为repository.SequenceDAO
+//
+// 返回:
+//
+//	*DailySequenceOrderNoGenerator - 订单号生成器
+func NewDailySequenceOrderNoGenerator(prefix, sequence string, sequencer OrderSequencer) *DailySequenceOrderNoGenerator {
+	return &DailySequenceOrderNoGenerator{
+		prefix:    prefix,
+		sequence:  sequence,
+		sequencer: sequencer,
+		now:       time.Now,
+	}
+}
+
+// Generate 生成一个新的订单号
+// 返回:
+//
+//	string - 订单号
+//	error - 获取序号失败时返回错误
+func (g *DailySequenceOrderNoGenerator) Generate() (string, error) {
+	day := g.now().Format("20060102")
+	// 序列保留到次日结束，足以覆盖跨零点的请求
+	seq, err := g.sequencer.Next(g.sequence+":"+day, 48*time.Hour)
+	if err != nil {
+		return "", fmt.Errorf("生成单号失败: %w", err)
+	}
+	return withCheckDigit(g.prefix, fmt.Sprintf("%s%06d", day, seq)), nil
+}
+
+const (
+	snowflakeNodeBits = 10                                   // 节点ID位数
+	snowflakeSeqBits  = 12                                   // 毫秒内序号位数
+	snowflakeMaxNode  = 1<<snowflakeNodeBits - 1             // 最大节点ID
+	snowflakeMaxSeq   = 1<<snowflakeSeqBits - 1              // 毫秒内最大序号
+	snowflakeEpochMs  = int64(1704067200000)                 // 起始时间 2024-01-01 00:00:00 UTC（毫秒）
+	snowflakeTimeBits = snowflakeNodeBits + snowflakeSeqBits // 时间戳左移位数
+)
+
+// SnowflakeOrderNoGenerator 雪花算法订单号生成器
+// 数字主体为 毫秒时间戳(自2024-01-01起) | 节点ID(10位) | 毫秒内序号(12位)，
+// 不依赖外部存储，多实例部署时通过不同的节点ID保证唯一；
+// 系统时钟回拨时沿用上一次的时间戳继续递增，不会产生重复的订单号
+type SnowflakeOrderNoGenerator struct {
+	mu     sync.Mutex       // 保护lastMs和seq
+	prefix string           // 单号前缀
+	nodeID int64            // 节点ID
+	lastMs int64            // 上一次生成时使用的时间戳
+	seq    int64            // 当前毫秒内的序号
+	now    func() time.Time // 当前时间，便于替换时钟
+}
+
+// NewSnowflakeOrderNoGenerator 创建雪花算法订单号生成器
+// 参数:
+//
+//	prefix - 单号前缀，如ORD
+//	nodeID - 节点ID（0-1023），超出范围时只保留低10位
+//
+// 返回:
+//
+//	*SnowflakeOrderNoGenerator - 订单号生成器
+func NewSnowflakeOrderNoGenerator(prefix string, nodeID int) *SnowflakeOrderNoGenerator {
+	return &SnowflakeOrderNoGenerator{
+		prefix: prefix,
+		nodeID: int64(nodeID) & snowflakeMaxNode,
+		now:    time.Now,
+	}
+}
+
+// Generate 生成一个新的订单号
+// 返回:
+//
+//	string - 订单号
+//	error - 始终为nil
+func (g *SnowflakeOrderNoGenerator) Generate() (string, error) {
+	g.mu.Lock()
+	ms := g.now().UnixMilli() - snowflakeEpochMs
+	if ms < g.lastMs {
+		ms = g.lastMs
+	}
+	if ms == g.lastMs {
+		g.seq = (g.seq + 1) & snowflakeMaxSeq
+		if g.seq == 0 {
+			// 当前毫秒内的序号已用完，借用下一毫秒
+			ms++
+		}
+	} else {
+		g.seq = 0
+	}
+	g.lastMs = ms
+	id := ms<<snowflakeTimeBits | g.nodeID<<snowflakeSeqBits | g.seq
+	g.mu.Unlock()
+
+	return withCheckDigit(g.prefix, strconv.FormatInt(id, 10)), nil
+}
+
+// withCheckDigit 在数字主体后追加Luhn校验位
+// 参数:
+//
+//	prefix - 订单号前缀
+//	digits - 数字主体
+//
+// 返回:
+//
+//	string - 完整的订单号
+func withCheckDigit(prefix, digits string) string {
+	return prefix + digits + strconv.Itoa(luhnCheckDigit(digits))
+}
+
+// luhnCheckDigit 计算数字串的Luhn校验位
+// 可以发现单个数字错误和绝大多数相邻数字颠倒，便于人工录入订单号时提前发现错误
+// 参数:
+//
+//	digits - 仅包含0-9的数字串
+//
+// 返回:
+//
+//	int - 校验位（0-9）
+func luhnCheckDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+// ValidOrderNo 校验订单号的格式和校验位
+// 参数:
+//
+//	orderNo - 订单号
+//
+// 返回:
+//
+//	bool - 格式正确且校验位匹配时返回true
+func ValidOrderNo(orderNo string) bool {
+	if len(orderNo) < len(orderNoPrefix)+2 || orderNo[:len(orderNoPrefix)] != orderNoPrefix {
+		return false
+	}
+	body := orderNo[len(orderNoPrefix):]
+	for i := 0; i < len(body); i++ {
+		if body[i] < '0' || body[i] > '9' {
+			return false
+		}
+	}
+	digits, check := body[:len(body)-1], int(body[len(body)-1]-'0')
+	return luhnCheckDigit(digits) == check
+}
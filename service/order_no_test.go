@@ -0,0 +1,329 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// snowflakeID 从订单号中取出雪花算法的数字主体
+func snowflakeID(t *testing.T, orderNo string) int64 {
+	t.Helper()
+	if !ValidOrderNo(orderNo) {
+		t.Fatalf("invalid order no %q", orderNo)
+	}
+	id, err := strconv.ParseInt(orderNo[len(orderNoPrefix):len(orderNo)-1], 10, 64)
+	if err != nil {
+		t.Fatalf("parse order no %q: %v", orderNo, err)
+	}
+	return id
+}
+
+// decodeSnowflake 拆分雪花算法ID为时间戳、节点ID和序号
+func decodeSnowflake(id int64) (ms, node, seq int64) {
+	return id >> snowflakeTimeBits, id >> snowflakeSeqBits & snowflakeMaxNode, id & snowflakeMaxSeq
+}
+
+// fixedClock 返回可手动调整的时钟
+func fixedClock(t time.Time) (func() time.Time, func(time.Duration)) {
+	var mu sync.Mutex
+	now := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return t
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		t = t.Add(d)
+	}
+	return now, advance
+}
+
+func TestSnowflakeGenerateConcurrent(t *testing.T) {
+	const workers, perWorker = 32, 2000
+
+	g := NewSnowflakeOrderNoGenerator(orderNoPrefix, 7)
+	results := make([][]string, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			nos := make([]string, 0, perWorker)
+			for i := 0; i < perWorker; i++ {
+				no, err := g.Generate()
+				if err != nil {
+					t.Errorf("Generate: %v", err)
+					return
+				}
+				nos = append(nos, no)
+			}
+			results[w] = nos
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, workers*perWorker)
+	for w, nos := range results {
+		var prev int64 = -1
+		for _, no := range nos {
+			if seen[no] {
+				t.Fatalf("duplicate order no %q", no)
+			}
+			seen[no] = true
+
+			// 同一goroutine先后生成的订单号必须递增
+			id := snowflakeID(t, no)
+			if id <= prev {
+				t.Fatalf("worker %d: order no not increasing: %d after %d", w, id, prev)
+			}
+			prev = id
+
+			if _, node, _ := decodeSnowflake(id); node != 7 {
+				t.Fatalf("node id = %d, want 7", node)
+			}
+		}
+	}
+	if len(seen) != workers*perWorker {
+		t.Fatalf("generated %d order nos, want %d", len(seen), workers*perWorker)
+	}
+}
+
+func TestSnowflakeSameMillisecond(t *testing.T) {
+	now, _ := fixedClock(time.UnixMilli(snowflakeEpochMs + 1000))
+	g := NewSnowflakeOrderNoGenerator(orderNoPrefix, 1)
+	g.now = now
+
+	for want := int64(0); want < 3; want++ {
+		no, _ := g.Generate()
+		ms, _, seq := decodeSnowflake(snowflakeID(t, no))
+		if ms != 1000 || seq != want {
+			t.Fatalf("got ms=%d seq=%d, want ms=1000 seq=%d", ms, seq, want)
+		}
+	}
+}
+
+func TestSnowflakeSequenceOverflow(t *testing.T) {
+	now, advance := fixedClock(time.UnixMilli(snowflakeEpochMs + 1000))
+	g := NewSnowflakeOrderNoGenerator(orderNoPrefix, 1)
+	g.now = now
+
+	// 用完当前毫秒内的全部序号
+	var prev int64 = -1
+	for i := 0; i <= snowflakeMaxSeq; i++ {
+		no, _ := g.Generate()
+		id := snowflakeID(t, no)
+		if id <= prev {
+			t.Fatalf("order no not increasing: %d after %d", id, prev)
+		}
+		prev = id
+	}
+
+	// 序号溢出后借用下一毫秒，时钟未前进时继续沿用借用的毫秒
+	for want := int64(0); want < 2; want++ {
+		no, _ := g.Generate()
+		id := snowflakeID(t, no)
+		ms, _, seq := decodeSnowflake(id)
+		if ms != 1001 || seq != want {
+			t.Fatalf("got ms=%d seq=%d, want ms=1001 seq=%d", ms, seq, want)
+		}
+		if id <= prev {
+			t.Fatalf("order no not increasing: %d after %d", id, prev)
+		}
+		prev = id
+	}
+
+	// 时钟追上借用的毫秒后序号继续递增，而不是从0重新开始
+	advance(time.Millisecond)
+	no, _ := g.Generate()
+	if ms, _, seq := decodeSnowflake(snowflakeID(t, no)); ms != 1001 || seq != 2 {
+		t.Fatalf("got ms=%d seq=%d, want ms=1001 seq=2", ms, seq)
+	}
+}
+
+func TestSnowflakeClockBackwards(t *testing.T) {
+	now, advance := fixedClock(time.UnixMilli(snowflakeEpochMs + 5000))
+	g := NewSnowflakeOrderNoGenerator(orderNoPrefix, 1)
+	g.now = now
+
+	first, _ := g.Generate()
+	advance(-time.Second)
+	second, _ := g.Generate()
+
+	if snowflakeID(t, second) <= snowflakeID(t, first) {
+		t.Fatalf("order no not increasing after clock rollback: %s then %s", first, second)
+	}
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   int
+	}{
+		{"7992739871", 3},
+		{"0", 0},
+		{"20261016000001", 6},
+	}
+	for _, tt := range tests {
+		if got := luhnCheckDigit(tt.digits); got != tt.want {
+			t.Errorf("luhnCheckDigit(%q) = %d, want %d", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestValidOrderNo(t *testing.T) {
+	// 配置文件和注释中的示例订单号
+	no := "ORD202610160001238"
+	if withCheckDigit(orderNoPrefix, "20261016000123") != no || !ValidOrderNo(no) {
+		t.Fatalf("ValidOrderNo(%q) = false, want true", no)
+	}
+
+	// 任意一位数字被改动都应被校验位发现
+	for i := len(orderNoPrefix); i < len(no); i++ {
+		for d := byte('0'); d <= '9'; d++ {
+			if no[i] == d {
+				continue
+			}
+			changed := no[:i] + string(d) + no[i+1:]
+			if ValidOrderNo(changed) {
+				t.Fatalf("ValidOrderNo(%q) = true after changing digit %d", changed, i)
+			}
+		}
+	}
+
+	for _, invalid := range []string{"", "ORD", "ORD1", "XYZ202610160001232", "ORD2026101600012a2", strings.ToLower(no)} {
+		if ValidOrderNo(invalid) {
+			t.Errorf("ValidOrderNo(%q) = true, want false", invalid)
+		}
+	}
+}
+
+// fakeSequencer 进程内的序号来源，记录最后一次调用的序列名和保留时间
+type fakeSequencer struct {
+	mu   sync.Mutex
+	seqs map[string]int64
+	name string
+	ttl  time.Duration
+	err  error
+}
+
+func (f *fakeSequencer) Next(name string, ttl time.Duration) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return 0, f.err
+	}
+	if f.seqs == nil {
+		f.seqs = make(map[string]int64)
+	}
+	f.seqs[name]++
+	f.name, f.ttl = name, ttl
+	return f.seqs[name], nil
+}
+
+func TestDailySequenceFormat(t *testing.T) {
+	seq := &fakeSequencer{}
+	now, advance := fixedClock(time.Date(2026, 10, 16, 23, 59, 59, 0, time.Local))
+	g := NewDailySequenceOrderNoGenerator(orderNoPrefix, "order_no", seq)
+	g.now = now
+
+	no, err := g.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if want := "ORD202610160000016"; no != want {
+		t.Fatalf("Generate() = %q, want %q", no, want)
+	}
+	if seq.name != "order_no:20261016" || seq.ttl != 48*time.Hour {
+		t.Fatalf("sequence = %q ttl %v, want order_no:20261016 ttl 48h", seq.name, seq.ttl)
+	}
+
+	no, _ = g.Generate()
+	if !strings.HasPrefix(no, "ORD20261016000002") || !ValidOrderNo(no) {
+		t.Fatalf("second order no = %q", no)
+	}
+
+	// 跨零点后使用新的日期和序列
+	advance(time.Second)
+	no, _ = g.Generate()
+	if !strings.HasPrefix(no, "ORD20261017000001") || !ValidOrderNo(no) {
+		t.Fatalf("next day order no = %q", no)
+	}
+
+	// 当日序号超过6位时自动加长
+	seq.seqs["order_no:20261017"] = 999999
+	no, _ = g.Generate()
+	if !strings.HasPrefix(no, "ORD202610171000000") || len(no) != len("ORD202610171000000")+1 || !ValidOrderNo(no) {
+		t.Fatalf("overflow order no = %q", no)
+	}
+}
+
+func TestDailySequenceConcurrent(t *testing.T) {
+	const workers, perWorker = 16, 500
+
+	g := NewDailySequenceOrderNoGenerator(orderNoPrefix, "order_no", &fakeSequencer{})
+
+	var mu sync.Mutex
+	seen := make(map[string]bool, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				no, err := g.Generate()
+				if err != nil {
+					t.Errorf("Generate: %v", err)
+					return
+				}
+				mu.Lock()
+				if seen[no] {
+					t.Errorf("duplicate order no %q", no)
+				}
+				seen[no] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != workers*perWorker {
+		t.Fatalf("generated %d order nos, want %d", len(seen), workers*perWorker)
+	}
+}
+
+func TestDailySequenceError(t *testing.T) {
+	g := NewDailySequenceOrderNoGenerator(orderNoPrefix, "order_no", &fakeSequencer{err: errors.New("redis down")})
+	if no, err := g.Generate(); err == nil {
+		t.Fatalf("Generate() = %q, want error", no)
+	}
+}
+
+func TestNoGeneratorPrefix(t *testing.T) {
+	seq := &fakeSequencer{}
+	now, _ := fixedClock(time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local))
+	daily := NewDailySequenceOrderNoGenerator(paymentNoPrefix, "payment_no", seq)
+	daily.now = now
+
+	no, err := daily.Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if want := withCheckDigit(paymentNoPrefix, "20261016000001"); no != want {
+		t.Fatalf("Generate() = %q, want %q", no, want)
+	}
+	// 不同单号使用各自的序列，互不占用序号
+	if seq.name != "payment_no:20261016" {
+		t.Fatalf("sequence = %q, want payment_no:20261016", seq.name)
+	}
+
+	no, _ = NewSnowflakeOrderNoGenerator(refundNoPrefix, 1).Generate()
+	if !strings.HasPrefix(no, refundNoPrefix) || ValidOrderNo(no) {
+		t.Fatalf("snowflake refund no = %q", no)
+	}
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"bookstore/config"
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// PaymentService 支付服务
+// 负责通过支付渠道发起支付、处理支付回调并在验签通过后将订单标记为已支付
+type PaymentService struct {
+	PaymentDAO         *repository.PaymentDAO // 支付单数据访问对象
+	OrderDAO           *repository.OrderDAO   // 订单数据访问对象
+	orderService       *OrderService          // 订单服务，用于执行订单状态流转
+	paymentNoGenerator OrderNoGenerator       // 商户支付单号生成器
+}
+
+// CreatePaymentResponse 发起支付响应
+type CreatePaymentResponse struct {
+	PaymentNo     string `json:"payment_no"`     // 商户支付单号
+	Provider      string `json:"provider"`       // 支付渠道
+	TransactionID string `json:"transaction_id"` // 支付渠道交易号
+	PayURL        string `json:"pay_url"`        // 用户完成支付的地址
+	Amount        int    `json:"amount"`         // 支付金额（元）
+}
+
+// NewPaymentService 创建新的支付服务实例
+// 返回:
+//
+//	*PaymentService - 初始化好的支付服务
+func NewPaymentService() *PaymentService {
+	return &PaymentService{
+		PaymentDAO:         repository.NewPaymentDAO(),
+		OrderDAO:           repository.NewOrderDAO(),
+		orderService:       NewOrderService(),
+		paymentNoGenerator: getNoGenerator(paymentNoPrefix),
+	}
+}
+
+// CreatePayment 为订单发起支付
+// 只有订单所有者可以为待支付订单发起支付，订单在收到验签通过的支付回调后才会标记为已支付
+// 参数:
+//
+//	orderID - 订单ID
+//	userID - 当前用户ID
+//
+// 返回:
+//
+//	*CreatePaymentResponse - 支付单号、交易号和支付地址
+//	error - 错误信息
+func (p *PaymentService) CreatePayment(orderID, userID int) (*CreatePaymentResponse, error) {
+	order, err := p.OrderDAO.GetOrderByID(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return nil, errors.New("无权操作该订单")
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil, fmt.Errorf("订单当前状态为「%s」，无法支付", model.OrderStatusText(order.Status))
+	}
+
+	provider, err := getPaymentProvider(config.AppConfig.Payment.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentNo, err := p.paymentNoGenerator.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	// 先落库支付单，保证回调到达时能找到对应记录
+	payment := &model.Payment{
+		PaymentNo: paymentNo,
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		Provider:  provider.Name(),
+		Amount:    order.TotalAmount,
+		Status:    model.PaymentStatusPending,
+	}
+	if err := p.PaymentDAO.CreatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	result, err := provider.CreatePayment(&PaymentRequest{
+		PaymentNo: payment.PaymentNo,
+		OrderNo:   order.OrderNo,
+		Amount:    payment.Amount,
+		Subject:   fmt.Sprintf("订单%s", order.OrderNo),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("发起支付失败: %v", err)
+	}
+
+	payment.TransactionID = result.TransactionID
+	if err := p.PaymentDAO.UpdatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	return &CreatePaymentResponse{
+		PaymentNo:     payment.PaymentNo,
+		Provider:      payment.Provider,
+		TransactionID: payment.TransactionID,
+		PayURL:        result.PayURL,
+		Amount:        payment.Amount,
+	}, nil
+}
+
+// HandleCallback 处理支付渠道的支付回调
+// 校验签名后更新支付单并保存原始报文，支付成功时在同一事务中将订单标记为已支付；
+// 重复回调直接返回成功，保证幂等
+// 参数:
+//
+//	providerName - 支付渠道名称
+//	payload - 回调原始报文
+//	signature - 回调签名
+//
+// 返回:
+//
+//	error - 验签失败、金额不符或订单状态不允许支付时返回错误
+func (p *PaymentService) HandleCallback(providerName string, payload []byte, signature string) error {
+	provider, err := getPaymentProvider(providerName)
+	if err != nil {
+		return err
+	}
+
+	callback, err := provider.VerifyCallback(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	payment, err := p.PaymentDAO.GetPaymentByPaymentNo(callback.PaymentNo)
+	if err != nil {
+		return errors.New("支付单不存在")
+	}
+	if payment.Provider != provider.Name() {
+		return errors.New("支付渠道不匹配")
+	}
+	if payment.Status == model.PaymentStatusSuccess || payment.Status == model.PaymentStatusRefunded {
+		return nil
+	}
+
+	// 支付失败只更新支付单，订单保持待支付
+	if !callback.Success {
+		return global.DBClient.Transaction(func(tx *gorm.DB) error {
+			paymentDAO := p.PaymentDAO.WithTx(tx)
+			locked, err := paymentDAO.GetPaymentForUpdate(callback.PaymentNo)
+			if err != nil {
+				return err
+			}
+			if locked.Status != model.PaymentStatusPending {
+				return nil
+			}
+			locked.Status = model.PaymentStatusFailed
+			locked.CallbackPayload = string(payload)
+			return paymentDAO.UpdatePayment(locked)
+		})
+	}
+
+	reason := fmt.Sprintf("支付成功，渠道: %s，交易号: %s", provider.Name(), callback.TransactionID)
+	err = p.orderService.markOrderPaid(payment.OrderID, reason, func(tx *gorm.DB, order *model.Order) error {
+		paymentDAO := p.PaymentDAO.WithTx(tx)
+		locked, err := paymentDAO.GetPaymentForUpdate(callback.PaymentNo)
+		if err != nil {
+			return err
+		}
+		if callback.Amount != order.TotalAmount {
+			return fmt.Errorf("支付金额%d与订单金额%d不符", callback.Amount, order.TotalAmount)
+		}
+
+		paidAt := callback.PaidAt
+		locked.Status = model.PaymentStatusSuccess
+		locked.TransactionID = callback.TransactionID
+		locked.CallbackPayload = string(payload)
+		locked.PaidAt = &paidAt
+		return paymentDAO.UpdatePayment(locked)
+	})
+
+	// 回调到达前订单已不是待支付状态，如已超时取消，或同一订单的另一笔支付已先成功，
+	// 渠道已扣款，需要原路退回
+	var transitionErr *OrderTransitionError
+	if errors.As(err, &transitionErr) {
+		return p.refundOrphanedPayment(provider, callback, payload)
+	}
+	return err
+}
+
+// refundOrphanedPayment 退回订单已取消或已由其他支付单支付后才支付成功的款项
+// 先将支付单标记为待退回并提交，再向支付渠道发起全额退款，退款成功后标记为已全额退款；
+// 退款失败时支付单保持待退回状态供对账，并返回错误让支付渠道重试回调，重试时以支付单号作为退款单号保证幂等
+// 参数:
+//
+//	provider - 支付渠道
+//	callback - 验签通过的支付回调内容
+//	payload - 回调原始报文
+//
+// 返回:
+//
+//	error - 错误信息
+func (p *PaymentService) refundOrphanedPayment(provider PaymentProvider, callback *PaymentCallback, payload []byte) error {
+	var payment *model.Payment
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		paymentDAO := p.PaymentDAO.WithTx(tx)
+		locked, err := paymentDAO.GetPaymentForUpdate(callback.PaymentNo)
+		if err != nil {
+			return err
+		}
+		payment = locked
+		if locked.Status == model.PaymentStatusOrphaned || locked.Status == model.PaymentStatusRefunded {
+			return nil
+		}
+
+		paidAt := callback.PaidAt
+		locked.Status = model.PaymentStatusOrphaned
+		locked.TransactionID = callback.TransactionID
+		locked.Amount = callback.Amount
+		locked.CallbackPayload = string(payload)
+		locked.PaidAt = &paidAt
+		return paymentDAO.UpdatePayment(locked)
+	})
+	if err != nil {
+		return err
+	}
+	if payment.Status == model.PaymentStatusRefunded {
+		return nil
+	}
+
+	result, err := provider.Refund(&PaymentRefundRequest{
+		RefundNo:      payment.PaymentNo,
+		PaymentNo:     payment.PaymentNo,
+		TransactionID: payment.TransactionID,
+		Amount:        payment.Amount,
+		TotalAmount:   payment.Amount,
+	})
+	if err != nil {
+		log.Printf("订单%d已不是待支付状态，支付单%s退款失败，待对账处理: %v", payment.OrderID, payment.PaymentNo, err)
+		return fmt.Errorf("订单已不是待支付状态，支付款项退回失败: %v", err)
+	}
+	log.Printf("订单%d已不是待支付状态，支付单%s已原路退回，退款交易号: %s", payment.OrderID, payment.PaymentNo, result.RefundTransactionID)
+
+	payment.Status = model.PaymentStatusRefunded
+	payment.RefundedAmount = payment.Amount
+	return p.PaymentDAO.UpdatePayment(payment)
+}
+
+// QueryPayment 查询支付单状态
+// 支付单待支付时会向支付渠道主动查询，但订单状态仍只由支付回调驱动
+// 参数:
+//
+//	paymentNo - 支付单号
+//	userID - 当前用户ID
+//
+// 返回:
+//
+//	*model.Payment - 支付单
+//	*PaymentResult - 支付渠道返回的查询结果，支付单已完结时为nil
+//	error - 错误信息
+func (p *PaymentService) QueryPayment(paymentNo string, userID int) (*model.Payment, *PaymentResult, error) {
+	payment, err := p.PaymentDAO.GetPaymentByPaymentNo(paymentNo)
+	if err != nil {
+		return nil, nil, errors.New("支付单不存在")
+	}
+	if payment.UserID != userID {
+		return nil, nil, errors.New("无权查看该支付单")
+	}
+	if payment.Status != model.PaymentStatusPending || payment.TransactionID == "" {
+		return payment, nil, nil
+	}
+
+	provider, err := getPaymentProvider(payment.Provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := provider.QueryPayment(payment.TransactionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询支付渠道失败: %v", err)
+	}
+	return payment, result, nil
+}
+
+// CompleteMockPayment 模拟用户在模拟收银台完成支付
+// 只有支付单所属用户可以完成支付，由模拟渠道生成签名回调，再走与真实回调相同的验签和入账流程
+// 参数:
+//
+//	transactionID - 模拟渠道交易号
+//	userID - 当前用户ID
+//
+// 返回:
+//
+//	error - 模拟渠道未启用、无权操作或回调处理失败时返回错误
+func (p *PaymentService) CompleteMockPayment(transactionID string, userID int) error {
+	provider, err := getPaymentProvider(MockPaymentProviderName)
+	if err != nil {
+		return err
+	}
+	mock, ok := provider.(*MockPaymentProvider)
+	if !ok {
+		return errors.New("模拟支付渠道未启用")
+	}
+
+	payment, err := p.PaymentDAO.GetPaymentByTransactionID(MockPaymentProviderName, transactionID)
+	if err != nil {
+		return errors.New("交易不存在")
+	}
+	if payment.UserID != userID {
+		return errors.New("无权操作该支付单")
+	}
+
+	payload, signature, err := mock.Complete(transactionID)
+	if err != nil {
+		return err
+	}
+	if err := p.HandleCallback(MockPaymentProviderName, payload, signature); err != nil {
+		return err
+	}
+
+	// 订单已取消或已支付时款项会被原路退回，需要告知用户本次支付未生效
+	payment, err = p.PaymentDAO.GetPaymentByPaymentNo(payment.PaymentNo)
+	if err != nil {
+		return err
+	}
+	if payment.Status != model.PaymentStatusSuccess {
+		return errors.New("订单已取消或已支付，本次支付款项已原路退回")
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MockPaymentProviderName 模拟支付渠道名称
+const MockPaymentProviderName = "mock"
+
+// mockTransaction 模拟支付渠道内部的交易记录
+type mockTransaction struct {
+	PaymentNo string    // 商户支付单号
+	Amount    int       // 支付金额（元）
+	Paid      bool      // 是否已支付
+	PaidAt    time.Time // 支付时间
+	Refunded  int       // 已退款金额（元）
+}
+
+// mockCallbackPayload 模拟支付渠道的回调报文
+type mockCallbackPayload struct {
+	PaymentNo     string `json:"payment_no"`     // 商户支付单号
+	TransactionID string `json:"transaction_id"` // 渠道交易号
+	Amount        int    `json:"amount"`         // 支付金额（元）
+	Status        string `json:"status"`         // 支付状态：SUCCESS/FAILED
+	PaidAt        int64  `json:"paid_at"`        // 支付时间（Unix秒）
+}
+
+// MockPaymentProvider 本地模拟支付渠道
+// 不依赖任何外部服务，交易记录保存在内存中，回调报文使用HMAC-SHA256签名，
+// 用于开发和测试环境离线跑通完整的支付流程
+type MockPaymentProvider struct {
+	secret       []byte                      // 回调签名密钥
+	mu           sync.Mutex                  // 保护交易记录和退款记录
+	transactions map[string]*mockTransaction // 以交易号为键的交易记录
+	refunds      map[string]string           // 以商户退款单号为键的退款交易号，保证重复退款请求幂等
+}
+
+// NewMockPaymentProvider 创建新的模拟支付渠道
+// 参数:
+//
+//	secret - 回调签名密钥
+//
+// 返回:
+//
+//	*MockPaymentProvider - 初始化好的模拟支付渠道
+func NewMockPaymentProvider(secret string) *MockPaymentProvider {
+	return &MockPaymentProvider{
+		secret:       []byte(secret),
+		transactions: make(map[string]*mockTransaction),
+		refunds:      make(map[string]string),
+	}
+}
+
+// Name 返回支付渠道名称
+func (m *MockPaymentProvider) Name() string {
+	return MockPaymentProviderName
+}
+
+// CreatePayment 在模拟渠道创建交易
+// 返回的支付地址指向模拟收银台，调用后即视为用户完成支付
+// 参数:
+//
+//	req - 发起支付请求
+//
+// 返回:
+//
+//	*PaymentResult - 交易号和支付地址
+//	error - 错误信息
+func (m *MockPaymentProvider) CreatePayment(req *PaymentRequest) (*PaymentResult, error) {
+	transactionID := fmt.Sprintf("MOCK%d", time.Now().UnixNano())
+
+	m.mu.Lock()
+	m.transactions[transactionID] = &mockTransaction{
+		PaymentNo: req.PaymentNo,
+		Amount:    req.Amount,
+	}
+	m.mu.Unlock()
+
+	return &PaymentResult{
+		TransactionID: transactionID,
+		PayURL:        fmt.Sprintf("/api/v1/payment/mock/%s/complete", transactionID),
+	}, nil
+}
+
+// QueryPayment 查询模拟渠道的交易结果
+// 参数:
+//
+//	transactionID - 交易号
+//
+// 返回:
+//
+//	*PaymentResult - 交易结果
+//	error - 交易不存在时返回错误
+func (m *MockPaymentProvider) QueryPayment(transactionID string) (*PaymentResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	txn, ok := m.transactions[transactionID]
+	if !ok {
+		return nil, errors.New("交易不存在")
+	}
+	return &PaymentResult{
+		TransactionID: transactionID,
+		Paid:          txn.Paid,
+	}, nil
+}
+
+// VerifyCallback 校验回调签名并解析回调内容
+// 参数:
+//
+//	payload - 回调原始报文
+//	signature - 报文的十六进制HMAC-SHA256签名
+//
+// 返回:
+//
+//	*PaymentCallback - 回调内容
+//	error - 签名无效或报文格式错误时返回错误
+func (m *MockPaymentProvider) VerifyCallback(payload []byte, signature string) (*PaymentCallback, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, m.sign(payload)) {
+		return nil, errors.New("回调签名无效")
+	}
+
+	var body mockCallbackPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("回调报文格式错误: %v", err)
+	}
+
+	return &PaymentCallback{
+		PaymentNo:     body.PaymentNo,
+		TransactionID: body.TransactionID,
+		Amount:        body.Amount,
+		Success:       body.Status == "SUCCESS",
+		PaidAt:        time.Unix(body.PaidAt, 0),
+	}, nil
+}
+
+// Refund 在模拟渠道发起退款
+// 同一退款单号重复请求时直接返回首次的退款交易号，不会重复退款；
+// 交易记录只保存在内存中，服务重启前创建的交易无法校验累计退款金额，此时仅校验本次金额
+// 参数:
+//
+//	req - 退款请求
+//
+// 返回:
+//
+//	*PaymentRefundResult - 退款交易号
+//	error - 退款金额无效时返回错误
+func (m *MockPaymentProvider) Refund(req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	if req.Amount <= 0 || req.Amount > req.TotalAmount {
+		return nil, errors.New("退款金额无效")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if refundTransactionID, ok := m.refunds[req.RefundNo]; ok {
+		return &PaymentRefundResult{RefundTransactionID: refundTransactionID}, nil
+	}
+
+	if txn, ok := m.transactions[req.TransactionID]; ok {
+		if !txn.Paid {
+			return nil, errors.New("交易未支付，无法退款")
+		}
+		if txn.Refunded+req.Amount > txn.Amount {
+			return nil, errors.New("累计退款金额超过支付金额")
+		}
+		txn.Refunded += req.Amount
+	}
+
+	refundTransactionID := fmt.Sprintf("MOCKREFUND%d", time.Now().UnixNano())
+	m.refunds[req.RefundNo] = refundTransactionID
+	return &PaymentRefundResult{
+		RefundTransactionID: refundTransactionID,
+	}, nil
+}
+
+// Complete 模拟用户在收银台完成支付
+// 将交易标记为已支付，并生成带签名的回调报文，调用方应将其交给回调处理流程
+// 参数:
+//
+//	transactionID - 交易号
+//
+// 返回:
+//
+//	[]byte - 回调原始报文
+//	string - 报文签名
+//	error - 交易不存在时返回错误
+func (m *MockPaymentProvider) Complete(transactionID string) ([]byte, string, error) {
+	m.mu.Lock()
+	txn, ok := m.transactions[transactionID]
+	if !ok {
+		m.mu.Unlock()
+		return nil, "", errors.New("交易不存在")
+	}
+	if !txn.Paid {
+		txn.Paid = true
+		txn.PaidAt = time.Now()
+	}
+	body := mockCallbackPayload{
+		PaymentNo:     txn.PaymentNo,
+		TransactionID: transactionID,
+		Amount:        txn.Amount,
+		Status:        "SUCCESS",
+		PaidAt:        txn.PaidAt.Unix(),
+	}
+	m.mu.Unlock()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, hex.EncodeToString(m.sign(payload)), nil
+}
+
+// sign 计算报文的HMAC-SHA256签名
+func (m *MockPaymentProvider) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bookstore/config"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PaymentRequest 发起支付请求
+// 由支付服务构造并传给支付渠道
+type PaymentRequest struct {
+	PaymentNo string // 商户支付单号
+	OrderNo   string // 订单号
+	Amount    int    // 支付金额（元）
+	Subject   string // 支付标题
+}
+
+// PaymentResult 支付渠道返回的支付信息
+type PaymentResult struct {
+	TransactionID string `json:"transaction_id"` // 支付渠道交易号
+	PayURL        string `json:"pay_url"`        // 用户完成支付的地址
+	Paid          bool   `json:"paid"`           // 是否已支付成功
+}
+
+// PaymentCallback 验签通过后的支付回调内容
+type PaymentCallback struct {
+	PaymentNo     string    // 商户支付单号
+	TransactionID string    // 支付渠道交易号
+	Amount        int       // 实际支付金额（元）
+	Success       bool      // 是否支付成功
+	PaidAt        time.Time // 支付完成时间
+}
+
+// PaymentRefundRequest 向支付渠道发起退款的请求
+type PaymentRefundRequest struct {
+	RefundNo      string // 商户售后单号，用于渠道侧幂等
+	PaymentNo     string // 原商户支付单号
+	TransactionID string // 原支付渠道交易号
+	Amount        int    // 本次退款金额（元）
+	TotalAmount   int    // 原支付金额（元）
+}
+
+// PaymentRefundResult 支付渠道返回的退款结果
+type PaymentRefundResult struct {
+	RefundTransactionID string // 支付渠道退款交易号
+}
+
+// PaymentProvider 支付渠道接口
+// 每个支付渠道（如模拟渠道、第三方支付）实现该接口后注册到支付服务
+type PaymentProvider interface {
+	// Name 返回支付渠道名称，用于配置和回调路由
+	Name() string
+	// CreatePayment 向支付渠道下单，返回交易号和支付地址
+	CreatePayment(req *PaymentRequest) (*PaymentResult, error)
+	// QueryPayment 根据交易号主动查询支付结果
+	QueryPayment(transactionID string) (*PaymentResult, error)
+	// VerifyCallback 校验回调签名并解析回调内容，签名无效时返回错误
+	VerifyCallback(payload []byte, signature string) (*PaymentCallback, error)
+	// Refund 对已支付的交易发起（部分）退款，同一退款单号重复请求时不得重复退款
+	Refund(req *PaymentRefundRequest) (*PaymentRefundResult, error)
+}
+
+var (
+	paymentProviders     = make(map[string]PaymentProvider) // 已注册的支付渠道
+	paymentProvidersOnce sync.Once                          // 保证支付渠道只注册一次
+)
+
+// registerPaymentProviders 根据配置注册所有可用的支付渠道
+// 支付渠道可能持有内部状态（如模拟渠道的交易记录），因此在进程内只创建一次
+func registerPaymentProviders() {
+	cfg := config.AppConfig.Payment
+	if cfg.MockSecret != "" {
+		mock := NewMockPaymentProvider(cfg.MockSecret)
+		paymentProviders[mock.Name()] = mock
+	}
+}
+
+// getPaymentProvider 根据名称获取支付渠道
+// 参数:
+//
+//	name - 支付渠道名称
+//
+// 返回:
+//
+//	PaymentProvider - 支付渠道
+//	error - 渠道未注册时返回错误
+func getPaymentProvider(name string) (PaymentProvider, error) {
+	paymentProvidersOnce.Do(registerPaymentProviders)
+	provider, ok := paymentProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("不支持的支付渠道: %s", name)
+	}
+	return provider, nil
+}
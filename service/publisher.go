@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+var ErrPublisherNotFound = errors.New("出版社不存在")
+
+// PublisherService 出版社服务
+// 负责出版社详情、出版社列表和合并重复出版社
+type PublisherService struct {
+	PublisherDAO *repository.PublisherDAO // 出版社数据访问对象
+	BookDAO      *repository.BookDAO      // 书籍数据访问对象
+}
+
+// NewPublisherService 创建新的出版社服务实例
+// 返回:
+//
+//	*PublisherService - 初始化好的出版社服务
+func NewPublisherService() *PublisherService {
+	return &PublisherService{
+		PublisherDAO: repository.NewPublisherDAO(),
+		BookDAO:      repository.NewBookDAO(),
+	}
+}
+
+// PublisherDetail 出版社详情
+type PublisherDetail struct {
+	Publisher *model.Publisher `json:"publisher"` // 出版社信息
+	Books     []*model.Book    `json:"books"`     // 当前页的上架图书
+	Total     int64            `json:"total"`     // 上架图书总数
+}
+
+// GetPublisherDetail 获取出版社信息及其分页的上架图书
+// 参数:
+//
+//	id - 出版社ID
+//	req - 分页、排序和筛选条件
+//
+// 返回:
+//
+//	*PublisherDetail - 出版社详情
+//	error - 出版社不存在时返回ErrPublisherNotFound
+func (p *PublisherService) GetPublisherDetail(id int, req *model.BookQueryRequest) (*PublisherDetail, error) {
+	publisher, err := p.PublisherDAO.GetPublisherByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPublisherNotFound
+		}
+		return nil, err
+	}
+
+	filter := bookFilterFromRequest(req)
+	filter.PublisherID = id
+	books, total, err := p.BookDAO.GetBooksByFilter(filter, req.Sort, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &PublisherDetail{Publisher: publisher, Books: books, Total: total}, nil
+}
+
+// GetPublishers 分页获取出版社列表，供后台查找重复出版社
+// 参数:
+//
+//	keyword - 名称关键词
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*repository.PublisherSummary - 出版社及其图书数量
+//	int64 - 出版社总数
+//	error - 错误信息
+func (p *PublisherService) GetPublishers(keyword string, page, pageSize int) ([]*repository.PublisherSummary, int64, error) {
+	return p.PublisherDAO.GetPublishersByPage(keyword, page, pageSize)
+}
+
+// MergePublishers 将重复的出版社合并到目标出版社
+// 被合并出版社的图书改为关联目标出版社，其名称作为目标出版社的别名保留；图书上的出版社文字保持不变
+// 参数:
+//
+//	req - 合并请求
+//
+// 返回:
+//
+//	*model.Publisher - 合并后的目标出版社
+//	error - 出版社不存在或目标出版社在被合并列表中时返回错误
+func (p *PublisherService) MergePublishers(req *model.MergeRequest) (*model.Publisher, error) {
+	if slices.Contains(req.SourceIDs, req.TargetID) {
+		return nil, ErrMergeTargetInSource
+	}
+	ids := append([]int{req.TargetID}, req.SourceIDs...)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	var target *model.Publisher
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		publisherDAO := p.PublisherDAO.WithTx(tx)
+
+		locked, err := publisherDAO.GetPublishersForUpdate(ids)
+		if err != nil {
+			return err
+		}
+		if len(locked) != len(ids) {
+			return ErrPublisherNotFound
+		}
+		for _, publisher := range locked {
+			if publisher.ID == req.TargetID {
+				target = publisher
+			}
+		}
+		return publisherDAO.MergePublishers(req.TargetID, req.SourceIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
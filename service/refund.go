@@ -0,0 +1,499 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefundService 售后服务
+// 负责用户发起退款/退货申请，以及管理员审核后的退款、库存恢复和订单状态流转
+type RefundService struct {
+	RefundDAO         *repository.RefundDAO  // 售后申请数据访问对象
+	OrderDAO          *repository.OrderDAO   // 订单数据访问对象
+	BookDAO           *repository.BookDAO    // 图书数据访问对象
+	PaymentDAO        *repository.PaymentDAO // 支付单数据访问对象
+	orderService      *OrderService          // 订单服务，用于执行订单状态流转
+	refundNoGenerator OrderNoGenerator       // 售后单号生成器
+}
+
+// CreateRefundRequest 发起售后申请请求
+type CreateRefundRequest struct {
+	Type   string                    `json:"type" binding:"required,oneof=refund return"` // 售后类型：refund-仅退款，return-退货退款
+	Reason string                    `json:"reason" binding:"required"`                   // 申请原因
+	Items  []CreateRefundItemRequest `json:"items"`                                       // 退款的订单项，为空表示整单退款
+}
+
+// CreateRefundItemRequest 售后申请项请求
+type CreateRefundItemRequest struct {
+	OrderItemID int `json:"order_item_id"` // 订单项ID
+	Quantity    int `json:"quantity"`      // 退款数量
+}
+
+// refundableStatuses 允许发起售后申请的订单状态
+var refundableStatuses = map[int]bool{
+	model.OrderStatusPaid:              true,
+	model.OrderStatusShipped:           true,
+	model.OrderStatusCompleted:         true,
+	model.OrderStatusPartiallyRefunded: true,
+}
+
+// NewRefundService 创建新的售后服务实例
+// 返回:
+//
+//	*RefundService - 初始化好的售后服务
+func NewRefundService() *RefundService {
+	return &RefundService{
+		RefundDAO:         repository.NewRefundDAO(),
+		OrderDAO:          repository.NewOrderDAO(),
+		BookDAO:           repository.NewBookDAO(),
+		PaymentDAO:        repository.NewPaymentDAO(),
+		orderService:      NewOrderService(),
+		refundNoGenerator: getNoGenerator(refundNoPrefix),
+	}
+}
+
+// RequestRefund 用户发起售后申请
+// 订单进入退款中状态，同一订单同一时间只能有一个待审核的申请
+// 参数:
+//
+//	orderID - 订单ID
+//	userID - 当前用户ID
+//	req - 售后申请请求
+//
+// 返回:
+//
+//	*model.Refund - 创建的售后申请
+//	error - 错误信息
+func (r *RefundService) RequestRefund(orderID, userID int, req *CreateRefundRequest) (*model.Refund, error) {
+	order, err := r.OrderDAO.GetOrderByID(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID != userID {
+		return nil, errors.New("无权操作该订单")
+	}
+
+	refundNo, err := r.refundNoGenerator.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	refund := &model.Refund{
+		RefundNo: refundNo,
+		OrderID:  orderID,
+		UserID:   userID,
+		Type:     req.Type,
+		Reason:   req.Reason,
+		Status:   model.RefundStatusPending,
+	}
+
+	err = r.orderService.transitionOrder(orderID, model.OrderStatusRefunding, model.OrderOperatorUser, userID, "申请售后: "+req.Reason,
+		func(tx *gorm.DB, order *model.Order) error {
+			if !refundableStatuses[order.Status] {
+				return fmt.Errorf("订单当前状态为「%s」，无法申请售后", model.OrderStatusText(order.Status))
+			}
+
+			items, amount, err := buildRefundItems(order, req.Items)
+			if err != nil {
+				return err
+			}
+			refund.Items = items
+			refund.Amount = amount
+			refund.PrevOrderStatus = order.Status
+			return r.RefundDAO.WithTx(tx).CreateRefund(refund)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// buildRefundItems 根据申请构建售后申请项并计算退款金额
+// 参数:
+//
+//	order - 已加锁的订单（包含订单项）
+//	reqItems - 申请的订单项，为空表示所有未退款的订单项
+//
+// 返回:
+//
+//	[]model.RefundItem - 售后申请项
+//	int - 退款金额
+//	error - 订单项不存在或数量超过可退数量时返回错误
+func buildRefundItems(order *model.Order, reqItems []CreateRefundItemRequest) ([]model.RefundItem, int, error) {
+	orderItems := make(map[int]model.OrderItem, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		orderItems[item.ID] = item
+	}
+
+	// 整单退款：退回所有剩余数量
+	if len(reqItems) == 0 {
+		for _, item := range order.OrderItems {
+			if remaining := item.Quantity - item.RefundedQuantity; remaining > 0 {
+				reqItems = append(reqItems, CreateRefundItemRequest{OrderItemID: item.ID, Quantity: remaining})
+			}
+		}
+	}
+
+	// 合并同一订单项的数量
+	quantities := make(map[int]int, len(reqItems))
+	for _, reqItem := range reqItems {
+		if reqItem.Quantity <= 0 {
+			return nil, 0, errors.New("退款数量必须大于0")
+		}
+		quantities[reqItem.OrderItemID] += reqItem.Quantity
+	}
+	if len(quantities) == 0 {
+		return nil, 0, errors.New("没有可退款的订单项")
+	}
+
+	var items []model.RefundItem
+	var amount int
+	for orderItemID, quantity := range quantities {
+		item, ok := orderItems[orderItemID]
+		if !ok {
+			return nil, 0, fmt.Errorf("订单项%d不属于该订单", orderItemID)
+		}
+		if quantity > item.Quantity-item.RefundedQuantity {
+			return nil, 0, fmt.Errorf("订单项%d的退款数量超过可退数量", orderItemID)
+		}
+
+		itemAmount := refundItemAmount(&item, quantity)
+		items = append(items, model.RefundItem{
+			OrderItemID: item.ID,
+			BookID:      item.BookID,
+			Quantity:    quantity,
+			Amount:      itemAmount,
+		})
+		amount += itemAmount
+	}
+
+	// 按图书ID排序，审核时按相同顺序更新图书行
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].BookID < items[j].BookID
+	})
+	return items, amount, nil
+}
+
+// refundItemAmount 计算订单项本次退款的金额
+// 按实付金额（小计减去分摊的优惠）累计比例计算，多次部分退款的金额之和恰好等于实付金额
+// 参数:
+//
+//	item - 订单项
+//	quantity - 本次退款数量
+//
+// 返回:
+//
+//	int - 本次退款金额（元）
+func refundItemAmount(item *model.OrderItem, quantity int) int {
+	paid := item.Subtotal - item.DiscountAmount
+	refunded := item.RefundedQuantity
+	return paid*(refunded+quantity)/item.Quantity - paid*refunded/item.Quantity
+}
+
+// ApproveRefund 管理员同意售后申请
+// 分两步完成：先在同一事务中累加已退款数量、恢复库存并扣减销量、占用支付单的可退金额，
+// 将申请标记为待渠道退款并提交；再在事务外调用支付渠道退款并记录结果，避免持有订单行锁等待外部调用。
+// 所有订单项均已退完时订单变为已退款，否则变为部分退款；
+// 渠道退款失败时申请保持待渠道退款状态，再次同意该申请会以相同的售后单号重试退款
+// 参数:
+//
+//	refundID - 售后申请ID
+//	adminID - 管理员ID
+//	remark - 审核备注
+//
+// 返回:
+//
+//	error - 错误信息
+func (r *RefundService) ApproveRefund(refundID, adminID int, remark string) error {
+	refund, err := r.RefundDAO.GetRefundByID(refundID)
+	if err != nil {
+		return errors.New("售后申请不存在")
+	}
+	if refund.Status == model.RefundStatusRefunding {
+		return r.completeRefundPayment(refundID)
+	}
+	if refund.Status != model.RefundStatusPending {
+		return errors.New("售后申请已处理")
+	}
+
+	// 订单处于退款中时不会有其他售后申请，可以据此预先判断退款后的状态
+	order, err := r.OrderDAO.GetOrderByID(refund.OrderID)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+	target := refundTargetStatus(order, refund)
+
+	reason := "同意售后"
+	if remark != "" {
+		reason += ": " + remark
+	}
+	err = r.orderService.transitionOrder(refund.OrderID, target, model.OrderOperatorAdmin, adminID, reason,
+		func(tx *gorm.DB, order *model.Order) error {
+			refundDAO := r.RefundDAO.WithTx(tx)
+			locked, err := refundDAO.GetRefundForUpdate(refundID)
+			if err != nil {
+				return err
+			}
+			if locked.Status != model.RefundStatusPending {
+				return errors.New("售后申请已处理")
+			}
+
+			// 累加已退款数量并恢复库存
+			orderDAO := r.OrderDAO.WithTx(tx)
+			bookDAO := r.BookDAO.WithTx(tx)
+			for _, item := range locked.Items {
+				if err := orderDAO.AddItemRefundedQuantity(item.OrderItemID, item.Quantity); err != nil {
+					return err
+				}
+				if err := bookDAO.RestoreStock(item.BookID, item.Quantity); err != nil {
+					return err
+				}
+			}
+			if err := orderDAO.AddRefundedAmount(order.ID, locked.Amount); err != nil {
+				return err
+			}
+
+			// 占用支付单的可退金额，渠道退款在事务提交后进行
+			paymentNo, err := r.reservePaymentRefund(tx, order.ID, locked)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			locked.Status = model.RefundStatusRefunding
+			if paymentNo == "" {
+				locked.Status = model.RefundStatusApproved
+			}
+			locked.AdminID = adminID
+			locked.AdminRemark = remark
+			locked.PaymentNo = paymentNo
+			locked.ProcessedAt = &now
+			return refundDAO.UpdateRefund(locked)
+		})
+	if err != nil {
+		return err
+	}
+	return r.completeRefundPayment(refundID)
+}
+
+// refundTargetStatus 计算同意售后后订单应流转到的状态
+// 参数:
+//
+//	order - 订单（包含订单项）
+//	refund - 售后申请（包含申请项）
+//
+// 返回:
+//
+//	int - 全部退完返回已退款，否则返回部分退款
+func refundTargetStatus(order *model.Order, refund *model.Refund) int {
+	refunding := make(map[int]int, len(refund.Items))
+	for _, item := range refund.Items {
+		refunding[item.OrderItemID] += item.Quantity
+	}
+	for _, item := range order.OrderItems {
+		if item.RefundedQuantity+refunding[item.ID] < item.Quantity {
+			return model.OrderStatusPartiallyRefunded
+		}
+	}
+	return model.OrderStatusRefunded
+}
+
+// reservePaymentRefund 占用原支付单的可退金额（需在事务中调用）
+// 在接入支付渠道之前支付的订单没有支付单，此时跳过渠道退款，由线下处理；
+// 退款金额为0（如退回的订单项已被优惠全额抵扣）时同样无需渠道退款
+// 参数:
+//
+//	tx - 事务中的数据库连接
+//	orderID - 订单ID
+//	refund - 售后申请
+//
+// 返回:
+//
+//	string - 原支付单号，没有支付单或无需渠道退款时为空
+//	error - 累计退款金额超过支付金额时返回错误
+func (r *RefundService) reservePaymentRefund(tx *gorm.DB, orderID int, refund *model.Refund) (string, error) {
+	if refund.Amount == 0 {
+		return "", nil
+	}
+
+	paymentDAO := r.PaymentDAO.WithTx(tx)
+	payment, err := paymentDAO.GetPaidPaymentByOrderID(orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	payment, err = paymentDAO.GetPaymentForUpdate(payment.PaymentNo)
+	if err != nil {
+		return "", err
+	}
+	if payment.RefundedAmount+refund.Amount > payment.Amount {
+		return "", errors.New("累计退款金额超过支付金额")
+	}
+
+	payment.RefundedAmount += refund.Amount
+	if err := paymentDAO.UpdatePayment(payment); err != nil {
+		return "", err
+	}
+	return payment.PaymentNo, nil
+}
+
+// completeRefundPayment 通过原支付渠道完成待渠道退款的售后申请
+// 以售后单号作为渠道退款单号，重复调用不会重复退款；退款成功后将申请标记为已同意，
+// 支付单全部退完时标记为已全额退款
+// 参数:
+//
+//	refundID - 售后申请ID
+//
+// 返回:
+//
+//	error - 渠道退款失败时返回错误，申请保持待渠道退款状态
+func (r *RefundService) completeRefundPayment(refundID int) error {
+	refund, err := r.RefundDAO.GetRefundByID(refundID)
+	if err != nil {
+		return errors.New("售后申请不存在")
+	}
+	if refund.Status != model.RefundStatusRefunding {
+		return nil
+	}
+
+	payment, err := r.PaymentDAO.GetPaymentByPaymentNo(refund.PaymentNo)
+	if err != nil {
+		return errors.New("支付单不存在")
+	}
+	provider, err := getPaymentProvider(payment.Provider)
+	if err != nil {
+		return err
+	}
+	result, err := provider.Refund(&PaymentRefundRequest{
+		RefundNo:      refund.RefundNo,
+		PaymentNo:     payment.PaymentNo,
+		TransactionID: payment.TransactionID,
+		Amount:        refund.Amount,
+		TotalAmount:   payment.Amount,
+	})
+	if err != nil {
+		return fmt.Errorf("已同意售后，支付渠道退款失败，请稍后重试: %v", err)
+	}
+
+	return global.DBClient.Transaction(func(tx *gorm.DB) error {
+		refundDAO := r.RefundDAO.WithTx(tx)
+		locked, err := refundDAO.GetRefundForUpdate(refundID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != model.RefundStatusRefunding {
+			return nil
+		}
+		locked.Status = model.RefundStatusApproved
+		locked.RefundTransactionID = result.RefundTransactionID
+		if err := refundDAO.UpdateRefund(locked); err != nil {
+			return err
+		}
+
+		paymentDAO := r.PaymentDAO.WithTx(tx)
+		payment, err := paymentDAO.GetPaymentForUpdate(refund.PaymentNo)
+		if err != nil {
+			return err
+		}
+		if payment.RefundedAmount < payment.Amount || payment.Status == model.PaymentStatusRefunded {
+			return nil
+		}
+		payment.Status = model.PaymentStatusRefunded
+		return paymentDAO.UpdatePayment(payment)
+	})
+}
+
+// RejectRefund 管理员拒绝售后申请
+// 订单恢复为申请前的状态
+// 参数:
+//
+//	refundID - 售后申请ID
+//	adminID - 管理员ID
+//	remark - 拒绝原因
+//
+// 返回:
+//
+//	error - 错误信息
+func (r *RefundService) RejectRefund(refundID, adminID int, remark string) error {
+	refund, err := r.RefundDAO.GetRefundByID(refundID)
+	if err != nil {
+		return errors.New("售后申请不存在")
+	}
+	if refund.Status != model.RefundStatusPending {
+		return errors.New("售后申请已处理")
+	}
+
+	return r.orderService.transitionOrder(refund.OrderID, refund.PrevOrderStatus, model.OrderOperatorAdmin, adminID, "拒绝售后: "+remark,
+		func(tx *gorm.DB, order *model.Order) error {
+			refundDAO := r.RefundDAO.WithTx(tx)
+			locked, err := refundDAO.GetRefundForUpdate(refundID)
+			if err != nil {
+				return err
+			}
+			if locked.Status != model.RefundStatusPending {
+				return errors.New("售后申请已处理")
+			}
+
+			now := time.Now()
+			locked.Status = model.RefundStatusRejected
+			locked.AdminID = adminID
+			locked.AdminRemark = remark
+			locked.ProcessedAt = &now
+			return refundDAO.UpdateRefund(locked)
+		})
+}
+
+// GetUserRefunds 获取用户的售后申请列表
+// 参数:
+//
+//	userID - 用户ID
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*model.Refund - 售后申请列表
+//	int64 - 总记录数
+//	error - 错误信息
+func (r *RefundService) GetUserRefunds(userID, page, pageSize int) ([]*model.Refund, int64, error) {
+	return r.RefundDAO.GetRefunds(userID, nil, page, pageSize)
+}
+
+// GetRefundList 获取售后申请列表（管理员）
+// 参数:
+//
+//	status - 审核状态，nil表示不过滤
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*model.Refund - 售后申请列表
+//	int64 - 总记录数
+//	error - 错误信息
+func (r *RefundService) GetRefundList(status *int, page, pageSize int) ([]*model.Refund, int64, error) {
+	return r.RefundDAO.GetRefunds(0, status, page, pageSize)
+}
+
+// GetRefundByID 根据ID获取售后申请
+// 参数:
+//
+//	id - 售后申请ID
+//
+// 返回:
+//
+//	*model.Refund - 售后申请（包含申请项、图书和订单信息）
+//	error - 错误信息
+func (r *RefundService) GetRefundByID(id int) (*model.Refund, error) {
+	return r.RefundDAO.GetRefundByID(id)
+}
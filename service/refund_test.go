@@ -0,0 +1,53 @@
+package service
+
+import (
+	"bookstore/model"
+	"testing"
+)
+
+func TestRefundItemAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    model.OrderItem
+		batches []int // 依次退款的数量
+		want    []int // 每次的退款金额
+	}{
+		{"无优惠全部退款", model.OrderItem{Quantity: 2, Subtotal: 60}, []int{2}, []int{60}},
+		{"分摊优惠后全部退款", model.OrderItem{Quantity: 3, Subtotal: 90, DiscountAmount: 10}, []int{3}, []int{80}},
+		{"多次部分退款之和等于实付", model.OrderItem{Quantity: 3, Subtotal: 100, DiscountAmount: 0}, []int{1, 1, 1}, []int{33, 33, 34}},
+		{"优惠减免到0元", model.OrderItem{Quantity: 1, Subtotal: 10, DiscountAmount: 10}, []int{1}, []int{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			for i, quantity := range tt.batches {
+				if got := refundItemAmount(&item, quantity); got != tt.want[i] {
+					t.Errorf("batch %d: refundItemAmount = %d, want %d", i, got, tt.want[i])
+				}
+				item.RefundedQuantity += quantity
+			}
+		})
+	}
+}
+
+// TestRefundItemAmountAfterAllocation 分摊优惠后每个订单项的退款金额都不能为负，且合计等于实付金额
+func TestRefundItemAmountAfterAllocation(t *testing.T) {
+	items := []*model.OrderItem{
+		{Quantity: 1, Subtotal: 10},
+		{Quantity: 2, Subtotal: 10},
+		{Quantity: 1, Subtotal: 1},
+	}
+	allocateDiscount(items, 21, 20)
+
+	total := 0
+	for i, item := range items {
+		amount := refundItemAmount(item, item.Quantity)
+		if amount < 0 {
+			t.Fatalf("item %d: refund amount %d is negative", i, amount)
+		}
+		total += amount
+	}
+	if total != 1 {
+		t.Fatalf("refunded %d in total, want 1", total)
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"bookstore/model"
+	"errors"
+	"fmt"
+)
+
+// 再次购买的方式
+const (
+	ReorderModeCart  = "cart"  // 加入购物车
+	ReorderModeOrder = "order" // 直接创建待支付订单
+)
+
+// ErrReorderUnavailable 原订单中没有可购买的图书
+var ErrReorderUnavailable = errors.New("原订单中的图书均不可购买")
+
+// ReorderRequest 再次购买请求
+type ReorderRequest struct {
+	Mode       string `json:"mode" binding:"omitempty,oneof=cart order"` // 再次购买方式：cart（默认）加入购物车，order直接下单
+	AddressID  int    `json:"address_id"`                                // 直接下单时的收货地址ID，0表示使用默认地址
+	CouponCode string `json:"coupon_code"`                               // 直接下单时使用的优惠码（可选）
+}
+
+// ReorderItem 再次购买的图书
+type ReorderItem struct {
+	BookID           int    `json:"book_id"`           // 图书ID
+	Title            string `json:"title"`             // 图书标题，图书已删除时为空
+	OriginalQuantity int    `json:"original_quantity"` // 原订单中的数量
+	Quantity         int    `json:"quantity"`          // 本次购买的数量，跳过时为0
+	OriginalPrice    int    `json:"original_price"`    // 原订单中的单价（元）
+	CurrentPrice     int    `json:"current_price"`     // 当前折后单价（元），图书已删除时为0
+	Message          string `json:"message,omitempty"` // 跳过或调整数量的原因
+}
+
+// ReorderResult 再次购买结果
+type ReorderResult struct {
+	Mode     string        `json:"mode"`            // 再次购买方式
+	Order    *model.Order  `json:"order,omitempty"` // 创建的订单，仅直接下单时返回
+	Cart     *CartResponse `json:"cart,omitempty"`  // 更新后的购物车，仅加入购物车时返回
+	Added    []ReorderItem `json:"added"`           // 已下单或加入购物车的图书
+	Repriced []ReorderItem `json:"repriced"`        // 价格与原订单不同的图书，已按当前价格购买
+	Skipped  []ReorderItem `json:"skipped"`         // 已删除、已下架、已售罄或超出购物车上限而跳过的图书
+}
+
+// Reorder 按历史订单再次购买
+// 重新校验原订单中每本图书的状态、库存和当前价格，不可购买的图书跳过，库存不足时按可用库存购买；
+// 可购买的图书按当前价格加入购物车或直接创建待支付订单
+// 参数:
+//
+//	userID - 用户ID
+//	orderID - 原订单ID，只允许订单所有者再次购买
+//	req - 再次购买请求
+//
+// 返回:
+//
+//	*ReorderResult - 再次购买结果，没有可购买的图书时同样返回，用于说明跳过的原因
+//	error - 原订单不存在时返回ErrOrderNotFound，没有可购买的图书时返回ErrReorderUnavailable
+func (c *CartService) Reorder(userID, orderID int, req *ReorderRequest) (*ReorderResult, error) {
+	order, err := c.orderService.OrderDAO.GetOrderByID(orderID)
+	if err != nil || order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+
+	result := &ReorderResult{
+		Mode:     req.Mode,
+		Added:    []ReorderItem{},
+		Repriced: []ReorderItem{},
+		Skipped:  []ReorderItem{},
+	}
+	if result.Mode == "" {
+		result.Mode = ReorderModeCart
+	}
+
+	// 合并同一图书的数量，按原订单中的顺序处理
+	var bookIDs []int
+	items := make(map[int]*ReorderItem)
+	for _, item := range order.OrderItems {
+		if existing, ok := items[item.BookID]; ok {
+			existing.OriginalQuantity += item.Quantity
+			continue
+		}
+		bookIDs = append(bookIDs, item.BookID)
+		items[item.BookID] = &ReorderItem{
+			BookID:           item.BookID,
+			OriginalQuantity: item.Quantity,
+			OriginalPrice:    item.Price,
+		}
+	}
+
+	quantities := make(map[int]int, len(items))
+	for bookID, item := range items {
+		quantities[bookID] = item.OriginalQuantity
+	}
+	books, err := c.loadBooks(quantities)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := userCartOwner(userID)
+	cartItems := map[int]int{}
+	if result.Mode == ReorderModeCart {
+		if cartItems, err = c.CartDAO.GetItems(owner); err != nil {
+			return nil, err
+		}
+	}
+	cartCount := len(cartItems)
+
+	var orderItems []CreateOrderItemRequest
+	updates := make(map[int]int)
+	for _, bookID := range bookIDs {
+		item := items[bookID]
+		book := books[bookID]
+		if book != nil {
+			item.Title = book.Title
+			item.CurrentPrice = book.FinalPrice()
+		}
+
+		if message := checkCartBook(book, 1); message != "" {
+			item.Message = message
+			result.Skipped = append(result.Skipped, *item)
+			continue
+		}
+
+		item.Quantity = min(item.OriginalQuantity, book.AvailableStock())
+		if item.Quantity < item.OriginalQuantity {
+			item.Message = fmt.Sprintf("库存不足，数量已调整为%d件", item.Quantity)
+		}
+		if result.Mode == ReorderModeCart {
+			if _, ok := cartItems[bookID]; !ok {
+				if cartCount >= maxCartItems {
+					item.Quantity = 0
+					item.Message = fmt.Sprintf("购物车最多只能放入%d种图书", maxCartItems)
+					result.Skipped = append(result.Skipped, *item)
+					continue
+				}
+				cartCount++
+			}
+			// 购物车中已有该图书时累加数量，合计不超过可用库存和单本上限
+			inCart, stock := cartItems[bookID], book.AvailableStock()
+			total := min(inCart+item.OriginalQuantity, stock, maxCartQuantity)
+			item.Quantity = max(total-inCart, 0)
+			item.Message = reorderCartMessage(inCart, item.Quantity, item.OriginalQuantity, stock < maxCartQuantity)
+			if item.Quantity == 0 {
+				result.Skipped = append(result.Skipped, *item)
+				continue
+			}
+			updates[bookID] = total
+		} else {
+			orderItems = append(orderItems, CreateOrderItemRequest{
+				BookID:   bookID,
+				Quantity: item.Quantity,
+				Price:    item.CurrentPrice,
+			})
+		}
+
+		result.Added = append(result.Added, *item)
+		if item.CurrentPrice != item.OriginalPrice {
+			result.Repriced = append(result.Repriced, *item)
+		}
+	}
+
+	if len(result.Added) == 0 {
+		return result, ErrReorderUnavailable
+	}
+
+	if result.Mode == ReorderModeOrder {
+		result.Order, err = c.orderService.CreateOrder(&CreateOrderRequest{
+			UserID:     userID,
+			AddressID:  req.AddressID,
+			CouponCode: req.CouponCode,
+			Items:      orderItems,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	if err := c.CartDAO.SetItems(owner, updates, userCartTTL); err != nil {
+		return nil, err
+	}
+	if result.Cart, err = c.getCart(owner); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// reorderCartMessage 说明再来一单加入购物车的数量为何少于原订单
+// 数量可能受可用库存限制，也可能受购物车中已有的数量和单本上限限制
+// 参数:
+//
+//	inCart - 购物车中已有的数量
+//	added - 本次加入的数量
+//	original - 原订单中的数量
+//	stockLimited - 可用库存是否低于单本上限，即合计数量受库存而非单本上限限制
+//
+// 返回:
+//
+//	string - 提示信息，数量未被调整时为空
+func reorderCartMessage(inCart, added, original int, stockLimited bool) string {
+	if added >= original {
+		return ""
+	}
+	limit := fmt.Sprintf("每种图书最多加入%d件", maxCartQuantity)
+	if stockLimited {
+		limit = "库存不足"
+	}
+	switch {
+	case inCart == 0:
+		return fmt.Sprintf("%s，数量已调整为%d件", limit, added)
+	case added == 0:
+		return fmt.Sprintf("购物车中已有%d件，%s，未再加入", inCart, limit)
+	default:
+		return fmt.Sprintf("购物车中已有%d件，%s，本次加入%d件", inCart, limit, added)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"bookstore/config"
+	"bookstore/model"
+	"bookstore/repository"
+	"log"
+	"sync"
+)
+
+// 搜索字段权重，命中标题的图书排在只命中描述的图书之前
+const (
+	searchWeightTitle       = 3.0
+	searchWeightAuthor      = 2.0
+	searchWeightPublisher   = 1.0
+	searchWeightDescription = 1.0
+)
+
+// SearchQuery 图书搜索条件
+type SearchQuery struct {
+	Keyword string // 搜索关键词
+	Offset  int    // 跳过的结果数
+	Limit   int    // 返回的最大结果数
+}
+
+// SearchHit 单条搜索结果
+type SearchHit struct {
+	BookID int      // 图书ID
+	Score  float64  // 相关度得分，越大越相关
+	Terms  []string // 命中的词（已规范化），用于生成高亮片段
+}
+
+// SearchResult 搜索结果
+type SearchResult struct {
+	Hits  []SearchHit // 当前页的结果，按相关度降序排列
+	Total int64       // 符合条件的结果总数
+}
+
+// SearchEngine 图书全文搜索引擎接口
+// 只检索上架图书的标题、作者、出版社和描述，图书创建、更新、删除后由书籍服务同步到引擎
+type SearchEngine interface {
+	// Name 返回搜索引擎名称，用于配置和日志
+	Name() string
+	// Rebuild 使用全部图书重建索引，启动时调用
+	Rebuild(books []*model.Book) error
+	// Index 新增或更新一本图书的索引，下架的图书会从索引中移除
+	Index(book *model.Book) error
+	// Remove 从索引中移除一本图书
+	Remove(bookID int) error
+	// Search 按相关度搜索图书
+	Search(query *SearchQuery) (*SearchResult, error)
+}
+
+var (
+	searchEngine     SearchEngine // 进程内共享的搜索引擎
+	searchEngineOnce sync.Once    // 保证搜索引擎只创建一次
+)
+
+// getSearchEngine 根据配置获取搜索引擎
+// 内存索引持有进程内的倒排表，因此在进程内只创建一次
+// 返回:
+//
+//	SearchEngine - 搜索引擎
+func getSearchEngine() SearchEngine {
+	searchEngineOnce.Do(func() {
+		switch config.AppConfig.Search.Engine {
+		case "mysql":
+			searchEngine = NewMySQLSearchEngine(repository.NewBookDAO())
+		default:
+			searchEngine = NewMemorySearchEngine()
+		}
+	})
+	return searchEngine
+}
+
+// InitSearchIndex 加载全部图书并重建搜索索引
+// 应在数据库连接初始化后、开始处理请求前调用
+// 返回:
+//
+//	error - 加载图书或重建索引失败时返回错误
+func InitSearchIndex() error {
+	engine := getSearchEngine()
+	books, err := repository.NewBookDAO().GetAllBooks()
+	if err != nil {
+		return err
+	}
+	if err := engine.Rebuild(books); err != nil {
+		return err
+	}
+	log.Printf("搜索索引已加载，引擎: %s，图书数量: %d", engine.Name(), len(books))
+	return nil
+}
+
+// BookSearchHit 带相关度和高亮片段的图书搜索结果
+type BookSearchHit struct {
+	*model.Book
+	Score      float64           `json:"score"`                // 相关度得分
+	Highlights map[string]string `json:"highlights,omitempty"` // 命中字段的高亮片段，键为字段名，命中词以<em>标签包裹
+}
+
+// newBookSearchHit 根据命中的词生成图书的高亮片段
+// 参数:
+//
+//	book - 图书信息
+//	hit - 搜索引擎返回的结果
+//
+// 返回:
+//
+//	*BookSearchHit - 带高亮片段的搜索结果
+func newBookSearchHit(book *model.Book, hit SearchHit) *BookSearchHit {
+	result := &BookSearchHit{
+		Book:       book,
+		Score:      hit.Score,
+		Highlights: make(map[string]string),
+	}
+	fields := []struct {
+		name    string
+		text    string
+		snippet bool
+	}{
+		{"title", book.Title, false},
+		{"author", book.Author, false},
+		{"publisher", book.Publisher, false},
+		{"description", book.Description, true},
+	}
+	for _, field := range fields {
+		if highlight := highlightSearchText(field.text, hit.Terms, field.snippet); highlight != "" {
+			result.Highlights[field.name] = highlight
+		}
+	}
+	return result
+}
+
+// searchBookFields 返回图书参与搜索的字段文本，顺序与searchFieldWeights一致
+// 参数:
+//
+//	book - 图书信息
+//
+// 返回:
+//
+//	[]string - 字段文本
+func searchBookFields(book *model.Book) []string {
+	return []string{book.Title, book.Author, book.Publisher, book.Description}
+}
+
+// searchFieldWeights 各搜索字段的权重，与searchBookFields返回的字段一一对应
+var searchFieldWeights = []float64{searchWeightTitle, searchWeightAuthor, searchWeightPublisher, searchWeightDescription}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bookstore/model"
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25相关度算法参数
+const (
+	bm25K1 = 1.2  // 词频饱和度
+	bm25B  = 0.75 // 字段长度归一化程度
+	// searchTypoPenalty 每个拼写错误对得分的折扣
+	searchTypoPenalty = 0.5
+)
+
+// memorySearchDocument 内存索引中的一本图书
+type memorySearchDocument struct {
+	termFreqs []map[string]int // 每个字段中各索引词的出现次数
+	lengths   []int            // 每个字段的索引词数量
+}
+
+// MemorySearchEngine 基于内存倒排索引的搜索引擎
+// 启动时从数据库加载全部上架图书，适合单实例部署；多实例部署时各实例的索引只随本实例的图书修改同步，
+// 应改用mysql引擎
+type MemorySearchEngine struct {
+	mu          sync.RWMutex
+	documents   map[int]*memorySearchDocument // 以图书ID为键的文档
+	postings    map[string]map[int]struct{}   // 倒排表，以索引词为键的图书ID集合
+	totalLength []int                         // 每个字段在所有文档中的索引词总数，用于计算平均长度
+}
+
+// NewMemorySearchEngine 创建内存搜索引擎
+// 返回:
+//
+//	*MemorySearchEngine - 空的内存搜索引擎，需调用Rebuild加载图书
+func NewMemorySearchEngine() *MemorySearchEngine {
+	return &MemorySearchEngine{
+		documents:   make(map[int]*memorySearchDocument),
+		postings:    make(map[string]map[int]struct{}),
+		totalLength: make([]int, len(searchFieldWeights)),
+	}
+}
+
+// Name 返回搜索引擎名称
+func (e *MemorySearchEngine) Name() string {
+	return "memory"
+}
+
+// Rebuild 清空索引后重新索引全部上架图书
+// 参数:
+//
+//	books - 全部图书
+//
+// 返回:
+//
+//	error - 始终为nil
+func (e *MemorySearchEngine) Rebuild(books []*model.Book) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.documents = make(map[int]*memorySearchDocument, len(books))
+	e.postings = make(map[string]map[int]struct{})
+	e.totalLength = make([]int, len(searchFieldWeights))
+	for _, book := range books {
+		e.add(book)
+	}
+	return nil
+}
+
+// Index 新增或更新一本图书的索引，下架的图书会从索引中移除
+// 参数:
+//
+//	book - 图书信息
+//
+// 返回:
+//
+//	error - 始终为nil
+func (e *MemorySearchEngine) Index(book *model.Book) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.remove(book.ID)
+	e.add(book)
+	return nil
+}
+
+// Remove 从索引中移除一本图书
+// 参数:
+//
+//	bookID - 图书ID
+//
+// 返回:
+//
+//	error - 始终为nil
+func (e *MemorySearchEngine) Remove(bookID int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.remove(bookID)
+	return nil
+}
+
+// add 将上架图书加入索引，调用方需持有写锁
+func (e *MemorySearchEngine) add(book *model.Book) {
+	if book.Status != 1 {
+		return
+	}
+
+	fields := searchBookFields(book)
+	doc := &memorySearchDocument{
+		termFreqs: make([]map[string]int, len(fields)),
+		lengths:   make([]int, len(fields)),
+	}
+	for i, text := range fields {
+		terms := analyzeSearchText(text)
+		doc.termFreqs[i] = make(map[string]int)
+		doc.lengths[i] = len(terms)
+		e.totalLength[i] += len(terms)
+		for _, term := range terms {
+			doc.termFreqs[i][term]++
+			if e.postings[term] == nil {
+				e.postings[term] = make(map[int]struct{})
+			}
+			e.postings[term][book.ID] = struct{}{}
+		}
+	}
+	e.documents[book.ID] = doc
+}
+
+// remove 将图书从索引中移除，调用方需持有写锁
+func (e *MemorySearchEngine) remove(bookID int) {
+	doc, ok := e.documents[bookID]
+	if !ok {
+		return
+	}
+
+	for i, freqs := range doc.termFreqs {
+		e.totalLength[i] -= doc.lengths[i]
+		for term := range freqs {
+			delete(e.postings[term], bookID)
+			if len(e.postings[term]) == 0 {
+				delete(e.postings, term)
+			}
+		}
+	}
+	delete(e.documents, bookID)
+}
+
+// memorySearchCandidate 查询词在索引中对应的词
+type memorySearchCandidate struct {
+	term   string  // 索引中的词
+	weight float64 // 得分折扣，精确匹配为1，每个拼写错误折半
+}
+
+// Search 按BM25相关度搜索图书
+// 每个查询词在各字段的得分按字段权重加权求和；英文单词在索引中不存在时按允许的拼写错误数查找相近的词；
+// 图书需命中大部分查询词，命中比例越高得分越高
+// 参数:
+//
+//	query - 搜索条件
+//
+// 返回:
+//
+//	*SearchResult - 当前页的结果和结果总数
+//	error - 始终为nil
+func (e *MemorySearchEngine) Search(query *SearchQuery) (*SearchResult, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	terms := analyzeSearchQuery(query.Keyword)
+	if len(terms) == 0 || len(e.documents) == 0 {
+		return &SearchResult{Hits: []SearchHit{}}, nil
+	}
+
+	avgLength := make([]float64, len(e.totalLength))
+	for i, total := range e.totalLength {
+		avgLength[i] = math.Max(float64(total)/float64(len(e.documents)), 1)
+	}
+
+	type scoredDocument struct {
+		score   float64
+		matched int
+		terms   []string
+	}
+	scored := make(map[int]*scoredDocument)
+
+	for _, term := range terms {
+		// 每本图书只取该查询词得分最高的候选词
+		best := make(map[int]float64)
+		bestTerm := make(map[int]string)
+		for _, candidate := range e.candidates(term) {
+			postings := e.postings[candidate.term]
+			idf := math.Log(1 + (float64(len(e.documents))-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+			for bookID := range postings {
+				doc := e.documents[bookID]
+				score := 0.0
+				for i, freqs := range doc.termFreqs {
+					tf := float64(freqs[candidate.term])
+					if tf == 0 {
+						continue
+					}
+					norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(doc.lengths[i])/avgLength[i]))
+					score += searchFieldWeights[i] * idf * norm
+				}
+				score *= candidate.weight
+				if score > best[bookID] {
+					best[bookID] = score
+					bestTerm[bookID] = candidate.term
+				}
+			}
+		}
+
+		for bookID, score := range best {
+			doc := scored[bookID]
+			if doc == nil {
+				doc = &scoredDocument{}
+				scored[bookID] = doc
+			}
+			doc.score += score
+			doc.matched++
+			doc.terms = append(doc.terms, bestTerm[bookID])
+		}
+	}
+
+	minMatch := searchMinShouldMatch(len(terms))
+	hits := make([]SearchHit, 0, len(scored))
+	for bookID, doc := range scored {
+		if doc.matched < minMatch {
+			continue
+		}
+		hits = append(hits, SearchHit{
+			BookID: bookID,
+			Score:  doc.score * float64(doc.matched) / float64(len(terms)),
+			Terms:  doc.terms,
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].BookID < hits[j].BookID
+	})
+
+	result := &SearchResult{Total: int64(len(hits))}
+	start := min(max(query.Offset, 0), len(hits))
+	end := len(hits)
+	if query.Limit > 0 {
+		end = min(start+query.Limit, len(hits))
+	}
+	result.Hits = hits[start:end]
+	return result, nil
+}
+
+// candidates 查找查询词在索引中对应的词，调用方需持有读锁
+// 参数:
+//
+//	term - 查询词
+//
+// 返回:
+//
+//	[]memorySearchCandidate - 索引中存在该词时只返回该词，否则返回拼写相近的英文单词
+func (e *MemorySearchEngine) candidates(term string) []memorySearchCandidate {
+	if _, ok := e.postings[term]; ok {
+		return []memorySearchCandidate{{term: term, weight: 1}}
+	}
+
+	query := []rune(term)
+	limit := maxTypos(query)
+	if limit == 0 || isCJKRune(query[0]) {
+		return nil
+	}
+
+	var candidates []memorySearchCandidate
+	for indexed := range e.postings {
+		runes := []rune(indexed)
+		if isCJKRune(runes[0]) {
+			continue
+		}
+		if distance := editDistance(query, runes, limit); distance <= limit {
+			candidates = append(candidates, memorySearchCandidate{
+				term:   indexed,
+				weight: math.Pow(searchTypoPenalty, float64(distance)),
+			})
+		}
+	}
+	return candidates
+}
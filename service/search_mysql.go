@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bookstore/model"
+	"bookstore/repository"
+	"strings"
+)
+
+// MySQLSearchEngine 基于MySQL FULLTEXT索引的搜索引擎
+// books表上的全文索引使用ngram分词器，由MySQL随图书的增删改自动维护，适合多实例部署；
+// ngram按相邻两字切分，关键词中个别错字或拼写错误时仍能按部分命中的词返回结果
+type MySQLSearchEngine struct {
+	bookDAO *repository.BookDAO // 书籍数据访问对象
+}
+
+// NewMySQLSearchEngine 创建MySQL全文搜索引擎
+// 参数:
+//
+//	bookDAO - 书籍数据访问对象
+//
+// 返回:
+//
+//	*MySQLSearchEngine - MySQL全文搜索引擎
+func NewMySQLSearchEngine(bookDAO *repository.BookDAO) *MySQLSearchEngine {
+	return &MySQLSearchEngine{bookDAO: bookDAO}
+}
+
+// Name 返回搜索引擎名称
+func (e *MySQLSearchEngine) Name() string {
+	return "mysql"
+}
+
+// Rebuild 全文索引由MySQL维护，无需重建
+func (e *MySQLSearchEngine) Rebuild(books []*model.Book) error {
+	return nil
+}
+
+// Index 全文索引由MySQL维护，无需同步
+func (e *MySQLSearchEngine) Index(book *model.Book) error {
+	return nil
+}
+
+// Remove 全文索引由MySQL维护，无需同步
+func (e *MySQLSearchEngine) Remove(bookID int) error {
+	return nil
+}
+
+// Search 使用MATCH ... AGAINST按字段加权的相关度搜索上架图书
+// 参数:
+//
+//	query - 搜索条件
+//
+// 返回:
+//
+//	*SearchResult - 当前页的结果和结果总数
+//	error - 查询失败时返回错误
+func (e *MySQLSearchEngine) Search(query *SearchQuery) (*SearchResult, error) {
+	terms := analyzeSearchQuery(query.Keyword)
+	if len(terms) == 0 {
+		return &SearchResult{Hits: []SearchHit{}}, nil
+	}
+
+	// 使用规范化后的片段作为查询文本，由ngram分词器再次切分
+	var segments []string
+	for _, segment := range splitSearchText(query.Keyword) {
+		segments = append(segments, string(segment.runes))
+	}
+
+	weights := repository.BookSearchWeights{
+		Title:       searchWeightTitle,
+		Author:      searchWeightAuthor,
+		Publisher:   searchWeightPublisher,
+		Description: searchWeightDescription,
+	}
+	scores, total, err := e.bookDAO.FullTextSearch(strings.Join(segments, " "), weights, query.Offset, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]SearchHit, 0, len(scores))
+	for _, score := range scores {
+		hits = append(hits, SearchHit{
+			BookID: score.ID,
+			Score:  score.Score,
+			Terms:  terms,
+		})
+	}
+	return &SearchResult{Hits: hits, Total: total}, nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// searchSnippetLength 长文本字段高亮摘要的最大字符数
+	searchSnippetLength = 100
+	// searchSnippetLead 摘要中第一个命中词之前保留的字符数
+	searchSnippetLead = 30
+	// searchHighlightOpen 高亮片段的起始标记
+	searchHighlightOpen = "<em>"
+	// searchHighlightClose 高亮片段的结束标记
+	searchHighlightClose = "</em>"
+)
+
+// normalizeSearchRune 规范化单个字符，全角字母数字转为半角，并统一为小写
+// 每个字符只映射为一个字符，规范化前后的字符位置一一对应，便于在原文中定位高亮位置
+// 参数:
+//
+//	r - 原始字符
+//
+// 返回:
+//
+//	rune - 规范化后的字符
+func normalizeSearchRune(r rune) rune {
+	switch {
+	case r == '　':
+		r = ' '
+	case r >= '！' && r <= '～':
+		r -= 0xFEE0
+	}
+	return unicode.ToLower(r)
+}
+
+// isCJKRune 判断字符是否为中日韩文字，这类文字之间没有空格分隔，按字和相邻两字切分
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// isWordRune 判断字符是否为非中日韩的字母或数字，连续的这类字符组成一个英文单词
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r)) && !isCJKRune(r)
+}
+
+// searchSegment 文本中连续的同类字符片段
+type searchSegment struct {
+	runes []rune // 规范化后的字符
+	cjk   bool   // 是否为中日韩文字片段
+}
+
+// splitSearchText 将文本规范化后切分为英文单词和中日韩文字片段，标点和空白作为分隔符
+// 参数:
+//
+//	text - 原始文本
+//
+// 返回:
+//
+//	[]searchSegment - 按出现顺序排列的片段
+func splitSearchText(text string) []searchSegment {
+	var segments []searchSegment
+	var current []rune
+	currentCJK := false
+
+	flush := func() {
+		if len(current) > 0 {
+			segments = append(segments, searchSegment{runes: current, cjk: currentCJK})
+			current = nil
+		}
+	}
+
+	for _, r := range text {
+		r = normalizeSearchRune(r)
+		switch {
+		case isCJKRune(r):
+			if !currentCJK {
+				flush()
+			}
+			currentCJK = true
+			current = append(current, r)
+		case isWordRune(r):
+			if currentCJK {
+				flush()
+			}
+			currentCJK = false
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return segments
+}
+
+// analyzeSearchText 将字段文本切分为索引词
+// 英文单词整体作为一个词；中日韩文字同时按单字和相邻两字切分，使单字和多字查询都能命中
+// 参数:
+//
+//	text - 字段原文
+//
+// 返回:
+//
+//	[]string - 索引词，同一个词出现多次时重复返回，用于统计词频
+func analyzeSearchText(text string) []string {
+	var terms []string
+	for _, segment := range splitSearchText(text) {
+		if !segment.cjk {
+			terms = append(terms, string(segment.runes))
+			continue
+		}
+		for i := range segment.runes {
+			terms = append(terms, string(segment.runes[i]))
+			if i+1 < len(segment.runes) {
+				terms = append(terms, string(segment.runes[i:i+2]))
+			}
+		}
+	}
+	return terms
+}
+
+// analyzeSearchQuery 将搜索关键词切分为查询词
+// 英文单词整体作为一个词；中日韩文字按相邻两字切分，只有一个字时按单字查询
+// 参数:
+//
+//	keyword - 搜索关键词
+//
+// 返回:
+//
+//	[]string - 去重后的查询词，按出现顺序排列
+func analyzeSearchQuery(keyword string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	for _, segment := range splitSearchText(keyword) {
+		if !segment.cjk || len(segment.runes) == 1 {
+			add(string(segment.runes))
+			continue
+		}
+		for i := 0; i+1 < len(segment.runes); i++ {
+			add(string(segment.runes[i : i+2]))
+		}
+	}
+	return terms
+}
+
+// searchMinShouldMatch 计算文档至少需要命中的查询词数量
+// 查询词较多时允许约三分之一未命中，使关键词中个别错字仍能搜到结果
+// 参数:
+//
+//	total - 查询词总数
+//
+// 返回:
+//
+//	int - 至少需要命中的查询词数量
+func searchMinShouldMatch(total int) int {
+	return max(total-total/3, 1)
+}
+
+// editDistance 计算两个词之间的编辑距离（允许相邻字符交换），超过limit时提前返回limit+1
+// 参数:
+//
+//	a - 第一个词
+//	b - 第二个词
+//	limit - 关心的最大距离
+//
+// 返回:
+//
+//	int - 编辑距离，超过limit时为limit+1
+func editDistance(a, b []rune, limit int) int {
+	if abs(len(a)-len(b)) > limit {
+		return limit + 1
+	}
+
+	// prev2、prev、curr 分别为前两行、前一行和当前行
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return min(prev[len(b)], limit+1)
+}
+
+// maxTypos 根据英文单词长度返回允许的拼写错误数，过短的单词不做纠错以免误匹配
+// 参数:
+//
+//	term - 查询词
+//
+// 返回:
+//
+//	int - 允许的最大编辑距离
+func maxTypos(term []rune) int {
+	switch {
+	case len(term) >= 8:
+		return 2
+	case len(term) >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// abs 返回整数的绝对值
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// highlightSearchText 将字段中命中的词用<em>标签包裹，其余文本做HTML转义
+// 参数:
+//
+//	text - 字段原文
+//	terms - 需要高亮的词（已规范化），英文单词只匹配完整单词
+//	snippet - 是否截取第一个命中词附近的摘要，用于描述等长文本
+//
+// 返回:
+//
+//	string - 高亮后的HTML片段，没有命中时为空
+func highlightSearchText(text string, terms []string, snippet bool) string {
+	original := []rune(text)
+	normalized := make([]rune, len(original))
+	for i, r := range original {
+		normalized[i] = normalizeSearchRune(r)
+	}
+
+	marked := make([]bool, len(original))
+	first := -1
+	for _, term := range terms {
+		pattern := []rune(term)
+		if len(pattern) == 0 {
+			continue
+		}
+		word := !isCJKRune(pattern[0])
+		for i := 0; i+len(pattern) <= len(normalized); i++ {
+			if string(normalized[i:i+len(pattern)]) != term {
+				continue
+			}
+			end := i + len(pattern)
+			if word && ((i > 0 && isWordRune(normalized[i-1])) || (end < len(normalized) && isWordRune(normalized[end]))) {
+				continue
+			}
+			for j := i; j < end; j++ {
+				marked[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start, end := 0, len(original)
+	if snippet && len(original) > searchSnippetLength {
+		start = max(first-searchSnippetLead, 0)
+		end = min(start+searchSnippetLength, len(original))
+		start = max(end-searchSnippetLength, 0)
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			sb.WriteString(searchHighlightOpen)
+			sb.WriteString(html.EscapeString(string(original[i:j])))
+			sb.WriteString(searchHighlightClose)
+		} else {
+			sb.WriteString(html.EscapeString(string(original[i:j])))
+		}
+		i = j
+	}
+	if end < len(original) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeriesNotFound      = errors.New("丛书不存在")
+	ErrVolumeWithoutSeries = errors.New("未设置丛书时不能设置卷号")
+)
+
+// SeriesService 丛书服务
+// 负责维护丛书，以及按卷号列出丛书中的图书
+type SeriesService struct {
+	SeriesDAO *repository.SeriesDAO // 丛书数据访问对象
+	BookDAO   *repository.BookDAO   // 书籍数据访问对象
+}
+
+// NewSeriesService 创建新的丛书服务实例
+// 返回:
+//
+//	*SeriesService - 初始化好的丛书服务
+func NewSeriesService() *SeriesService {
+	return &SeriesService{
+		SeriesDAO: repository.NewSeriesDAO(),
+		BookDAO:   repository.NewBookDAO(),
+	}
+}
+
+// SeriesDetail 丛书详情
+type SeriesDetail struct {
+	Series *model.Series `json:"series"` // 丛书信息
+	Books  []*model.Book `json:"books"`  // 丛书中的上架图书，按卷号排列
+}
+
+// GetSeriesDetail 获取丛书信息及其全部上架图书
+// 参数:
+//
+//	id - 丛书ID
+//
+// 返回:
+//
+//	*SeriesDetail - 丛书详情
+//	error - 丛书不存在时返回ErrSeriesNotFound
+func (s *SeriesService) GetSeriesDetail(id int) (*SeriesDetail, error) {
+	series, err := s.getSeries(id)
+	if err != nil {
+		return nil, err
+	}
+	books, err := s.BookDAO.GetSeriesBooks(id)
+	if err != nil {
+		return nil, err
+	}
+	return &SeriesDetail{Series: series, Books: books}, nil
+}
+
+// GetSeriesList 分页获取丛书列表
+// 参数:
+//
+//	keyword - 名称关键词
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*repository.SeriesSummary - 丛书及其图书数量
+//	int64 - 丛书总数
+//	error - 错误信息
+func (s *SeriesService) GetSeriesList(keyword string, page, pageSize int) ([]*repository.SeriesSummary, int64, error) {
+	return s.SeriesDAO.GetSeriesByPage(keyword, page, pageSize)
+}
+
+// CreateSeries 创建丛书
+// 参数:
+//
+//	req - 丛书请求
+//
+// 返回:
+//
+//	*model.Series - 创建的丛书
+//	error - 错误信息
+func (s *SeriesService) CreateSeries(req *model.SeriesRequest) (*model.Series, error) {
+	series := &model.Series{Name: req.Name, Description: req.Description}
+	if err := s.SeriesDAO.CreateSeries(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// UpdateSeries 更新丛书
+// 参数:
+//
+//	id - 丛书ID
+//	req - 丛书请求
+//
+// 返回:
+//
+//	*model.Series - 更新后的丛书
+//	error - 丛书不存在时返回ErrSeriesNotFound
+func (s *SeriesService) UpdateSeries(id int, req *model.SeriesRequest) (*model.Series, error) {
+	series, err := s.getSeries(id)
+	if err != nil {
+		return nil, err
+	}
+	series.Name = req.Name
+	series.Description = req.Description
+	if err := s.SeriesDAO.UpdateSeries(series); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// DeleteSeries 删除丛书，丛书中的图书保留，移出丛书并清空卷号
+// 参数:
+//
+//	id - 丛书ID
+//
+// 返回:
+//
+//	error - 丛书不存在时返回ErrSeriesNotFound
+func (s *SeriesService) DeleteSeries(id int) error {
+	if _, err := s.getSeries(id); err != nil {
+		return err
+	}
+	return global.DBClient.Transaction(func(tx *gorm.DB) error {
+		return s.SeriesDAO.WithTx(tx).DeleteSeries(id)
+	})
+}
+
+// getSeries 根据ID获取丛书
+// 参数:
+//
+//	id - 丛书ID
+//
+// 返回:
+//
+//	*model.Series - 丛书对象指针
+//	error - 丛书不存在时返回ErrSeriesNotFound
+func (s *SeriesService) getSeries(id int) (*model.Series, error) {
+	series, err := s.SeriesDAO.GetSeriesByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSeriesNotFound
+	}
+	return series, err
+}
+
+// SeriesNeighbours 图书在丛书中的位置及前后卷
+type SeriesNeighbours struct {
+	Series   *model.Series `json:"series"`   // 丛书信息
+	Volume   int           `json:"volume"`   // 当前图书的卷号，0表示未编号
+	Total    int           `json:"total"`    // 丛书中的上架图书数量
+	Previous *model.Book   `json:"previous"` // 前一卷，没有时为空
+	Next     *model.Book   `json:"next"`     // 后一卷，没有时为空
+}
+
+// findSeriesNeighbours 在按卷号排列的丛书图书中查找当前图书的前后卷
+// 同一卷有多个版本时取排在最前的一本（销量最高），未编号的图书没有前后卷
+// 参数:
+//
+//	books - 丛书中的上架图书，按卷号、销量从高到低排列
+//	volume - 当前图书的卷号
+//
+// 返回:
+//
+//	*model.Book - 前一卷，没有时为nil
+//	*model.Book - 后一卷，没有时为nil
+func findSeriesNeighbours(books []*model.Book, volume int) (*model.Book, *model.Book) {
+	if volume <= 0 {
+		return nil, nil
+	}
+	var previous, next *model.Book
+	for _, book := range books {
+		switch {
+		case book.Volume <= 0 || book.Volume == volume:
+			continue
+		case book.Volume < volume:
+			if previous == nil || book.Volume > previous.Volume {
+				previous = book
+			}
+		case next == nil:
+			next = book
+		}
+	}
+	return previous, next
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"bookstore/global"
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShipmentService 发货服务
+// 负责订单发货、物流轨迹维护以及包裹全部签收后的订单完成
+type ShipmentService struct {
+	ShipmentDAO  *repository.ShipmentDAO // 发货记录数据访问对象
+	OrderDAO     *repository.OrderDAO    // 订单数据访问对象
+	orderService *OrderService           // 订单服务，用于执行订单状态流转
+}
+
+// CreateShipmentRequest 创建发货记录请求
+type CreateShipmentRequest struct {
+	Carrier    string `json:"carrier" binding:"required,max=50"`      // 承运物流公司（必填）
+	TrackingNo string `json:"tracking_no" binding:"required,max=100"` // 物流单号（必填）
+	Remark     string `json:"remark" binding:"max=255"`               // 备注
+}
+
+// UpdateShipmentRequest 更新发货记录请求
+// 所有字段均为可选，未填写的字段保持不变；填写了描述或状态时追加一条物流轨迹
+type UpdateShipmentRequest struct {
+	Carrier     string `json:"carrier" binding:"max=50"`      // 承运物流公司
+	TrackingNo  string `json:"tracking_no" binding:"max=100"` // 物流单号
+	Status      *int   `json:"status"`                        // 物流状态，取值见model.ShipmentStatus*常量
+	Location    string `json:"location" binding:"max=100"`    // 轨迹所在地点
+	Description string `json:"description" binding:"max=255"` // 轨迹描述
+}
+
+// NewShipmentService 创建新的发货服务实例
+// 返回:
+//
+//	*ShipmentService - 初始化好的发货服务
+func NewShipmentService() *ShipmentService {
+	return &ShipmentService{
+		ShipmentDAO:  repository.NewShipmentDAO(),
+		OrderDAO:     repository.NewOrderDAO(),
+		orderService: NewOrderService(),
+	}
+}
+
+// CreateShipment 为订单创建发货记录
+// 已支付订单的首个包裹会将订单流转为已发货；已发货订单可以继续追加包裹
+// 参数:
+//
+//	orderID - 订单ID
+//	adminID - 操作管理员ID
+//	req - 创建发货记录请求
+//
+// 返回:
+//
+//	*model.Shipment - 创建的发货记录
+//	error - 错误信息
+func (s *ShipmentService) CreateShipment(orderID, adminID int, req *CreateShipmentRequest) (*model.Shipment, error) {
+	order, err := s.OrderDAO.GetOrderByID(orderID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	now := time.Now()
+	shipment := &model.Shipment{
+		OrderID:    orderID,
+		Carrier:    req.Carrier,
+		TrackingNo: req.TrackingNo,
+		Status:     model.ShipmentStatusInTransit,
+		Remark:     req.Remark,
+		OperatorID: adminID,
+		ShippedAt:  now,
+		Events: []model.ShipmentEvent{{
+			Status:      model.ShipmentStatusInTransit,
+			Description: fmt.Sprintf("商家已发货，%s %s", req.Carrier, req.TrackingNo),
+			OccurredAt:  now,
+		}},
+	}
+	create := func(tx *gorm.DB, order *model.Order) error {
+		return s.ShipmentDAO.WithTx(tx).CreateShipment(shipment)
+	}
+
+	switch order.Status {
+	case model.OrderStatusPaid:
+		reason := fmt.Sprintf("发货: %s %s", req.Carrier, req.TrackingNo)
+		err = s.orderService.transitionOrder(orderID, model.OrderStatusShipped, model.OrderOperatorAdmin, adminID, reason, create)
+	case model.OrderStatusShipped:
+		// 追加包裹不改变订单状态，但仍需锁定订单，防止与售后、确认收货并发
+		err = global.DBClient.Transaction(func(tx *gorm.DB) error {
+			locked, err := s.OrderDAO.WithTx(tx).GetOrderForUpdate(orderID)
+			if err != nil {
+				return ErrOrderNotFound
+			}
+			if locked.Status != model.OrderStatusShipped {
+				return fmt.Errorf("订单当前状态为「%s」，无法发货", model.OrderStatusText(locked.Status))
+			}
+			return create(tx, locked)
+		})
+	default:
+		return nil, fmt.Errorf("订单当前状态为「%s」，无法发货", model.OrderStatusText(order.Status))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// UpdateShipment 更新发货记录并追加物流轨迹
+// 包裹标记为已签收后，若订单的所有包裹均已签收，订单自动完成
+// 参数:
+//
+//	shipmentID - 发货记录ID
+//	req - 更新发货记录请求
+//
+// 返回:
+//
+//	*model.Shipment - 更新后的发货记录（包含物流轨迹）
+//	error - 错误信息
+func (s *ShipmentService) UpdateShipment(shipmentID int, req *UpdateShipmentRequest) (*model.Shipment, error) {
+	if req.Status != nil && *req.Status != model.ShipmentStatusInTransit && *req.Status != model.ShipmentStatusDelivered {
+		return nil, errors.New("无效的物流状态")
+	}
+
+	var orderID int
+	var delivered bool
+	err := global.DBClient.Transaction(func(tx *gorm.DB) error {
+		shipmentDAO := s.ShipmentDAO.WithTx(tx)
+
+		shipment, err := shipmentDAO.GetShipmentForUpdate(shipmentID)
+		if err != nil {
+			return errors.New("发货记录不存在")
+		}
+		orderID = shipment.OrderID
+
+		if req.Carrier != "" {
+			shipment.Carrier = req.Carrier
+		}
+		if req.TrackingNo != "" {
+			shipment.TrackingNo = req.TrackingNo
+		}
+
+		description := req.Description
+		if req.Status != nil && *req.Status != shipment.Status {
+			// 已签收的包裹不能退回运输中
+			if shipment.Status == model.ShipmentStatusDelivered {
+				return errors.New("包裹已签收，不能变更物流状态")
+			}
+			now := time.Now()
+			shipment.Status = *req.Status
+			shipment.DeliveredAt = &now
+			delivered = true
+			if description == "" {
+				description = "包裹已签收"
+			}
+		}
+
+		if err := shipmentDAO.UpdateShipment(shipment); err != nil {
+			return err
+		}
+		if description == "" {
+			return nil
+		}
+		return shipmentDAO.CreateEvent(&model.ShipmentEvent{
+			ShipmentID:  shipment.ID,
+			Status:      shipment.Status,
+			Location:    req.Location,
+			Description: description,
+			OccurredAt:  time.Now(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if delivered {
+		s.completeIfAllDelivered(orderID)
+	}
+	return s.ShipmentDAO.GetShipmentByID(shipmentID)
+}
+
+// completeIfAllDelivered 订单的所有包裹均已签收时自动完成订单
+// 只有已发货的订单会被完成，售后处理中等其他状态的订单仅记录日志，
+// 售后驳回恢复为已发货后由自动确认收货兜底
+// 参数:
+//
+//	orderID - 订单ID
+func (s *ShipmentService) completeIfAllDelivered(orderID int) {
+	undelivered, err := s.ShipmentDAO.CountUndeliveredShipments(orderID)
+	if err != nil {
+		log.Printf("统计未签收包裹失败，订单ID: %d，错误: %v", orderID, err)
+		return
+	}
+	if undelivered > 0 {
+		return
+	}
+
+	if err := s.orderService.completeOrder(orderID, model.OrderOperatorSystem, 0, "全部包裹已签收"); err != nil {
+		log.Printf("包裹已全部签收，自动完成订单失败，订单ID: %d，错误: %v", orderID, err)
+	}
+}
+
+// GetOrderShipments 获取订单的发货记录
+// 参数:
+//
+//	orderID - 订单ID
+//
+// 返回:
+//
+//	[]*model.Shipment - 发货记录列表（包含物流轨迹）
+//	error - 错误信息
+func (s *ShipmentService) GetOrderShipments(orderID int) ([]*model.Shipment, error) {
+	return s.ShipmentDAO.GetOrderShipments(orderID)
+}
@@ -0,0 +1,701 @@
+package service
+
+import (
+	"bookstore/model"
+	"bookstore/repository"
+	"context"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 搜索建议的类型
+const (
+	SuggestTypeTitle    = "title"    // 图书标题
+	SuggestTypeAuthor   = "author"   // 作者
+	SuggestTypeCategory = "category" // 分类
+)
+
+const (
+	// suggestPopularityWeight 搜索热度相对销量的排序权重
+	suggestPopularityWeight = 2.0
+	// suggestPopularityKeep 保留热度的搜索词数量
+	suggestPopularityKeep = 10000
+	// suggestMaxQueryLength 记录热度的搜索词最大字符数，过长的搜索词通常不会被再次搜索
+	suggestMaxQueryLength = 64
+	// suggestCacheSize 缓存的输入前缀数量上限，超出后清空重新缓存
+	suggestCacheSize = 10000
+	// SuggestMaxLimit 单次最多返回的建议数量
+	SuggestMaxLimit = 20
+)
+
+// Suggestion 搜索建议
+type Suggestion struct {
+	Type       string `json:"type"`                  // 建议类型：title、author、category
+	Text       string `json:"text"`                  // 建议文本
+	BookID     int    `json:"book_id,omitempty"`     // 图书ID，仅标题建议返回
+	CategoryID int    `json:"category_id,omitempty"` // 分类ID，仅分类建议返回
+}
+
+// suggestEntry 建议索引中的一条建议
+type suggestEntry struct {
+	Suggestion
+	normalized string      // 规范化后的文本，用于去重
+	sales      map[int]int // 以图书ID为键的销量，作者和分类建议为其下所有上架图书的销量
+	keys       []string    // 该建议在前缀树中的键
+	popularity float64     // 汇总的搜索热度，见aggregatePopularity
+}
+
+// totalSales 计算建议对应图书的总销量
+func (e *suggestEntry) totalSales() int {
+	total := 0
+	for _, sale := range e.sales {
+		total += sale
+	}
+	return total
+}
+
+// suggestTrieNode 前缀树节点
+type suggestTrieNode struct {
+	children map[rune]*suggestTrieNode // 子节点
+	entries  map[*suggestEntry]bool    // 以该节点为结尾的键对应的建议，值表示该键是否从文本开头开始
+}
+
+// suggestBook 建议索引中记录的图书信息，用于图书修改或删除时撤销原有的建议
+type suggestBook struct {
+	author     string // 作者
+	categoryID int    // 分类ID
+}
+
+// SuggestIndex 搜索建议索引
+// 将上架图书的标题、作者和分类名称按前缀组织在内存前缀树中，输入时按前缀返回建议，
+// 启动时从数据库加载，后台修改图书和分类时同步更新，并定期重建以刷新销量和搜索热度
+type SuggestIndex struct {
+	bookDAO       *repository.BookDAO             // 书籍数据访问对象
+	categoryDAO   *repository.CategoryDAO         // 分类数据访问对象
+	popularityDAO *repository.SearchPopularityDAO // 搜索热度数据访问对象
+
+	rebuildMu sync.Mutex // 保证同一时间只有一次重建
+
+	mu         sync.RWMutex
+	root       *suggestTrieNode          // 前缀树根节点
+	entries    map[string]*suggestEntry  // 以类型和标识为键的建议
+	books      map[int]suggestBook       // 以图书ID为键的已索引图书
+	categories map[int]string            // 以分类ID为键的分类名称
+	popularity map[string]float64        // 以规范化搜索词为键的搜索次数
+	rebuilding bool                      // 是否正在重建
+	pending    []func(idx *SuggestIndex) // 重建期间发生的修改，替换索引前重放到新索引上
+
+	cacheMu sync.Mutex              // 保护cache
+	cache   map[string][]Suggestion // 以规范化前缀为键的排序结果，索引变化时清空
+}
+
+var (
+	suggestIndex     *SuggestIndex // 进程内共享的搜索建议索引
+	suggestIndexOnce sync.Once     // 保证搜索建议索引只创建一次
+)
+
+// getSuggestIndex 获取进程内共享的搜索建议索引
+// 返回:
+//
+//	*SuggestIndex - 搜索建议索引
+func getSuggestIndex() *SuggestIndex {
+	suggestIndexOnce.Do(func() {
+		suggestIndex = NewSuggestIndex(repository.NewBookDAO(), repository.NewCategoryDAO(), repository.NewSearchPopularityDAO())
+	})
+	return suggestIndex
+}
+
+// NewSuggestIndex 创建空的搜索建议索引
+// 参数:
+//
+//	bookDAO - 书籍数据访问对象
+//	categoryDAO - 分类数据访问对象
+//	popularityDAO - 搜索热度数据访问对象
+//
+// 返回:
+//
+//	*SuggestIndex - 搜索建议索引，需调用Rebuild加载数据
+func NewSuggestIndex(bookDAO *repository.BookDAO, categoryDAO *repository.CategoryDAO, popularityDAO *repository.SearchPopularityDAO) *SuggestIndex {
+	return &SuggestIndex{
+		bookDAO:       bookDAO,
+		categoryDAO:   categoryDAO,
+		popularityDAO: popularityDAO,
+		root:          &suggestTrieNode{},
+		entries:       make(map[string]*suggestEntry),
+		books:         make(map[int]suggestBook),
+		categories:    make(map[int]string),
+		popularity:    make(map[string]float64),
+		cache:         make(map[string][]Suggestion),
+	}
+}
+
+// InitSuggestIndex 加载图书、分类和搜索热度并建立搜索建议索引
+// 应在数据库和Redis连接初始化后、开始处理请求前调用
+// 返回:
+//
+//	error - 加载数据失败时返回错误
+func InitSuggestIndex() error {
+	return getSuggestIndex().Rebuild()
+}
+
+// Rebuild 从数据库和Redis重新加载数据并替换整个索引
+// 加载数据期间后台对图书和分类的修改会被记录下来，在替换前重放到新索引上，避免被旧数据覆盖
+// 返回:
+//
+//	error - 加载图书或分类失败时返回错误，搜索热度加载失败只记录日志
+func (s *SuggestIndex) Rebuild() error {
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+
+	s.mu.Lock()
+	s.rebuilding = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.rebuilding, s.pending = false, nil
+		s.mu.Unlock()
+	}()
+
+	books, err := s.bookDAO.GetAllBooks()
+	if err != nil {
+		return err
+	}
+	categories, err := s.categoryDAO.GetAllCategories()
+	if err != nil {
+		return err
+	}
+	popularity, err := s.popularityDAO.GetTop(suggestPopularityKeep)
+	if err != nil {
+		log.Printf("加载搜索热度失败，错误: %v", err)
+		popularity = make(map[string]float64)
+	}
+
+	// 在新索引上构建完成后再整体替换，重建期间不影响查询
+	fresh := NewSuggestIndex(s.bookDAO, s.categoryDAO, s.popularityDAO)
+	for _, category := range categories {
+		fresh.categories[category.ID] = category.Name
+	}
+	for _, book := range books {
+		fresh.addBook(book)
+	}
+	fresh.popularity = popularity
+	fresh.aggregatePopularity()
+
+	s.mu.Lock()
+	for _, op := range s.pending {
+		op(fresh)
+	}
+	s.root, s.entries, s.books, s.categories, s.popularity = fresh.root, fresh.entries, fresh.books, fresh.categories, fresh.popularity
+	s.clearCache()
+	s.mu.Unlock()
+	return nil
+}
+
+// apply 在写锁下修改索引并清空排序结果缓存
+// 正在重建时同时记录该修改，由Rebuild在替换索引前重放
+// 参数:
+//
+//	op - 修改操作，只能通过参数访问索引
+func (s *SuggestIndex) apply(op func(idx *SuggestIndex)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op(s)
+	if s.rebuilding {
+		s.pending = append(s.pending, op)
+	}
+	s.clearCache()
+}
+
+// IndexBook 新增或更新一本图书的建议，下架的图书只撤销原有的建议
+// 参数:
+//
+//	book - 图书信息
+func (s *SuggestIndex) IndexBook(book *model.Book) {
+	snapshot := *book
+	s.apply(func(idx *SuggestIndex) {
+		idx.removeBook(snapshot.ID)
+		idx.addBook(&snapshot)
+	})
+}
+
+// RemoveBook 撤销一本图书的建议
+// 参数:
+//
+//	bookID - 图书ID
+func (s *SuggestIndex) RemoveBook(bookID int) {
+	s.apply(func(idx *SuggestIndex) {
+		idx.removeBook(bookID)
+	})
+}
+
+// IndexCategory 新增分类或更新分类名称
+// 参数:
+//
+//	category - 分类信息
+func (s *SuggestIndex) IndexCategory(category *model.Category) {
+	id, name := category.ID, category.Name
+	s.apply(func(idx *SuggestIndex) {
+		idx.categories[id] = name
+		entry, ok := idx.entries[categorySuggestKey(id)]
+		if !ok || entry.Text == name {
+			return
+		}
+		// 分类改名后按新名称重新加入前缀树
+		idx.removeFromTrie(entry)
+		entry.Text = name
+		entry.normalized = normalizeSuggestText(name)
+		idx.insertIntoTrie(entry)
+		entry.popularity = idx.directPopularity(entry)
+	})
+}
+
+// RemoveCategory 撤销一个分类的建议
+// 参数:
+//
+//	categoryID - 分类ID
+func (s *SuggestIndex) RemoveCategory(categoryID int) {
+	s.apply(func(idx *SuggestIndex) {
+		delete(idx.categories, categoryID)
+		if entry, ok := idx.entries[categorySuggestKey(categoryID)]; ok {
+			idx.removeFromTrie(entry)
+			delete(idx.entries, categorySuggestKey(categoryID))
+		}
+	})
+}
+
+// RecordSearch 记录一次搜索，提高该搜索词对应建议的排名
+// 搜索次数在定期重建时汇总到建议上，见aggregatePopularity
+// 参数:
+//
+//	keyword - 用户输入的搜索关键词
+func (s *SuggestIndex) RecordSearch(keyword string) {
+	normalized := normalizeSuggestText(keyword)
+	if normalized == "" || len([]rune(normalized)) > suggestMaxQueryLength {
+		return
+	}
+
+	count, err := s.popularityDAO.Increment(normalized, suggestPopularityKeep)
+	if err != nil {
+		log.Printf("记录搜索热度失败，搜索词: %s，错误: %v", normalized, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.popularity[normalized] = count
+	s.mu.Unlock()
+}
+
+// Suggest 按前缀返回搜索建议
+// 标题、作者和分类名称的开头或其中任一单词、汉字开头与输入匹配即可；从文本开头匹配的建议排在前面，
+// 其次按销量和搜索热度综合排序。同一前缀的排序结果会被缓存，直到索引发生变化
+// 参数:
+//
+//	prefix - 用户已输入的内容
+//	limit - 返回的最大数量，不超过SuggestMaxLimit
+//
+// 返回:
+//
+//	[]Suggestion - 搜索建议，相同文本的建议只返回一条
+func (s *SuggestIndex) Suggest(prefix string, limit int) []Suggestion {
+	query := normalizeSuggestText(prefix)
+	if query == "" || limit <= 0 {
+		return []Suggestion{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.cacheMu.Lock()
+	suggestions, ok := s.cache[query]
+	s.cacheMu.Unlock()
+	if !ok {
+		suggestions = s.rank(query)
+		s.cacheMu.Lock()
+		if len(s.cache) >= suggestCacheSize {
+			s.cache = make(map[string][]Suggestion)
+		}
+		s.cache[query] = suggestions
+		s.cacheMu.Unlock()
+	}
+	return suggestions[:min(limit, len(suggestions))]
+}
+
+// rank 查找与前缀匹配的建议并排序，调用方需持有读锁
+// 参数:
+//
+//	query - 规范化后的前缀
+//
+// 返回:
+//
+//	[]Suggestion - 排序去重后的前SuggestMaxLimit条建议
+func (s *SuggestIndex) rank(query string) []Suggestion {
+	suggestions := []Suggestion{}
+	node := s.findNode(query)
+	if node == nil {
+		return suggestions
+	}
+
+	// 收集前缀节点下的全部建议，同一建议有多个键命中时取从开头匹配的结果
+	matches := make(map[*suggestEntry]bool)
+	collectSuggestEntries(node, matches)
+
+	type rankedEntry struct {
+		entry   *suggestEntry
+		leading bool
+		score   float64
+	}
+	ranked := make([]rankedEntry, 0, len(matches))
+	for entry, leading := range matches {
+		score := math.Log1p(float64(entry.totalSales())) + suggestPopularityWeight*math.Log1p(entry.popularity)
+		ranked = append(ranked, rankedEntry{entry: entry, leading: leading, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.leading != b.leading {
+			return a.leading
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if len(a.entry.normalized) != len(b.entry.normalized) {
+			return len(a.entry.normalized) < len(b.entry.normalized)
+		}
+		return a.entry.Text < b.entry.Text
+	})
+
+	seen := make(map[string]bool)
+	for _, item := range ranked {
+		key := item.entry.Type + ":" + item.entry.normalized
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		suggestions = append(suggestions, item.entry.Suggestion)
+		if len(suggestions) >= SuggestMaxLimit {
+			break
+		}
+	}
+	return suggestions
+}
+
+// findNode 查找前缀对应的前缀树节点，调用方需持有读锁
+// 参数:
+//
+//	query - 规范化后的前缀
+//
+// 返回:
+//
+//	*suggestTrieNode - 前缀对应的节点，没有建议以该前缀开头时返回nil
+func (s *SuggestIndex) findNode(query string) *suggestTrieNode {
+	node := s.root
+	for _, r := range query {
+		if node = node.children[r]; node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// collectSuggestEntries 收集节点及其子孙节点下的全部建议
+// 参数:
+//
+//	node - 起始节点
+//	matches - 收集结果，值表示是否有键从文本开头匹配
+func collectSuggestEntries(node *suggestTrieNode, matches map[*suggestEntry]bool) {
+	for entry, leading := range node.entries {
+		matches[entry] = matches[entry] || leading
+	}
+	for _, child := range node.children {
+		collectSuggestEntries(child, matches)
+	}
+}
+
+// aggregatePopularity 将搜索词的搜索次数汇总到建议上，调用方需持有写锁
+// 搜索词与建议的匹配方式和输入前缀相同，一个搜索词会计入所有能被它匹配到的建议，
+// 如搜索“三体”同时计入“三体”“三体 II 黑暗森林”等标题；匹配到的标题还会计入该图书的作者建议，
+// 使搜索书名也能提高作者的排名。分类下图书众多，只计入直接匹配的搜索词
+func (s *SuggestIndex) aggregatePopularity() {
+	for _, entry := range s.entries {
+		entry.popularity = 0
+	}
+	for term, count := range s.popularity {
+		node := s.findNode(term)
+		if node == nil {
+			continue
+		}
+		matches := make(map[*suggestEntry]bool)
+		collectSuggestEntries(node, matches)
+
+		for entry := range matches {
+			if entry.Type != SuggestTypeTitle {
+				continue
+			}
+			if book, ok := s.books[entry.BookID]; ok {
+				if author, ok := s.entries[authorSuggestKey(book.author)]; ok {
+					matches[author] = true
+				}
+			}
+		}
+		for entry := range matches {
+			entry.popularity += count
+		}
+	}
+}
+
+// directPopularity 计算能直接匹配到建议的搜索词的搜索次数之和，调用方需持有写锁
+// 用于重建之间新加入的建议，来自标题的作者热度在下次重建时汇总
+// 参数:
+//
+//	entry - 已加入前缀树的建议
+//
+// 返回:
+//
+//	float64 - 搜索次数之和
+func (s *SuggestIndex) directPopularity(entry *suggestEntry) float64 {
+	var total float64
+	for term, count := range s.popularity {
+		for _, key := range entry.keys {
+			if strings.HasPrefix(key, term) {
+				total += count
+				break
+			}
+		}
+	}
+	return total
+}
+
+// clearCache 清空排序结果缓存，调用方需持有写锁
+func (s *SuggestIndex) clearCache() {
+	s.cacheMu.Lock()
+	s.cache = make(map[string][]Suggestion)
+	s.cacheMu.Unlock()
+}
+
+// addBook 将上架图书的标题、作者和分类加入索引，调用方需持有写锁
+func (s *SuggestIndex) addBook(book *model.Book) {
+	if book.Status != 1 {
+		return
+	}
+
+	categoryID := int(book.CategoryID)
+	s.books[book.ID] = suggestBook{author: book.Author, categoryID: categoryID}
+
+	s.addSale(titleSuggestKey(book.ID), Suggestion{Type: SuggestTypeTitle, Text: book.Title, BookID: book.ID}, book.ID, book.Sale)
+	if normalizeSuggestText(book.Author) != "" {
+		s.addSale(authorSuggestKey(book.Author), Suggestion{Type: SuggestTypeAuthor, Text: book.Author}, book.ID, book.Sale)
+	}
+	if name, ok := s.categories[categoryID]; ok {
+		s.addSale(categorySuggestKey(categoryID), Suggestion{Type: SuggestTypeCategory, Text: name, CategoryID: categoryID}, book.ID, book.Sale)
+	}
+}
+
+// removeBook 撤销图书对标题、作者和分类建议的贡献，调用方需持有写锁
+func (s *SuggestIndex) removeBook(bookID int) {
+	book, ok := s.books[bookID]
+	if !ok {
+		return
+	}
+
+	delete(s.books, bookID)
+	s.removeSale(titleSuggestKey(bookID), bookID)
+	s.removeSale(authorSuggestKey(book.author), bookID)
+	s.removeSale(categorySuggestKey(book.categoryID), bookID)
+}
+
+// addSale 将图书计入建议，建议不存在时创建并加入前缀树，调用方需持有写锁
+func (s *SuggestIndex) addSale(key string, suggestion Suggestion, bookID, sale int) {
+	entry, ok := s.entries[key]
+	if !ok {
+		normalized := normalizeSuggestText(suggestion.Text)
+		if normalized == "" {
+			return
+		}
+		entry = &suggestEntry{
+			Suggestion: suggestion,
+			normalized: normalized,
+			sales:      make(map[int]int),
+		}
+		s.entries[key] = entry
+		s.insertIntoTrie(entry)
+		entry.popularity = s.directPopularity(entry)
+	}
+	entry.sales[bookID] = sale
+}
+
+// removeSale 将图书从建议中移除，建议不再对应任何图书时从前缀树中删除，调用方需持有写锁
+func (s *SuggestIndex) removeSale(key string, bookID int) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(entry.sales, bookID)
+	if len(entry.sales) == 0 {
+		s.removeFromTrie(entry)
+		delete(s.entries, key)
+	}
+}
+
+// insertIntoTrie 将建议按文本开头及其中每个单词、汉字开头的后缀加入前缀树，调用方需持有写锁
+func (s *SuggestIndex) insertIntoTrie(entry *suggestEntry) {
+	runes := []rune(entry.normalized)
+	entry.keys = nil
+	for i := range runes {
+		// 英文单词只从单词开头匹配，汉字可以从任意一个字开始匹配
+		start := i == 0 || isCJKRune(runes[i]) || (runes[i] != ' ' && (runes[i-1] == ' ' || isCJKRune(runes[i-1])))
+		if !start {
+			continue
+		}
+		key := string(runes[i:])
+		entry.keys = append(entry.keys, key)
+
+		node := s.root
+		for _, r := range key {
+			if node.children == nil {
+				node.children = make(map[rune]*suggestTrieNode)
+			}
+			child, ok := node.children[r]
+			if !ok {
+				child = &suggestTrieNode{}
+				node.children[r] = child
+			}
+			node = child
+		}
+		if node.entries == nil {
+			node.entries = make(map[*suggestEntry]bool)
+		}
+		node.entries[entry] = i == 0
+	}
+}
+
+// removeFromTrie 将建议从前缀树中删除，并清理不再使用的节点，调用方需持有写锁
+func (s *SuggestIndex) removeFromTrie(entry *suggestEntry) {
+	for _, key := range entry.keys {
+		removeSuggestKey(s.root, []rune(key), entry)
+	}
+	entry.keys = nil
+}
+
+// removeSuggestKey 从节点开始沿键删除建议
+// 参数:
+//
+//	node - 当前节点
+//	key - 剩余的键
+//	entry - 要删除的建议
+//
+// 返回:
+//
+//	bool - 当前节点是否已经为空，可由父节点删除
+func removeSuggestKey(node *suggestTrieNode, key []rune, entry *suggestEntry) bool {
+	if len(key) == 0 {
+		delete(node.entries, entry)
+	} else if child, ok := node.children[key[0]]; ok && removeSuggestKey(child, key[1:], entry) {
+		delete(node.children, key[0])
+	}
+	return len(node.entries) == 0 && len(node.children) == 0
+}
+
+// normalizeSuggestText 规范化建议文本和用户输入，统一大小写和全角半角，标点和连续空白合并为一个空格
+// 参数:
+//
+//	text - 原始文本
+//
+// 返回:
+//
+//	string - 规范化后的文本
+func normalizeSuggestText(text string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range text {
+		r = normalizeSearchRune(r)
+		if isCJKRune(r) || isWordRune(r) {
+			if space && sb.Len() > 0 {
+				sb.WriteRune(' ')
+			}
+			space = false
+			sb.WriteRune(r)
+			continue
+		}
+		space = true
+	}
+	return sb.String()
+}
+
+// titleSuggestKey 图书标题建议的标识，每本图书一条
+func titleSuggestKey(bookID int) string {
+	return SuggestTypeTitle + ":" + strconv.Itoa(bookID)
+}
+
+// authorSuggestKey 作者建议的标识，同名作者的图书合并为一条
+func authorSuggestKey(author string) string {
+	return SuggestTypeAuthor + ":" + normalizeSuggestText(author)
+}
+
+// categorySuggestKey 分类建议的标识，每个分类一条
+func categorySuggestKey(categoryID int) string {
+	return SuggestTypeCategory + ":" + strconv.Itoa(categoryID)
+}
+
+// SuggestRefreshWorker 搜索建议定期重建处理器
+// 图书销量随订单变化，不经过后台修改，因此定期从数据库重建索引以刷新排序
+type SuggestRefreshWorker struct {
+	index    *SuggestIndex      // 搜索建议索引
+	interval time.Duration      // 重建间隔
+	cancel   context.CancelFunc // 用于通知后台协程退出
+	wg       sync.WaitGroup     // 等待后台协程退出
+}
+
+// NewSuggestRefreshWorker 创建搜索建议定期重建处理器
+// 参数:
+//
+//	interval - 重建间隔
+//
+// 返回:
+//
+//	*SuggestRefreshWorker - 初始化好的重建处理器
+func NewSuggestRefreshWorker(interval time.Duration) *SuggestRefreshWorker {
+	return &SuggestRefreshWorker{
+		index:    getSuggestIndex(),
+		interval: interval,
+	}
+}
+
+// Start 启动后台重建协程
+func (w *SuggestRefreshWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		log.Printf("搜索建议重建处理器已启动，重建间隔: %v", w.interval)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := w.index.Rebuild(); err != nil {
+					log.Printf("重建搜索建议索引失败: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止后台重建协程，并等待正在进行的重建完成
+func (w *SuggestRefreshWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+	log.Println("搜索建议重建处理器已停止")
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTagNotFound     = errors.New("标签不存在")
+	ErrTagSlugInvalid  = errors.New("标签别名只能包含小写字母、数字和连字符，且不能以连字符开头或结尾")
+	ErrTagBookNotFound = errors.New("部分图书不存在")
+)
+
+// tagSlugPattern 标签别名格式：小写字母和数字，以单个连字符分隔
+var tagSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// TagService 标签服务
+// 负责标签的维护、批量为图书打标签以及按标签浏览图书
+type TagService struct {
+	TagDAO  *repository.TagDAO  // 标签数据访问对象
+	BookDAO *repository.BookDAO // 书籍数据访问对象
+}
+
+// NewTagService 创建新的标签服务实例
+// 返回:
+//
+//	*TagService - 初始化好的标签服务
+func NewTagService() *TagService {
+	return &TagService{
+		TagDAO:  repository.NewTagDAO(),
+		BookDAO: repository.NewBookDAO(),
+	}
+}
+
+// TagDetail 标签详情
+type TagDetail struct {
+	Tag   *model.Tag    `json:"tag"`   // 标签信息
+	Books []*model.Book `json:"books"` // 当前页的上架图书
+	Total int64         `json:"total"` // 上架图书总数
+}
+
+// GetTagBooks 获取标签信息及带有该标签的分页上架图书
+// 参数:
+//
+//	slug - 标签别名
+//	req - 分页、排序和筛选条件，其中的标签条件与该标签同时生效
+//
+// 返回:
+//
+//	*TagDetail - 标签详情
+//	error - 标签不存在时返回ErrTagNotFound
+func (t *TagService) GetTagBooks(slug string, req *model.BookQueryRequest) (*TagDetail, error) {
+	tag, err := t.TagDAO.GetTagBySlug(strings.ToLower(slug))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	filter := bookFilterFromRequest(req)
+	filter.Tags = append(filter.Tags, tag.Slug)
+	books, total, err := t.BookDAO.GetBooksByFilter(filter, req.Sort, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &TagDetail{Tag: tag, Books: books, Total: total}, nil
+}
+
+// GetPublicTags 获取带有上架图书的全部标签，供前台按标签浏览
+// 返回:
+//
+//	[]*repository.TagSummary - 标签及其上架图书数量
+//	error - 错误信息
+func (t *TagService) GetPublicTags() ([]*repository.TagSummary, error) {
+	return t.TagDAO.GetTagsWithBookCount()
+}
+
+// GetTags 分页获取标签列表（管理员）
+// 参数:
+//
+//	keyword - 名称或别名关键词
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*repository.TagSummary - 标签及其图书数量
+//	int64 - 标签总数
+//	error - 错误信息
+func (t *TagService) GetTags(keyword string, page, pageSize int) ([]*repository.TagSummary, int64, error) {
+	return t.TagDAO.GetTagsByPage(keyword, page, pageSize)
+}
+
+// CreateTag 创建标签
+// 参数:
+//
+//	req - 标签请求
+//
+// 返回:
+//
+//	*model.Tag - 创建的标签
+//	error - 别名格式错误时返回ErrTagSlugInvalid
+func (t *TagService) CreateTag(req *model.TagRequest) (*model.Tag, error) {
+	tag := &model.Tag{}
+	if err := applyTagRequest(tag, req); err != nil {
+		return nil, err
+	}
+	if err := t.TagDAO.CreateTag(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// UpdateTag 更新标签
+// 修改别名后，使用旧别名的链接将无法访问
+// 参数:
+//
+//	id - 标签ID
+//	req - 标签请求
+//
+// 返回:
+//
+//	*model.Tag - 更新后的标签
+//	error - 标签不存在时返回ErrTagNotFound，别名格式错误时返回ErrTagSlugInvalid
+func (t *TagService) UpdateTag(id int, req *model.TagRequest) (*model.Tag, error) {
+	tag, err := t.getTag(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyTagRequest(tag, req); err != nil {
+		return nil, err
+	}
+	if err := t.TagDAO.UpdateTag(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteTag 删除标签，同时移除所有图书上的该标签
+// 参数:
+//
+//	id - 标签ID
+//
+// 返回:
+//
+//	error - 标签不存在时返回ErrTagNotFound
+func (t *TagService) DeleteTag(id int) error {
+	if _, err := t.getTag(id); err != nil {
+		return err
+	}
+	return t.TagDAO.DeleteTag(id)
+}
+
+// AddBookTags 批量为图书添加标签，已有的标签保持不变
+// 参数:
+//
+//	req - 图书ID和标签ID
+//
+// 返回:
+//
+//	int64 - 新增的图书标签数量
+//	error - 图书或标签不存在时返回错误
+func (t *TagService) AddBookTags(req *model.BookTagRequest) (int64, error) {
+	bookIDs, tagIDs, err := t.checkBookTagRequest(req)
+	if err != nil {
+		return 0, err
+	}
+	return t.TagDAO.AddBookTags(bookIDs, tagIDs)
+}
+
+// RemoveBookTags 批量移除图书的标签
+// 参数:
+//
+//	req - 图书ID和标签ID
+//
+// 返回:
+//
+//	int64 - 移除的图书标签数量
+//	error - 错误信息
+func (t *TagService) RemoveBookTags(req *model.BookTagRequest) (int64, error) {
+	return t.TagDAO.RemoveBookTags(req.BookIDs, req.TagIDs)
+}
+
+// checkBookTagRequest 去除重复ID并检查图书和标签是否都存在
+// 参数:
+//
+//	req - 图书ID和标签ID
+//
+// 返回:
+//
+//	[]int - 去重后的图书ID
+//	[]int - 去重后的标签ID
+//	error - 图书不存在时返回ErrTagBookNotFound，标签不存在时返回ErrTagNotFound
+func (t *TagService) checkBookTagRequest(req *model.BookTagRequest) ([]int, []int, error) {
+	bookIDs := slices.Clone(req.BookIDs)
+	slices.Sort(bookIDs)
+	bookIDs = slices.Compact(bookIDs)
+	tagIDs := slices.Clone(req.TagIDs)
+	slices.Sort(tagIDs)
+	tagIDs = slices.Compact(tagIDs)
+
+	books, err := t.BookDAO.GetBooksByIDs(bookIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(books) != len(bookIDs) {
+		return nil, nil, ErrTagBookNotFound
+	}
+	count, err := t.TagDAO.CountTagsByIDs(tagIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if count != int64(len(tagIDs)) {
+		return nil, nil, ErrTagNotFound
+	}
+	return bookIDs, tagIDs, nil
+}
+
+// getTag 根据ID获取标签
+// 参数:
+//
+//	id - 标签ID
+//
+// 返回:
+//
+//	*model.Tag - 标签对象指针
+//	error - 标签不存在时返回ErrTagNotFound
+func (t *TagService) getTag(id int) (*model.Tag, error) {
+	tag, err := t.TagDAO.GetTagByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTagNotFound
+	}
+	return tag, err
+}
+
+// applyTagRequest 校验标签请求并写入标签，别名统一转为小写
+// 参数:
+//
+//	tag - 待写入的标签
+//	req - 标签请求
+//
+// 返回:
+//
+//	error - 别名格式错误时返回ErrTagSlugInvalid
+func applyTagRequest(tag *model.Tag, req *model.TagRequest) error {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !tagSlugPattern.MatchString(slug) {
+		return ErrTagSlugInvalid
+	}
+	tag.Name = strings.TrimSpace(req.Name)
+	tag.Slug = slug
+	tag.Description = req.Description
+	return nil
+}
@@ -17,10 +17,11 @@ type UserService struct {
 
 // LoginResponse 登录响应数据结构
 type LoginResponse struct {
-	AccessToken  string    `json:"access_token"`  // 访问令牌
-	RefreshToken string    `json:"refresh_token"` // 刷新令牌
-	ExpiresIn    int64     `json:"expires_in"`    // 过期时间(秒)
-	UserInfo     *UserInfo `json:"user_info"`     // 用户基本信息
+	AccessToken  string           `json:"access_token"`         // 访问令牌
+	RefreshToken string           `json:"refresh_token"`        // 刷新令牌
+	ExpiresIn    int64            `json:"expires_in"`           // 过期时间(秒)
+	UserInfo     *UserInfo        `json:"user_info"`            // 用户基本信息
+	CartMerge    *CartMergeResult `json:"cart_merge,omitempty"` // 访客购物车合并结果，登录时携带了访客购物车令牌才返回
 }
 
 // UserInfo 用户基本信息结构
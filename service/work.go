@@ -0,0 +1,115 @@
+package service
+
+import (
+	"bookstore/model"
+	"bookstore/repository"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var ErrWorkNotFound = errors.New("作品不存在")
+
+// WorkService 作品服务
+// 负责维护作品，同一作品的不同版本通过作品归为一组
+type WorkService struct {
+	WorkDAO *repository.WorkDAO // 作品数据访问对象
+}
+
+// NewWorkService 创建新的作品服务实例
+// 返回:
+//
+//	*WorkService - 初始化好的作品服务
+func NewWorkService() *WorkService {
+	return &WorkService{
+		WorkDAO: repository.NewWorkDAO(),
+	}
+}
+
+// GetWorks 分页获取作品列表
+// 参数:
+//
+//	keyword - 名称关键词
+//	page - 页码
+//	pageSize - 每页数量
+//
+// 返回:
+//
+//	[]*repository.WorkSummary - 作品及其版本数量
+//	int64 - 作品总数
+//	error - 错误信息
+func (w *WorkService) GetWorks(keyword string, page, pageSize int) ([]*repository.WorkSummary, int64, error) {
+	return w.WorkDAO.GetWorksByPage(keyword, page, pageSize)
+}
+
+// CreateWork 创建作品
+// 参数:
+//
+//	req - 作品请求
+//
+// 返回:
+//
+//	*model.Work - 创建的作品
+//	error - 错误信息
+func (w *WorkService) CreateWork(req *model.WorkRequest) (*model.Work, error) {
+	work := &model.Work{Title: req.Title, Description: req.Description}
+	if err := w.WorkDAO.CreateWork(work); err != nil {
+		return nil, err
+	}
+	return work, nil
+}
+
+// UpdateWork 更新作品
+// 参数:
+//
+//	id - 作品ID
+//	req - 作品请求
+//
+// 返回:
+//
+//	*model.Work - 更新后的作品
+//	error - 作品不存在时返回ErrWorkNotFound
+func (w *WorkService) UpdateWork(id int, req *model.WorkRequest) (*model.Work, error) {
+	work, err := w.getWork(id)
+	if err != nil {
+		return nil, err
+	}
+	work.Title = req.Title
+	work.Description = req.Description
+	if err := w.WorkDAO.UpdateWork(work); err != nil {
+		return nil, err
+	}
+	return work, nil
+}
+
+// DeleteWork 删除作品，作品下的图书保留，只是不再归为一组
+// 参数:
+//
+//	id - 作品ID
+//
+// 返回:
+//
+//	error - 作品不存在时返回ErrWorkNotFound
+func (w *WorkService) DeleteWork(id int) error {
+	if _, err := w.getWork(id); err != nil {
+		return err
+	}
+	return w.WorkDAO.DeleteWork(id)
+}
+
+// getWork 根据ID获取作品
+// 参数:
+//
+//	id - 作品ID
+//
+// 返回:
+//
+//	*model.Work - 作品对象指针
+//	error - 作品不存在时返回ErrWorkNotFound
+func (w *WorkService) getWork(id int) (*model.Work, error) {
+	work, err := w.WorkDAO.GetWorkByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWorkNotFound
+	}
+	return work, err
+}
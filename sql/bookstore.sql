@@ -20,18 +20,81 @@ CREATE TABLE users (
 CREATE TABLE categories (
     id INT AUTO_INCREMENT PRIMARY KEY,
     name VARCHAR(50) NOT NULL COMMENT '分类名称',
+    parent_id INT DEFAULT NULL COMMENT '父分类ID，NULL表示顶级分类',
+    path VARCHAR(255) NOT NULL DEFAULT '' COMMENT '物化路径，由根分类到自身的ID组成，如/1/5/12/',
+    depth INT NOT NULL DEFAULT 0 COMMENT '层级深度，顶级分类为0',
     description VARCHAR(200) DEFAULT NULL COMMENT '分类描述',
     icon VARCHAR(20) DEFAULT NULL COMMENT '分类图标',
     color VARCHAR(20) DEFAULT NULL COMMENT '分类颜色',
     gradient VARCHAR(100) DEFAULT NULL COMMENT '渐变色彩',
     sort INT DEFAULT 0 COMMENT '排序权重',
     is_active BOOLEAN DEFAULT TRUE COMMENT '是否启用',
-    book_count INT DEFAULT 0 COMMENT '该分类下的图书数量',
+    book_count INT DEFAULT 0 COMMENT '直属该分类的上架图书数量',
+    off_shelf_book_count INT DEFAULT 0 COMMENT '直属该分类的下架图书数量',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    UNIQUE KEY uk_name (name)
+    UNIQUE KEY uk_name (name),
+    KEY idx_parent_id (parent_id),
+    KEY idx_path (path),
+    -- 有子分类的分类不能删除，需先移走或删除子分类
+    FOREIGN KEY (parent_id) REFERENCES categories(id) ON DELETE RESTRICT
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='图书分类表';
 
+-- 创建作者表（著者、译者、编者）
+CREATE TABLE authors (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '作者姓名',
+    bio TEXT COMMENT '作者简介',
+    photo_url VARCHAR(255) DEFAULT NULL COMMENT '作者照片URL',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作者表';
+
+-- 创建作者别名表，合并重复作者时保留被合并作者的姓名
+CREATE TABLE author_aliases (
+    name VARCHAR(100) NOT NULL PRIMARY KEY COMMENT '别名',
+    author_id INT NOT NULL COMMENT '对应的作者ID',
+    FOREIGN KEY (author_id) REFERENCES authors(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作者别名表';
+
+-- 创建出版社表
+CREATE TABLE publishers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '出版社名称',
+    description TEXT COMMENT '出版社简介',
+    website VARCHAR(255) DEFAULT NULL COMMENT '出版社网站',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='出版社表';
+
+-- 创建出版社别名表，合并重复出版社时保留被合并出版社的名称
+CREATE TABLE publisher_aliases (
+    name VARCHAR(100) NOT NULL PRIMARY KEY COMMENT '别名',
+    publisher_id INT NOT NULL COMMENT '对应的出版社ID',
+    FOREIGN KEY (publisher_id) REFERENCES publishers(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='出版社别名表';
+
+-- 创建作品表，同一作品的不同版本（装帧、版次）归为一组
+CREATE TABLE works (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL COMMENT '作品名称',
+    description TEXT COMMENT '作品简介',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='作品表';
+
+-- 创建丛书表
+CREATE TABLE series (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL COMMENT '丛书名称',
+    description TEXT COMMENT '丛书简介',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='丛书表';
+
 -- 创建书籍表
 CREATE TABLE books (
     id INT AUTO_INCREMENT PRIMARY KEY,
@@ -42,21 +105,73 @@ CREATE TABLE books (
     type VARCHAR(50),
     category_id INT DEFAULT NULL COMMENT '分类ID',
     stock INT DEFAULT 0,
+    reserved INT DEFAULT 0 COMMENT '待支付订单预留的库存数量',
     status TINYINT(1) DEFAULT 1 COMMENT '图书状态：0-下架，1-上架',
     description TEXT,
     cover_url VARCHAR(255),
     isbn VARCHAR(20),
     publisher VARCHAR(100),
+    publisher_id INT DEFAULT NULL COMMENT '出版社ID，由出版社名称关联',
     publish_date VARCHAR(50),
     pages INT,
     language VARCHAR(20) DEFAULT '中文',
     format VARCHAR(20) DEFAULT '平装',
+    work_id INT DEFAULT NULL COMMENT '所属作品ID，同一作品的不同版本共用',
+    edition VARCHAR(50) DEFAULT '' COMMENT '版本说明，如第2版',
+    series_id INT DEFAULT NULL COMMENT '所属丛书ID',
+    volume INT DEFAULT 0 COMMENT '在丛书中的卷号，0表示未编号',
     sale INT DEFAULT 0 COMMENT '销售量',
+    rating DECIMAL(3,1) DEFAULT 0 COMMENT '评分（0-10分），0表示暂无评分',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL,
+    FOREIGN KEY (publisher_id) REFERENCES publishers(id) ON DELETE SET NULL,
+    FOREIGN KEY (work_id) REFERENCES works(id) ON DELETE SET NULL,
+    FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE SET NULL,
+    KEY idx_series_volume (series_id, volume),
+    -- 全文索引使用ngram分词器（默认按相邻两字切分），供search.engine为mysql时的图书搜索使用；
+    -- 组合索引用于筛选，单列索引用于按字段加权计算相关度
+    FULLTEXT KEY ft_books_search (title, author, publisher, description) WITH PARSER ngram,
+    FULLTEXT KEY ft_books_title (title) WITH PARSER ngram,
+    FULLTEXT KEY ft_books_author (author) WITH PARSER ngram,
+    FULLTEXT KEY ft_books_publisher (publisher) WITH PARSER ngram,
+    FULLTEXT KEY ft_books_description (description) WITH PARSER ngram
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 创建图书作者关联表
+CREATE TABLE book_authors (
+    book_id INT NOT NULL COMMENT '图书ID',
+    author_id INT NOT NULL COMMENT '作者ID',
+    role VARCHAR(20) NOT NULL DEFAULT 'author' COMMENT '署名角色：author-著者，translator-译者，editor-编者',
+    sort INT NOT NULL DEFAULT 0 COMMENT '署名顺序',
+    PRIMARY KEY (book_id, author_id, role),
+    KEY idx_author_id (author_id),
+    FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES authors(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='图书作者关联表';
+
+-- 创建标签表
+CREATE TABLE tags (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL COMMENT '标签名称',
+    slug VARCHAR(50) NOT NULL COMMENT '标签别名，用于链接，只含小写字母、数字和连字符',
+    description VARCHAR(255) DEFAULT '' COMMENT '标签说明',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_name (name),
+    UNIQUE KEY uk_slug (slug)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='标签表';
+
+-- 创建图书标签关联表
+CREATE TABLE book_tags (
+    book_id INT NOT NULL COMMENT '图书ID',
+    tag_id INT NOT NULL COMMENT '标签ID',
+    PRIMARY KEY (book_id, tag_id),
+    KEY idx_tag_id (tag_id),
+    FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='图书标签关联表';
+
 -- 创建收藏表
 CREATE TABLE favorites (
     id INT AUTO_INCREMENT PRIMARY KEY,
@@ -69,15 +184,44 @@ CREATE TABLE favorites (
     UNIQUE KEY unique_user_book (user_id, book_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 创建收货地址表
+CREATE TABLE addresses (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    receiver_name VARCHAR(50) NOT NULL COMMENT '收货人姓名',
+    phone VARCHAR(20) NOT NULL COMMENT '收货人手机号',
+    province VARCHAR(50) NOT NULL COMMENT '省份',
+    city VARCHAR(50) NOT NULL COMMENT '城市',
+    district VARCHAR(50) NOT NULL DEFAULT '' COMMENT '区县',
+    detail VARCHAR(255) NOT NULL COMMENT '详细地址',
+    postal_code VARCHAR(20) NOT NULL DEFAULT '' COMMENT '邮政编码',
+    is_default BOOLEAN DEFAULT FALSE COMMENT '是否为默认地址',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='收货地址表';
+
 -- 创建订单表
 CREATE TABLE orders (
     id INT AUTO_INCREMENT PRIMARY KEY,
     user_id INT NOT NULL,
     order_no VARCHAR(50) NOT NULL COMMENT '订单号',
-    total_amount INT NOT NULL COMMENT '总金额（元）',
-    status TINYINT DEFAULT 0 COMMENT '订单状态：0-待支付，1-已支付，2-已取消',
+    total_amount INT NOT NULL COMMENT '应付金额（元），即商品金额减去优惠金额',
+    subtotal_amount INT DEFAULT 0 COMMENT '商品金额（元）',
+    discount_amount INT DEFAULT 0 COMMENT '优惠金额（元）',
+    coupon_code VARCHAR(50) DEFAULT NULL COMMENT '使用的优惠码',
+    status TINYINT DEFAULT 0 COMMENT '订单状态：0-待支付，1-已支付，2-已取消，3-已发货，4-已完成，5-退款中，6-已退款，7-部分退款',
+    refunded_amount INT DEFAULT 0 COMMENT '已退款金额（元）',
     is_paid BOOLEAN DEFAULT FALSE COMMENT '是否已支付',
     payment_time TIMESTAMP NULL DEFAULT NULL COMMENT '支付时间',
+    shipping_receiver_name VARCHAR(50) NOT NULL DEFAULT '' COMMENT '收货人姓名（下单时快照）',
+    shipping_phone VARCHAR(20) NOT NULL DEFAULT '' COMMENT '收货人手机号（下单时快照）',
+    shipping_province VARCHAR(50) NOT NULL DEFAULT '' COMMENT '省份（下单时快照）',
+    shipping_city VARCHAR(50) NOT NULL DEFAULT '' COMMENT '城市（下单时快照）',
+    shipping_district VARCHAR(50) NOT NULL DEFAULT '' COMMENT '区县（下单时快照）',
+    shipping_detail VARCHAR(255) NOT NULL DEFAULT '' COMMENT '详细地址（下单时快照）',
+    shipping_postal_code VARCHAR(20) NOT NULL DEFAULT '' COMMENT '邮政编码（下单时快照）',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     UNIQUE KEY uk_order_no (order_no),
@@ -92,12 +236,211 @@ CREATE TABLE order_items (
     quantity INT NOT NULL,
     price INT NOT NULL COMMENT '单价（元）',
     subtotal INT NOT NULL COMMENT '小计（元）',
+    refunded_quantity INT DEFAULT 0 COMMENT '已退款数量',
+    discount_amount INT DEFAULT 0 COMMENT '分摊到该订单项的优惠金额（元）',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
     FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+-- 创建优惠券表
+CREATE TABLE coupons (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL COMMENT '优惠码（大写）',
+    name VARCHAR(100) NOT NULL COMMENT '优惠券名称',
+    description VARCHAR(255) DEFAULT NULL COMMENT '使用说明',
+    type VARCHAR(20) NOT NULL COMMENT '优惠类型：fixed-满减，percent-折扣',
+    value INT NOT NULL COMMENT '减免金额（元）或减免百分比',
+    max_discount INT DEFAULT 0 COMMENT '折扣券最高减免金额（元），0表示不限',
+    min_spend INT DEFAULT 0 COMMENT '适用商品金额门槛（元），0表示无门槛',
+    scope VARCHAR(20) NOT NULL DEFAULT 'all' COMMENT '适用范围：all-全场，category-指定分类，book-指定图书',
+    scope_ids TEXT COMMENT '适用的分类ID或图书ID（JSON数组）',
+    total_limit INT DEFAULT 0 COMMENT '总使用次数上限，0表示不限',
+    per_user_limit INT DEFAULT 0 COMMENT '每个用户使用次数上限，0表示不限',
+    used_count INT DEFAULT 0 COMMENT '已使用次数（不含已退回）',
+    start_at DATETIME NOT NULL COMMENT '生效时间',
+    end_at DATETIME NOT NULL COMMENT '失效时间',
+    is_active BOOLEAN DEFAULT TRUE COMMENT '是否启用',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_code (code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='优惠券表';
+
+-- 创建优惠券使用记录表
+CREATE TABLE coupon_redemptions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    coupon_id INT NOT NULL,
+    user_id INT NOT NULL,
+    order_id INT NOT NULL,
+    discount_amount INT NOT NULL COMMENT '优惠金额（元）',
+    status TINYINT DEFAULT 0 COMMENT '状态：0-已使用，1-订单取消后已退回',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_coupon_user (coupon_id, user_id),
+    INDEX idx_order_id (order_id),
+    FOREIGN KEY (coupon_id) REFERENCES coupons(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='优惠券使用记录表';
+
+-- 创建订单优惠明细表
+CREATE TABLE order_discounts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id INT NOT NULL,
+    coupon_id INT DEFAULT 0 COMMENT '优惠券ID',
+    coupon_code VARCHAR(50) DEFAULT NULL COMMENT '优惠码',
+    description VARCHAR(255) DEFAULT NULL COMMENT '优惠说明',
+    amount INT NOT NULL COMMENT '优惠金额（元）',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_order_id (order_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='订单优惠明细表';
+
+-- 创建订单状态变更记录表
+CREATE TABLE order_status_logs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id INT NOT NULL,
+    from_status TINYINT NOT NULL COMMENT '变更前状态',
+    to_status TINYINT NOT NULL COMMENT '变更后状态',
+    operator VARCHAR(20) NOT NULL COMMENT '操作人类型：user/admin/system',
+    operator_id INT DEFAULT 0 COMMENT '操作人ID，系统操作为0',
+    reason VARCHAR(255) DEFAULT NULL COMMENT '变更原因',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_order_id (order_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='订单状态变更记录表';
+
+-- 创建发货记录表
+CREATE TABLE shipments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id INT NOT NULL,
+    carrier VARCHAR(50) NOT NULL COMMENT '承运物流公司',
+    tracking_no VARCHAR(100) NOT NULL COMMENT '物流单号',
+    status TINYINT DEFAULT 0 COMMENT '物流状态：0-运输中，1-已签收',
+    remark VARCHAR(255) DEFAULT NULL COMMENT '备注',
+    operator_id INT DEFAULT 0 COMMENT '发货管理员ID',
+    shipped_at DATETIME NOT NULL COMMENT '发货时间',
+    delivered_at DATETIME NULL DEFAULT NULL COMMENT '签收时间',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_order_id (order_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='发货记录表';
+
+-- 创建物流轨迹表
+CREATE TABLE shipment_events (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    shipment_id INT NOT NULL,
+    status TINYINT DEFAULT 0 COMMENT '该节点的物流状态',
+    location VARCHAR(100) DEFAULT NULL COMMENT '所在地点',
+    description VARCHAR(255) NOT NULL COMMENT '轨迹描述',
+    occurred_at DATETIME NOT NULL COMMENT '发生时间',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_shipment_id (shipment_id),
+    FOREIGN KEY (shipment_id) REFERENCES shipments(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='物流轨迹表';
+
+-- 创建支付单表
+CREATE TABLE payments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    payment_no VARCHAR(50) NOT NULL COMMENT '商户支付单号',
+    order_id INT NOT NULL,
+    user_id INT NOT NULL,
+    provider VARCHAR(30) NOT NULL COMMENT '支付渠道',
+    transaction_id VARCHAR(100) DEFAULT NULL COMMENT '支付渠道交易号',
+    amount INT NOT NULL COMMENT '支付金额（元）',
+    refunded_amount INT DEFAULT 0 COMMENT '已退款金额（元）',
+    status TINYINT DEFAULT 0 COMMENT '支付状态：0-待支付，1-支付成功，2-支付失败，3-已全额退款，4-已扣款但订单已取消或已支付待退回',
+    callback_payload TEXT COMMENT '支付回调原始报文',
+    paid_at DATETIME NULL DEFAULT NULL COMMENT '支付成功时间',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_payment_no (payment_no),
+    INDEX idx_order_id (order_id),
+    INDEX idx_transaction_id (transaction_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='支付单表';
+
+-- 创建售后申请表
+CREATE TABLE refunds (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    refund_no VARCHAR(50) NOT NULL COMMENT '售后单号',
+    order_id INT NOT NULL,
+    user_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL COMMENT '售后类型：refund-仅退款，return-退货退款',
+    reason VARCHAR(255) NOT NULL COMMENT '申请原因',
+    amount INT NOT NULL COMMENT '退款金额（元）',
+    status TINYINT DEFAULT 0 COMMENT '审核状态：0-待审核，1-已同意，2-已拒绝，3-已同意待渠道退款',
+    prev_order_status TINYINT NOT NULL COMMENT '申请前的订单状态',
+    admin_id INT DEFAULT 0 COMMENT '审核管理员ID',
+    admin_remark VARCHAR(255) DEFAULT NULL COMMENT '审核备注',
+    payment_no VARCHAR(50) DEFAULT NULL COMMENT '原路退款的支付单号',
+    refund_transaction_id VARCHAR(100) DEFAULT NULL COMMENT '支付渠道退款交易号',
+    processed_at DATETIME NULL DEFAULT NULL COMMENT '审核时间',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_refund_no (refund_no),
+    INDEX idx_order_id (order_id),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='售后申请表';
+
+-- 创建售后申请项表
+CREATE TABLE refund_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    refund_id INT NOT NULL,
+    order_item_id INT NOT NULL,
+    book_id INT NOT NULL,
+    quantity INT NOT NULL COMMENT '退款数量',
+    amount INT NOT NULL COMMENT '退款金额（元）',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_refund_id (refund_id),
+    FOREIGN KEY (refund_id) REFERENCES refunds(id) ON DELETE CASCADE,
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='售后申请项表';
+
+-- 创建发票表
+CREATE TABLE invoices (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    invoice_no VARCHAR(50) NOT NULL COMMENT '发票号',
+    order_id INT NOT NULL,
+    user_id INT NOT NULL,
+    order_no VARCHAR(50) NOT NULL COMMENT '订单号',
+    status TINYINT DEFAULT 0 COMMENT '发票状态：0-已开具，1-已作废',
+    buyer_name VARCHAR(100) DEFAULT NULL COMMENT '买方名称',
+    buyer_account VARCHAR(100) DEFAULT NULL COMMENT '买方账号',
+    buyer_phone VARCHAR(20) DEFAULT NULL COMMENT '买方电话',
+    buyer_email VARCHAR(100) DEFAULT NULL COMMENT '买方邮箱',
+    buyer_address VARCHAR(500) DEFAULT NULL COMMENT '买方地址',
+    items JSON NOT NULL COMMENT '发票明细快照',
+    subtotal_amount INT NOT NULL DEFAULT 0 COMMENT '商品金额（元）',
+    discount_amount INT NOT NULL DEFAULT 0 COMMENT '优惠金额（元）',
+    total_amount INT NOT NULL DEFAULT 0 COMMENT '实付金额（元）',
+    refunded_amount INT NOT NULL DEFAULT 0 COMMENT '开具时已退款金额（元）',
+    paid_at DATETIME NULL DEFAULT NULL COMMENT '支付时间',
+    issued_at DATETIME NOT NULL COMMENT '开具时间',
+    issued_by INT DEFAULT 0 COMMENT '开具的管理员ID，自动开具为0',
+    voided_at DATETIME NULL DEFAULT NULL COMMENT '作废时间',
+    voided_by INT DEFAULT 0 COMMENT '作废的管理员ID',
+    void_reason VARCHAR(255) DEFAULT NULL COMMENT '作废原因',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_invoice_no (invoice_no),
+    INDEX idx_order_status (order_id, status),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='发票表';
+
+-- 创建发票号序列表
+CREATE TABLE invoice_sequences (
+    name VARCHAR(50) PRIMARY KEY COMMENT '序列名称，按年份划分',
+    value BIGINT NOT NULL DEFAULT 0 COMMENT '最后一个已分配的序号'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='发票号序列表';
+
 -- 创建轮播图表
 CREATE TABLE carousel (
     id INT PRIMARY KEY AUTO_INCREMENT,
@@ -6,6 +6,9 @@ INSERT INTO categories (name, description, icon, color, gradient, sort, is_activ
 ('历史', '历史传记和史料', '🏛️', '#A8E6CF', 'linear-gradient(135deg, #A8E6CF, #88D8C0)', 4, 1, 0),
 ('计算机', '计算机技术和编程', '💻', '#FF9A8B', 'linear-gradient(135deg, #FF9A8B, #FF6B6B)', 5, 1, 0);
 
+-- 补全顶级分类的物化路径
+UPDATE categories SET path = CONCAT('/', id, '/'), depth = 0 WHERE parent_id IS NULL;
+
 -- 插入轮播图数据（使用独特的轮播图图片）
 INSERT INTO carousel (title, description, image_url, link_url, sort_order) VALUES
 ('精选好书推荐', '发现更多精彩好书', 'https://images.unsplash.com/photo-1532274402911-5a369e4c4bb5?w=300&h=400&fit=crop', '/category/文学', 1),
@@ -46,7 +49,9 @@ INSERT INTO books (title, author, description, price, discount, type, category_i
 ('设计模式', '埃里希·伽马', '软件开发中的设计模式，提高代码复用性和可维护性。', 65, 0, '计算机', 5, 75, 1, 'https://images.unsplash.com/photo-1519681393784-d120267933ba?w=300&h=400&fit=crop', '9787111075752', '机械工业出版社', '2007-03-01', 254, '中文', '平装', 580, NOW(), NOW()),
 ('深入理解计算机系统', '兰德尔·E·布莱恩特', '计算机系统的经典教材，从程序员视角理解系统。', 95, 10, '计算机', 5, 50, 1, 'https://images.unsplash.com/photo-1506905925346-21bda4d32df4?w=300&h=400&fit=crop', '9787111321330', '机械工业出版社', '2011-01-01', 702, '中文', '平装', 350, NOW(), NOW());
 
--- 更新categories表的book_count
+-- 更新categories表的book_count和off_shelf_book_count
 UPDATE categories SET book_count = (
-    SELECT COUNT(*) FROM books WHERE category_id = categories.id
+    SELECT COUNT(*) FROM books WHERE category_id = categories.id AND status = 1
+), off_shelf_book_count = (
+    SELECT COUNT(*) FROM books WHERE category_id = categories.id AND status <> 1
 ); 
\ No newline at end of file
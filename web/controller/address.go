@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"bookstore/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddressController 收货地址控制器
+// 负责处理用户地址簿相关的HTTP请求，包括地址的增删改查和设置默认地址
+type AddressController struct {
+	AddressService *service.AddressService // 收货地址服务
+}
+
+// NewAddressController 创建新的收货地址控制器实例
+// 返回:
+//
+//	*AddressController - 初始化好的收货地址控制器
+func NewAddressController() *AddressController {
+	return &AddressController{
+		AddressService: service.NewAddressService(),
+	}
+}
+
+// GetAddresses 获取用户的地址簿
+// 路由: GET /user/addresses (需认证)
+func (a *AddressController) GetAddresses(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	addresses, err := a.AddressService.GetUserAddresses(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取收货地址失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    addresses,
+		"message": "获取收货地址成功",
+	})
+}
+
+// GetAddress 获取指定收货地址
+// 路由: GET /user/addresses/:id (需认证)
+func (a *AddressController) GetAddress(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的地址ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	address, err := a.AddressService.GetUserAddress(userID.(int), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    -1,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    address,
+		"message": "获取收货地址成功",
+	})
+}
+
+// CreateAddress 添加收货地址
+// 路由: POST /user/addresses (需认证)
+func (a *AddressController) CreateAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	var req service.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	address, err := a.AddressService.CreateAddress(userID.(int), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "添加收货地址失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    address,
+		"message": "添加收货地址成功",
+	})
+}
+
+// UpdateAddress 更新收货地址
+// 路由: PUT /user/addresses/:id (需认证)
+func (a *AddressController) UpdateAddress(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的地址ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	var req service.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	address, err := a.AddressService.UpdateAddress(userID.(int), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "更新收货地址失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    address,
+		"message": "更新收货地址成功",
+	})
+}
+
+// DeleteAddress 删除收货地址
+// 路由: DELETE /user/addresses/:id (需认证)
+func (a *AddressController) DeleteAddress(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的地址ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	if err := a.AddressService.DeleteAddress(userID.(int), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "删除收货地址失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "删除收货地址成功",
+	})
+}
+
+// SetDefaultAddress 设置默认收货地址
+// 路由: PUT /user/addresses/:id/default (需认证)
+func (a *AddressController) SetDefaultAddress(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的地址ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	if err := a.AddressService.SetDefaultAddress(userID.(int), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "设置默认地址失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "设置默认地址成功",
+	})
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"bookstore/model"
 	"bookstore/service"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -12,7 +13,8 @@ import (
 // AdminBookController 管理员图书控制器
 // 负责图书和分类的增删改查等管理操作
 type AdminBookController struct {
-	bookService *service.BookService // 图书服务
+	bookService     *service.BookService     // 图书服务
+	categoryService *service.CategoryService // 分类服务
 }
 
 // NewAdminBookController 创建新的管理员图书控制器实例
@@ -21,7 +23,8 @@ type AdminBookController struct {
 //	*AdminBookController - 初始化好的管理员图书控制器
 func NewAdminBookController() *AdminBookController {
 	return &AdminBookController{
-		bookService: service.NewBookService(),
+		bookService:     service.NewBookService(),
+		categoryService: service.NewCategoryService(),
 	}
 }
 
@@ -247,6 +250,50 @@ func (c *AdminBookController) UpdateBookStatus(ctx *gin.Context) {
 	})
 }
 
+// SetBookGrouping 设置图书所属的作品和丛书
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理设置图书作品和丛书的请求，请求中的各字段整体覆盖图书当前的设置
+func (c *AdminBookController) SetBookGrouping(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req model.BookGroupingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	if err := c.bookService.SetBookGrouping(id, &req); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrWorkNotFound) || errors.Is(err, service.ErrSeriesNotFound) ||
+			errors.Is(err, service.ErrVolumeWithoutSeries) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "设置作品和丛书失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "设置作品和丛书成功",
+	})
+}
+
 // GetCategories 获取所有分类
 // 参数:
 //
@@ -286,8 +333,12 @@ func (c *AdminBookController) CreateCategory(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.bookService.CreateCategory(&category); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+	if err := c.categoryService.CreateCategory(&category); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrCategoryParentNotFound) || errors.Is(err, service.ErrCategoryTooDeep) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
 			"code":    -1,
 			"message": "创建分类失败: " + err.Error(),
 		})
@@ -357,8 +408,15 @@ func (c *AdminBookController) DeleteCategory(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.bookService.DeleteCategory(uint(id)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+	if err := c.categoryService.DeleteCategory(int(id)); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrCategoryNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrCategoryNotEmpty):
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, gin.H{
 			"code":    -1,
 			"message": "删除分类失败: " + err.Error(),
 		})
@@ -370,3 +428,76 @@ func (c *AdminBookController) DeleteCategory(ctx *gin.Context) {
 		"message": "删除分类成功",
 	})
 }
+
+// MoveCategory 移动分类
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理移动分类请求，将分类连同其全部子分类移动到新的父分类下
+func (c *AdminBookController) MoveCategory(ctx *gin.Context) {
+	idStr := ctx.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req model.CategoryMoveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	category, err := c.categoryService.MoveCategory(int(id), req.ParentID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrCategoryNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrCategoryParentNotFound), errors.Is(err, service.ErrCategoryCycle),
+			errors.Is(err, service.ErrCategoryTooDeep):
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "移动分类失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "移动分类成功",
+		"data":    category,
+	})
+}
+
+// RecountCategoryBooks 重新统计分类图书数量
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理重新统计请求，按图书表修正所有分类的上架和下架图书数量
+func (c *AdminBookController) RecountCategoryBooks(ctx *gin.Context) {
+	result, err := c.categoryService.RecountBookCounts()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "重新统计分类图书数量失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "重新统计分类图书数量成功",
+		"data":    result,
+	})
+}
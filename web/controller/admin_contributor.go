@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"bookstore/model"
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminContributorController 管理员作者和出版社控制器
+// 负责查找和合并重复的作者、出版社，以及将图书的署名文字迁移为作者和出版社关联
+type AdminContributorController struct {
+	authorService      *service.AuthorService      // 作者服务
+	publisherService   *service.PublisherService   // 出版社服务
+	contributorService *service.ContributorService // 图书署名服务
+}
+
+// NewAdminContributorController 创建新的管理员作者和出版社控制器实例
+// 返回:
+//
+//	*AdminContributorController - 初始化好的管理员作者和出版社控制器
+func NewAdminContributorController() *AdminContributorController {
+	return &AdminContributorController{
+		authorService:      service.NewAuthorService(),
+		publisherService:   service.NewPublisherService(),
+		contributorService: service.NewContributorService(),
+	}
+}
+
+// GetAuthors 获取作者列表
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取作者列表请求，支持分页和按姓名搜索，返回每位作者关联的图书数量
+func (c *AdminContributorController) GetAuthors(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	authors, total, err := c.authorService.GetAuthors(ctx.Query("keyword"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取作者列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取作者列表成功",
+		"data": gin.H{
+			"authors":      authors,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
+
+// MergeAuthors 合并重复作者
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理合并作者请求，将被合并作者的图书转移到保留的作者并删除被合并作者
+func (c *AdminContributorController) MergeAuthors(ctx *gin.Context) {
+	var req model.MergeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	author, err := c.authorService.MergeAuthors(&req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrAuthorNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrMergeTargetInSource):
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "合并作者失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "合并作者成功",
+		"data":    author,
+	})
+}
+
+// GetPublishers 获取出版社列表
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取出版社列表请求，支持分页和按名称搜索，返回每个出版社关联的图书数量
+func (c *AdminContributorController) GetPublishers(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	publishers, total, err := c.publisherService.GetPublishers(ctx.Query("keyword"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取出版社列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取出版社列表成功",
+		"data": gin.H{
+			"publishers":   publishers,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
+
+// MergePublishers 合并重复出版社
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理合并出版社请求，将被合并出版社的图书转移到保留的出版社并删除被合并出版社
+func (c *AdminContributorController) MergePublishers(ctx *gin.Context) {
+	var req model.MergeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	publisher, err := c.publisherService.MergePublishers(&req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrPublisherNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrMergeTargetInSource):
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "合并出版社失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "合并出版社成功",
+		"data":    publisher,
+	})
+}
+
+// MigrateContributors 迁移图书署名
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理迁移请求，按图书的作者和出版社文字为尚未关联的图书建立作者和出版社关联
+func (c *AdminContributorController) MigrateContributors(ctx *gin.Context) {
+	result, err := c.contributorService.MigrateBooks()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "迁移图书署名失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "迁移图书署名成功",
+		"data":    result,
+	})
+}
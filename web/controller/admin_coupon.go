@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"bookstore/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminCouponController 管理员优惠券控制器
+// 负责优惠券相关的管理操作，包括优惠券的增删改查、核销统计和使用记录
+type AdminCouponController struct {
+	couponService *service.CouponService // 优惠券服务
+}
+
+// NewAdminCouponController 创建新的管理员优惠券控制器实例
+// 返回:
+//
+//	*AdminCouponController - 初始化好的管理员优惠券控制器
+func NewAdminCouponController() *AdminCouponController {
+	return &AdminCouponController{
+		couponService: service.NewCouponService(),
+	}
+}
+
+// GetCouponList 获取优惠券列表
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取优惠券列表请求，支持分页和按优惠码或名称搜索
+func (c *AdminCouponController) GetCouponList(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	coupons, total, err := c.couponService.GetCouponList(ctx.Query("keyword"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取优惠券列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取优惠券列表成功",
+		"data": gin.H{
+			"coupons":      coupons,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
+
+// GetCouponByID 根据ID获取优惠券
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取优惠券详情请求
+func (c *AdminCouponController) GetCouponByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	coupon, err := c.couponService.GetCouponByID(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"code":    -1,
+			"message": "优惠券不存在",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取优惠券成功",
+		"data":    coupon,
+	})
+}
+
+// CreateCoupon 创建优惠券
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理创建优惠券请求，验证参数并调用服务层创建优惠券
+func (c *AdminCouponController) CreateCoupon(ctx *gin.Context) {
+	var req service.CouponRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	coupon, err := c.couponService.CreateCoupon(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "创建优惠券失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "创建优惠券成功",
+		"data":    coupon,
+	})
+}
+
+// UpdateCoupon 更新优惠券
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理更新优惠券请求，修改后的规则只对之后的订单生效
+func (c *AdminCouponController) UpdateCoupon(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req service.CouponRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	coupon, err := c.couponService.UpdateCoupon(id, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "更新优惠券失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "更新优惠券成功",
+		"data":    coupon,
+	})
+}
+
+// DeleteCoupon 删除优惠券
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理删除优惠券请求，已被使用过的优惠券只能停用不能删除
+func (c *AdminCouponController) DeleteCoupon(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	if err := c.couponService.DeleteCoupon(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "删除优惠券失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "删除优惠券成功",
+	})
+}
+
+// GetCouponStats 获取优惠券核销统计
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取核销统计请求，返回使用次数、使用人数、累计优惠金额和带动的订单金额
+func (c *AdminCouponController) GetCouponStats(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	stats, err := c.couponService.GetCouponStats(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"code":    -1,
+			"message": "获取核销统计失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取核销统计成功",
+		"data":    stats,
+	})
+}
+
+// GetRedemptions 获取优惠券使用记录
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取使用记录请求，支持分页，返回使用的用户和订单
+func (c *AdminCouponController) GetRedemptions(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	redemptions, total, err := c.couponService.GetRedemptions(id, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取使用记录失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取使用记录成功",
+		"data": gin.H{
+			"redemptions":  redemptions,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
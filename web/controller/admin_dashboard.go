@@ -60,11 +60,11 @@ func (c *AdminDashboardController) GetDashboardStats(ctx *gin.Context) {
 	// 获取用户总数
 	global.DBClient.Model(&model.User{}).Count(&stats.TotalUsers)
 
-	// 获取总收入（已支付的订单）
+	// 获取总收入（已支付的订单，扣除已退款金额）
 	var totalRevenue int64
 	global.DBClient.Model(&model.Order{}).
 		Where("is_paid = ?", true).
-		Select("COALESCE(SUM(total_amount), 0)").
+		Select("COALESCE(SUM(total_amount - refunded_amount), 0)").
 		Scan(&totalRevenue)
 	stats.TotalRevenue = float64(totalRevenue) // 已经是元为单位
 
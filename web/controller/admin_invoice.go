@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminInvoiceController 管理员发票控制器
+// 负责发票相关的管理操作，包括查看订单发票、查看或下载发票、重新开具和作废发票
+type AdminInvoiceController struct {
+	invoiceService *service.InvoiceService // 发票服务
+}
+
+// NewAdminInvoiceController 创建新的管理员发票控制器实例
+// 返回:
+//
+//	*AdminInvoiceController - 初始化好的管理员发票控制器
+func NewAdminInvoiceController() *AdminInvoiceController {
+	return &AdminInvoiceController{
+		invoiceService: service.NewInvoiceService(),
+	}
+}
+
+// GetOrderInvoices 获取订单的全部发票
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取订单发票列表请求，包括已作废的发票，按开具时间倒序排列
+func (c *AdminInvoiceController) GetOrderInvoices(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	invoices, err := c.invoiceService.GetOrderInvoices(orderID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取发票列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取发票列表成功",
+		"data":    invoices,
+	})
+}
+
+// RegenerateInvoice 重新开具订单发票
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理重新开具发票请求，作废订单当前有效的发票后按订单当前信息开具新发票；
+// 订单从未开具过发票时直接开具
+func (c *AdminInvoiceController) RegenerateInvoice(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	adminID := ctx.GetInt("admin_user_id")
+	invoice, err := c.invoiceService.RegenerateInvoice(orderID, adminID)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrOrderNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "开具发票失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "开具发票成功",
+		"data":    invoice,
+	})
+}
+
+// GetInvoice 查看或下载发票
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理查看发票请求，已作废的发票同样可以查看并带有作废标记；
+// 通过format查询参数选择html（默认）或pdf格式
+func (c *AdminInvoiceController) GetInvoice(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	invoice, err := c.invoiceService.GetInvoiceByID(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"code":    -1,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	writeInvoice(ctx, invoice)
+}
+
+// VoidInvoice 作废发票
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理作废发票请求，必须填写作废原因
+func (c *AdminInvoiceController) VoidInvoice(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"` // 作废原因
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := ctx.GetInt("admin_user_id")
+	if err := c.invoiceService.VoidInvoice(id, adminID, req.Reason); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "作废发票失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "作废发票成功",
+	})
+}
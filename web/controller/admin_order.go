@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"bookstore/model"
 	"bookstore/service"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -9,9 +11,10 @@ import (
 )
 
 // AdminOrderController 管理员订单控制器
-// 负责订单相关的管理操作，包括获取订单列表、订单详情和更新订单状态
+// 负责订单相关的管理操作，包括获取订单列表、订单详情、导出订单、更新订单状态以及发货和物流维护
 type AdminOrderController struct {
-	orderService *service.OrderService // 订单服务
+	orderService    *service.OrderService    // 订单服务
+	shipmentService *service.ShipmentService // 发货服务
 }
 
 // NewAdminOrderController 创建新的管理员订单控制器实例
@@ -20,7 +23,8 @@ type AdminOrderController struct {
 //	*AdminOrderController - 初始化好的管理员订单控制器
 func NewAdminOrderController() *AdminOrderController {
 	return &AdminOrderController{
-		orderService: service.NewOrderService(),
+		orderService:    service.NewOrderService(),
+		shipmentService: service.NewShipmentService(),
 	}
 }
 
@@ -29,21 +33,82 @@ func NewAdminOrderController() *AdminOrderController {
 //
 //	ctx - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理获取订单列表请求，返回所有订单信息（当前为示例实现）
+// 处理获取订单列表请求，支持分页以及按状态、用户、订单号、下单日期和金额范围筛选
 func (c *AdminOrderController) GetOrderList(ctx *gin.Context) {
+	var req model.OrderListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	result, err := c.orderService.GetOrderList(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "获取订单列表失败: " + err.Error(),
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "获取订单列表成功",
-		"data":    []any{},
+		"data":    result,
 	})
 }
 
+// ExportOrders 导出订单
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理导出订单请求，按下单日期范围和订单状态筛选，以CSV或XLSX格式流式输出订单及订单项；
+// 参数校验通过后才开始输出，输出过程中出错时只能中断连接，客户端会收到不完整的文件
+func (c *AdminOrderController) ExportOrders(ctx *gin.Context) {
+	var req model.OrderExportRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	export, err := c.orderService.PrepareOrderExport(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "导出订单失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Content-Type", export.ContentType)
+	ctx.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	ctx.Status(http.StatusOK)
+	if err := export.Stream(ctx.Writer); err != nil {
+		log.Printf("导出订单失败，文件: %s，错误: %v", export.Filename, err)
+		ctx.Abort()
+	}
+}
+
 // GetOrderByID 根据ID获取订单
 // 参数:
 //
 //	ctx - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理根据ID获取订单请求，验证参数并返回指定订单的详细信息
+// 处理根据ID获取订单请求，返回订单及其订单项、图书、用户和状态变更记录
 func (c *AdminOrderController) GetOrderByID(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -55,10 +120,19 @@ func (c *AdminOrderController) GetOrderByID(ctx *gin.Context) {
 		return
 	}
 
+	order, err := c.orderService.GetOrderByIDForAdmin(int(id))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"code":    -1,
+			"message": "订单不存在",
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "获取订单成功",
-		"data":    gin.H{"id": id},
+		"data":    order,
 	})
 }
 
@@ -67,7 +141,7 @@ func (c *AdminOrderController) GetOrderByID(ctx *gin.Context) {
 //
 //	ctx - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理更新订单状态请求，验证参数并调用服务层更新订单状态
+// 处理更新订单状态请求，状态变更经过订单状态机校验，非法流转会被拒绝
 func (c *AdminOrderController) UpdateOrderStatus(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -80,7 +154,8 @@ func (c *AdminOrderController) UpdateOrderStatus(ctx *gin.Context) {
 	}
 
 	var req struct {
-		Status int `json:"status" binding:"required"` // 订单状态
+		Status int    `json:"status" binding:"required"` // 目标订单状态
+		Reason string `json:"reason"`                    // 变更原因
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -90,9 +165,135 @@ func (c *AdminOrderController) UpdateOrderStatus(ctx *gin.Context) {
 		return
 	}
 
+	adminID := ctx.GetInt("admin_user_id")
+	if err := c.orderService.ChangeOrderStatus(int(id), req.Status, model.OrderOperatorAdmin, adminID, req.Reason); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "更新订单状态失败: " + err.Error(),
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{
 		"code":    0,
 		"message": "更新订单状态成功",
 		"data":    gin.H{"id": id, "status": req.Status},
 	})
 }
+
+// GetOrderShipments 获取订单的发货记录
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取订单发货记录请求，返回每个包裹及其物流轨迹
+func (c *AdminOrderController) GetOrderShipments(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	shipments, err := c.shipmentService.GetOrderShipments(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取发货记录失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取发货记录成功",
+		"data":    shipments,
+	})
+}
+
+// CreateShipment 订单发货
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理订单发货请求，录入承运公司和物流单号；已支付订单首次发货后变为已发货，
+// 已发货订单可以继续追加包裹
+func (c *AdminOrderController) CreateShipment(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req service.CreateShipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := ctx.GetInt("admin_user_id")
+	shipment, err := c.shipmentService.CreateShipment(id, adminID, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "发货失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "发货成功",
+		"data":    shipment,
+	})
+}
+
+// UpdateShipment 更新发货记录
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理更新发货记录请求，可修正物流信息、追加物流轨迹或标记包裹已签收；
+// 订单的所有包裹均已签收时订单自动完成
+func (c *AdminOrderController) UpdateShipment(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req service.UpdateShipmentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	shipment, err := c.shipmentService.UpdateShipment(id, &req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "更新发货记录失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "更新发货记录成功",
+		"data":    shipment,
+	})
+}
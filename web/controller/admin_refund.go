@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"bookstore/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminRefundController 管理员售后控制器
+// 负责售后申请相关的管理操作，包括获取申请列表、申请详情以及同意或拒绝申请
+type AdminRefundController struct {
+	refundService *service.RefundService // 售后服务
+}
+
+// NewAdminRefundController 创建新的管理员售后控制器实例
+// 返回:
+//
+//	*AdminRefundController - 初始化好的管理员售后控制器
+func NewAdminRefundController() *AdminRefundController {
+	return &AdminRefundController{
+		refundService: service.NewRefundService(),
+	}
+}
+
+// GetRefundList 获取售后申请列表
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取售后申请列表请求，支持分页和按审核状态筛选
+func (c *AdminRefundController) GetRefundList(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	var status *int
+	if statusStr := ctx.Query("status"); statusStr != "" {
+		s, err := strconv.Atoi(statusStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"code":    -1,
+				"message": "状态参数错误",
+			})
+			return
+		}
+		status = &s
+	}
+
+	refunds, total, err := c.refundService.GetRefundList(status, page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取售后列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取售后列表成功",
+		"data": gin.H{
+			"refunds":      refunds,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
+
+// GetRefundByID 根据ID获取售后申请
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取售后申请详情请求，返回申请项、图书和订单信息
+func (c *AdminRefundController) GetRefundByID(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	refund, err := c.refundService.GetRefundByID(id)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"code":    -1,
+			"message": "售后申请不存在",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取售后申请成功",
+		"data":    refund,
+	})
+}
+
+// ApproveRefund 同意售后申请
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理同意售后申请请求，恢复库存、通过原支付渠道退款并更新订单状态；
+// 对渠道退款失败的申请再次调用会重试退款
+func (c *AdminRefundController) ApproveRefund(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	// 审核备注为可选参数，请求体为空时忽略
+	var req struct {
+		Remark string `json:"remark"` // 审核备注
+	}
+	_ = ctx.ShouldBindJSON(&req)
+
+	adminID := ctx.GetInt("admin_user_id")
+	if err := c.refundService.ApproveRefund(id, adminID, req.Remark); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "同意售后申请失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "同意售后申请成功",
+	})
+}
+
+// RejectRefund 拒绝售后申请
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理拒绝售后申请请求，必须填写拒绝原因，订单恢复为申请前的状态
+func (c *AdminRefundController) RejectRefund(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req struct {
+		Remark string `json:"remark" binding:"required"` // 拒绝原因
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := ctx.GetInt("admin_user_id")
+	if err := c.refundService.RejectRefund(id, adminID, req.Remark); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "拒绝售后申请失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "拒绝售后申请成功",
+	})
+}
@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"bookstore/model"
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminSeriesController 管理员作品和丛书控制器
+// 负责维护作品和丛书，同一作品的不同版本通过作品归为一组，丛书中的图书按卷号排列
+type AdminSeriesController struct {
+	workService   *service.WorkService   // 作品服务
+	seriesService *service.SeriesService // 丛书服务
+}
+
+// NewAdminSeriesController 创建新的管理员作品和丛书控制器实例
+// 返回:
+//
+//	*AdminSeriesController - 初始化好的管理员作品和丛书控制器
+func NewAdminSeriesController() *AdminSeriesController {
+	return &AdminSeriesController{
+		workService:   service.NewWorkService(),
+		seriesService: service.NewSeriesService(),
+	}
+}
+
+// GetWorks 获取作品列表
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取作品列表请求，支持分页和按名称搜索，返回每部作品的版本数量
+func (c *AdminSeriesController) GetWorks(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	works, total, err := c.workService.GetWorks(ctx.Query("keyword"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取作品列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取作品列表成功",
+		"data": gin.H{
+			"works":        works,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
+
+// CreateWork 创建作品
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理创建作品请求
+func (c *AdminSeriesController) CreateWork(ctx *gin.Context) {
+	var req model.WorkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	work, err := c.workService.CreateWork(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "创建作品失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "创建作品成功",
+		"data":    work,
+	})
+}
+
+// UpdateWork 更新作品
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理更新作品请求
+func (c *AdminSeriesController) UpdateWork(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req model.WorkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	work, err := c.workService.UpdateWork(id, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrWorkNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "更新作品失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "更新作品成功",
+		"data":    work,
+	})
+}
+
+// DeleteWork 删除作品
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理删除作品请求，作品下的图书保留，不再归为同一作品
+func (c *AdminSeriesController) DeleteWork(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	if err := c.workService.DeleteWork(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrWorkNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "删除作品失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "删除作品成功",
+	})
+}
+
+// GetSeriesList 获取丛书列表
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取丛书列表请求，支持分页和按名称搜索，返回每套丛书包含的图书数量
+func (c *AdminSeriesController) GetSeriesList(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	series, total, err := c.seriesService.GetSeriesList(ctx.Query("keyword"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取丛书列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取丛书列表成功",
+		"data": gin.H{
+			"series":       series,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
+
+// CreateSeries 创建丛书
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理创建丛书请求
+func (c *AdminSeriesController) CreateSeries(ctx *gin.Context) {
+	var req model.SeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	series, err := c.seriesService.CreateSeries(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "创建丛书失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "创建丛书成功",
+		"data":    series,
+	})
+}
+
+// UpdateSeries 更新丛书
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理更新丛书请求
+func (c *AdminSeriesController) UpdateSeries(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req model.SeriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	series, err := c.seriesService.UpdateSeries(id, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrSeriesNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "更新丛书失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "更新丛书成功",
+		"data":    series,
+	})
+}
+
+// DeleteSeries 删除丛书
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理删除丛书请求，丛书中的图书保留，移出丛书并清空卷号
+func (c *AdminSeriesController) DeleteSeries(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	if err := c.seriesService.DeleteSeries(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSeriesNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "删除丛书失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "删除丛书成功",
+	})
+}
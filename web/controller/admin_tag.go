@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"bookstore/model"
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTagController 管理员标签控制器
+// 负责标签的增删改查以及批量为图书添加、移除标签
+type AdminTagController struct {
+	tagService *service.TagService // 标签服务
+}
+
+// NewAdminTagController 创建新的管理员标签控制器实例
+// 返回:
+//
+//	*AdminTagController - 初始化好的管理员标签控制器
+func NewAdminTagController() *AdminTagController {
+	return &AdminTagController{
+		tagService: service.NewTagService(),
+	}
+}
+
+// GetTags 获取标签列表
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取标签列表请求，支持分页和按名称或别名搜索，返回每个标签的图书数量
+func (c *AdminTagController) GetTags(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	tags, total, err := c.tagService.GetTags(ctx.Query("keyword"), page, pageSize)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取标签列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "获取标签列表成功",
+		"data": gin.H{
+			"tags":         tags,
+			"total":        total,
+			"total_page":   (total + int64(pageSize) - 1) / int64(pageSize),
+			"current_page": page,
+		},
+	})
+}
+
+// CreateTag 创建标签
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理创建标签请求，名称和别名不能与已有标签重复
+func (c *AdminTagController) CreateTag(ctx *gin.Context) {
+	var req model.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	tag, err := c.tagService.CreateTag(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "创建标签失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "创建标签成功",
+		"data":    tag,
+	})
+}
+
+// UpdateTag 更新标签
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理更新标签请求
+func (c *AdminTagController) UpdateTag(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	var req model.TagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	tag, err := c.tagService.UpdateTag(id, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrTagNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "更新标签失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "更新标签成功",
+		"data":    tag,
+	})
+}
+
+// DeleteTag 删除标签
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理删除标签请求，同时移除所有图书上的该标签
+func (c *AdminTagController) DeleteTag(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "ID参数错误",
+		})
+		return
+	}
+
+	if err := c.tagService.DeleteTag(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrTagNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "删除标签失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "删除标签成功",
+	})
+}
+
+// AddBookTags 批量为图书添加标签
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理批量打标签请求，为图书列表中选中的图书添加选中的标签，已有的标签保持不变
+func (c *AdminTagController) AddBookTags(ctx *gin.Context) {
+	var req model.BookTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	added, err := c.tagService.AddBookTags(&req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrTagNotFound) || errors.Is(err, service.ErrTagBookNotFound) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
+			"code":    -1,
+			"message": "添加标签失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "添加标签成功",
+		"data":    gin.H{"added": added},
+	})
+}
+
+// RemoveBookTags 批量移除图书的标签
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理批量移除标签请求，移除图书列表中选中的图书上的选中标签
+func (c *AdminTagController) RemoveBookTags(ctx *gin.Context) {
+	var req model.BookTagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	removed, err := c.tagService.RemoveBookTags(&req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "移除标签失败: " + err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "移除标签成功",
+		"data":    gin.H{"removed": removed},
+	})
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"bookstore/model"
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorController 作者控制器
+// 负责处理作者详情页的HTTP请求
+type AuthorController struct {
+	AuthorService *service.AuthorService // 作者服务
+}
+
+// NewAuthorController 创建新的作者控制器实例
+// 返回:
+//
+//	*AuthorController - 初始化好的作者控制器
+func NewAuthorController() *AuthorController {
+	return &AuthorController{
+		AuthorService: service.NewAuthorService(),
+	}
+}
+
+// GetAuthorDetail 获取作者详情
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取作者详情请求，返回作者信息及其分页的上架图书，支持与图书列表相同的筛选和排序参数
+func (a *AuthorController) GetAuthorDetail(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的作者ID",
+		})
+		return
+	}
+
+	var req model.BookQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Sort == model.BookSortRelevance {
+		req.Sort = "" // 没有关键词，按相关度排序无意义
+	}
+
+	detail, err := a.AuthorService.GetAuthorDetail(id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrAuthorNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    -1,
+				"message": "作者不存在",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取作者详情失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    detail,
+		"message": "获取作者详情成功",
+	})
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"bookstore/model"
 	"bookstore/service"
 	"net/http"
 	"strconv"
@@ -29,12 +30,23 @@ func NewBookController() *BookController {
 //
 //	c - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理获取书籍列表请求，支持分页参数，返回分页后的书籍列表
+// 处理获取书籍列表请求，支持分页、按分类/类型/语言/装帧/出版社/价格区间/库存筛选和排序，
+// 返回分页后的书籍列表及各筛选项的数量
 func (b *BookController) GetBookList(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "12"))
+	var req model.BookQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Sort == model.BookSortRelevance {
+		req.Sort = "" // 列表没有关键词，按相关度排序无意义
+	}
 
-	books, total, err := b.BookService.GetBooksByPage(page, pageSize)
+	books, total, facets, err := b.BookService.GetBooksByFilter(&req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    -1,
@@ -49,9 +61,10 @@ func (b *BookController) GetBookList(c *gin.Context) {
 		"data": gin.H{
 			"books":      books,
 			"total":      total,
-			"page":       page,
-			"page_size":  pageSize,
-			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+			"page":       req.Page,
+			"page_size":  req.PageSize,
+			"total_page": (total + int64(req.PageSize) - 1) / int64(req.PageSize),
+			"facets":     facets,
 		},
 		"message": "获取书籍列表成功",
 	})
@@ -73,7 +86,7 @@ func (b *BookController) GetBookDetail(c *gin.Context) {
 		return
 	}
 
-	book, err := b.BookService.GetBookByID(id)
+	book, err := b.BookService.GetBookDetail(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"code":    -1,
@@ -146,14 +159,24 @@ func (b *BookController) GetNewBooks(c *gin.Context) {
 //
 //	c - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理搜索书籍请求，支持关键词搜索和分页参数，返回匹配的书籍列表
+// 处理搜索书籍请求，支持关键词搜索、分页以及与书籍列表相同的筛选和排序参数，
+// 默认按相关度排序，返回带有高亮片段的书籍列表及各筛选项的数量，
+// 命中结果过多被截断时truncated为true，提示用户缩小搜索范围
 func (b *BookController) SearchBooks(c *gin.Context) {
-	keyword := c.Query("q")
-	if keyword == "" {
-		keyword = c.Query("keyword") // 兼容旧版本
+	var req model.BookQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Keyword == "" {
+		req.Keyword = c.Query("keyword") // 兼容旧版本
 	}
 
-	if keyword == "" {
+	if req.Keyword == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code":    -1,
 			"message": "搜索关键词不能为空",
@@ -161,10 +184,7 @@ func (b *BookController) SearchBooks(c *gin.Context) {
 		return
 	}
 
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "12"))
-
-	books, total, err := b.BookService.SearchBooksWithPagination(keyword, page, pageSize)
+	books, total, facets, truncated, err := b.BookService.SearchBooksWithPagination(&req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    -1,
@@ -179,20 +199,42 @@ func (b *BookController) SearchBooks(c *gin.Context) {
 		"data": gin.H{
 			"books":      books,
 			"total":      total,
-			"page":       page,
-			"page_size":  pageSize,
-			"total_page": (total + int64(pageSize) - 1) / int64(pageSize),
+			"page":       req.Page,
+			"page_size":  req.PageSize,
+			"total_page": (total + int64(req.PageSize) - 1) / int64(req.PageSize),
+			"facets":     facets,
+			"truncated":  truncated,
 		},
 		"message": "搜索书籍成功",
 	})
 }
 
+// SuggestBooks 获取搜索建议
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理搜索框输入时的建议请求，根据已输入的内容返回匹配的图书标题、作者和分类，按销量和搜索热度排序
+func (b *BookController) SuggestBooks(c *gin.Context) {
+	prefix := c.Query("q")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit <= 0 || limit > service.SuggestMaxLimit {
+		limit = 10
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    b.BookService.SuggestBooks(prefix, limit),
+		"message": "获取搜索建议成功",
+	})
+}
+
 // GetBooksByCategory 根据分类获取书籍
 // 参数:
 //
 //	c - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理根据分类获取书籍请求，返回指定分类下的所有书籍
+// 处理根据分类获取书籍请求，分类可以是分类ID或名称，返回该分类及其子孙分类下的所有上架书籍
 func (b *BookController) GetBooksByCategory(c *gin.Context) {
 	category := c.Param("category")
 	if category == "" {
@@ -203,7 +245,7 @@ func (b *BookController) GetBooksByCategory(c *gin.Context) {
 		return
 	}
 
-	books, err := b.BookService.GetBooksByType(category)
+	books, err := b.BookService.GetBooksByCategory(category)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    -1,
@@ -0,0 +1,490 @@
+package controller
+
+import (
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartController 购物车控制器
+// 负责处理购物车相关的HTTP请求，包括添加、修改数量、移除、清空、查看购物车和结算下单
+type CartController struct {
+	CartService *service.CartService // 购物车服务
+}
+
+// NewCartController 创建新的购物车控制器实例
+// 返回:
+//
+//	*CartController - 初始化好的购物车控制器
+func NewCartController() *CartController {
+	return &CartController{
+		CartService: service.NewCartService(),
+	}
+}
+
+// CartItemRequest 添加或修改购物车商品请求
+type CartItemRequest struct {
+	BookID   int `json:"book_id" binding:"required"` // 图书ID（必填）
+	Quantity int `json:"quantity" binding:"min=0"`   // 数量，添加时默认为1，修改为0时移除
+}
+
+// GetCart 获取购物车
+// 路由: GET /cart (需认证)
+func (cc *CartController) GetCart(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	cart, err := cc.CartService.GetCart(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取购物车失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "获取购物车成功",
+	})
+}
+
+// AddItem 添加图书到购物车
+// 路由: POST /cart/items (需认证)
+func (cc *CartController) AddItem(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	var req CartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	cart, err := cc.CartService.AddItem(userID.(int), req.BookID, req.Quantity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "加入购物车失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "加入购物车成功",
+	})
+}
+
+// UpdateItem 修改购物车中图书的数量
+// 路由: PUT /cart/items/:book_id (需认证)
+func (cc *CartController) UpdateItem(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的图书ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	var req struct {
+		Quantity *int `json:"quantity" binding:"required"` // 新的数量，0表示移除
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	cart, err := cc.CartService.UpdateQuantity(userID.(int), bookID, *req.Quantity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "修改数量失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "修改数量成功",
+	})
+}
+
+// RemoveItem 从购物车移除图书
+// 路由: DELETE /cart/items/:book_id (需认证)
+func (cc *CartController) RemoveItem(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的图书ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	cart, err := cc.CartService.RemoveItem(userID.(int), bookID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "移除商品失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "移除商品成功",
+	})
+}
+
+// ClearCart 清空购物车
+// 路由: DELETE /cart (需认证)
+func (cc *CartController) ClearCart(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	if err := cc.CartService.ClearCart(userID.(int)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "清空购物车失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "清空购物车成功",
+	})
+}
+
+// Checkout 结算购物车
+// 路由: POST /cart/checkout (需认证)
+// 将购物车中可购买的商品（或指定的商品）创建为订单，下单成功后从购物车移除；
+// 价格变动时返回409及变动明细，与直接下单一致
+func (cc *CartController) Checkout(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	// 结算参数均为可选，请求体为空时结算全部商品
+	var req service.CheckoutCartRequest
+	_ = c.ShouldBindJSON(&req)
+
+	order, err := cc.CartService.Checkout(userID.(int), &req)
+	if err != nil {
+		var priceErr *service.PriceChangedError
+		if errors.As(err, &priceErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"code":    -1,
+				"message": priceErr.Error(),
+				"data":    gin.H{"changed_items": priceErr.Items},
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "结算失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    order,
+		"message": "下单成功",
+	})
+}
+
+// cartTokenHeader 访客购物车令牌所在的请求头
+const cartTokenHeader = "X-Cart-Token"
+
+// guestCartToken 从请求头读取访客购物车令牌
+// 参数:
+//
+//	c - gin上下文
+//	create - 未携带令牌时是否生成新令牌
+//
+// 返回:
+//
+//	string - 访客购物车令牌，未携带且不生成时为空
+//	bool - 令牌可用时返回true，否则已写入错误响应
+func guestCartToken(c *gin.Context, create bool) (string, bool) {
+	token := c.GetHeader(cartTokenHeader)
+	if token == "" && create {
+		newToken, err := service.NewGuestCartToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    -1,
+				"message": "生成购物车令牌失败",
+				"error":   err.Error(),
+			})
+			return "", false
+		}
+		return newToken, true
+	}
+	if token != "" && !service.ValidGuestCartToken(token) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的购物车令牌",
+		})
+		return "", false
+	}
+	return token, true
+}
+
+// GetGuestCart 获取访客购物车
+// 路由: GET /cart/guest，通过X-Cart-Token请求头识别购物车
+func (cc *CartController) GetGuestCart(c *gin.Context) {
+	token, ok := guestCartToken(c, false)
+	if !ok {
+		return
+	}
+	if token == "" {
+		// 尚未加入过商品的访客返回空购物车
+		c.JSON(http.StatusOK, gin.H{
+			"code":    0,
+			"data":    &service.CartResponse{Items: []service.CartItem{}},
+			"message": "获取购物车成功",
+		})
+		return
+	}
+
+	cart, err := cc.CartService.GetGuestCart(token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取购物车失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "获取购物车成功",
+	})
+}
+
+// AddGuestItem 添加图书到访客购物车
+// 路由: POST /cart/guest/items
+// 未携带X-Cart-Token时创建新的访客购物车，令牌通过响应中的cart_token返回
+func (cc *CartController) AddGuestItem(c *gin.Context) {
+	var req CartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	token, ok := guestCartToken(c, true)
+	if !ok {
+		return
+	}
+
+	cart, err := cc.CartService.AddGuestItem(token, req.BookID, req.Quantity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "加入购物车失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "加入购物车成功",
+	})
+}
+
+// UpdateGuestItem 修改访客购物车中图书的数量
+// 路由: PUT /cart/guest/items/:book_id
+func (cc *CartController) UpdateGuestItem(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的图书ID",
+		})
+		return
+	}
+
+	var req struct {
+		Quantity *int `json:"quantity" binding:"required"` // 新的数量，0表示移除
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	token, ok := guestCartToken(c, false)
+	if !ok {
+		return
+	}
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "缺少购物车令牌",
+		})
+		return
+	}
+
+	cart, err := cc.CartService.UpdateGuestQuantity(token, bookID, *req.Quantity)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "修改数量失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "修改数量成功",
+	})
+}
+
+// RemoveGuestItem 从访客购物车移除图书
+// 路由: DELETE /cart/guest/items/:book_id
+func (cc *CartController) RemoveGuestItem(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的图书ID",
+		})
+		return
+	}
+
+	token, ok := guestCartToken(c, false)
+	if !ok {
+		return
+	}
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "缺少购物车令牌",
+		})
+		return
+	}
+
+	cart, err := cc.CartService.RemoveGuestItem(token, bookID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "移除商品失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    cart,
+		"message": "移除商品成功",
+	})
+}
+
+// ClearGuestCart 清空访客购物车
+// 路由: DELETE /cart/guest
+func (cc *CartController) ClearGuestCart(c *gin.Context) {
+	token, ok := guestCartToken(c, false)
+	if !ok {
+		return
+	}
+	if token != "" {
+		if err := cc.CartService.ClearGuestCart(token); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    -1,
+				"message": "清空购物车失败",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "清空购物车成功",
+	})
+}
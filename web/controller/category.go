@@ -3,6 +3,7 @@ package controller
 import (
 	"bookstore/model"
 	"bookstore/service"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -49,6 +50,30 @@ func (c *CategoryController) GetCategories(ctx *gin.Context) {
 	})
 }
 
+// GetCategoryTree 获取分类树
+// 参数:
+//
+//	ctx - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取分类树请求，返回嵌套的分类列表，子分类保存在children中
+func (c *CategoryController) GetCategoryTree(ctx *gin.Context) {
+	tree, err := c.CategoryService.GetCategoryTree()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取分类树失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    tree,
+		"message": "获取分类树成功",
+	})
+}
+
 // GetCategoryByID 根据ID获取分类
 // 参数:
 //
@@ -100,7 +125,11 @@ func (c *CategoryController) CreateCategory(ctx *gin.Context) {
 
 	err := c.CategoryService.CreateCategory(&category)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrCategoryParentNotFound) || errors.Is(err, service.ErrCategoryTooDeep) {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{
 			"code":    -1,
 			"message": "创建分类失败",
 			"error":   err.Error(),
@@ -144,7 +173,11 @@ func (c *CategoryController) UpdateCategory(ctx *gin.Context) {
 	category.ID = id
 	err = c.CategoryService.UpdateCategory(&category)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrCategoryNotFound) {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{
 			"code":    -1,
 			"message": "更新分类失败",
 			"error":   err.Error(),
@@ -177,7 +210,14 @@ func (c *CategoryController) DeleteCategory(ctx *gin.Context) {
 
 	err = c.CategoryService.DeleteCategory(id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrCategoryNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrCategoryNotEmpty):
+			status = http.StatusConflict
+		}
+		ctx.JSON(status, gin.H{
 			"code":    -1,
 			"message": "删除分类失败",
 			"error":   err.Error(),
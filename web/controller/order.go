@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"bookstore/model"
 	"bookstore/service"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -9,9 +11,13 @@ import (
 )
 
 // OrderController 订单控制器
-// 负责处理与订单相关的HTTP请求，包括创建订单、获取订单详情、获取用户订单列表、支付订单和获取订单统计信息
+// 负责处理与订单相关的HTTP请求，包括创建订单、获取订单详情、获取用户订单列表、支付订单、取消订单、确认收货、申请售后、获取发票和获取订单统计信息
 type OrderController struct {
-	OrderService *service.OrderService // 订单服务
+	OrderService   *service.OrderService   // 订单服务
+	PaymentService *service.PaymentService // 支付服务
+	RefundService  *service.RefundService  // 售后服务
+	InvoiceService *service.InvoiceService // 发票服务
+	CartService    *service.CartService    // 购物车服务
 }
 
 // NewOrderController 创建新的订单控制器实例
@@ -20,7 +26,11 @@ type OrderController struct {
 //	*OrderController - 初始化好的订单控制器
 func NewOrderController() *OrderController {
 	return &OrderController{
-		OrderService: service.NewOrderService(),
+		OrderService:   service.NewOrderService(),
+		PaymentService: service.NewPaymentService(),
+		RefundService:  service.NewRefundService(),
+		InvoiceService: service.NewInvoiceService(),
+		CartService:    service.NewCartService(),
 	}
 }
 
@@ -30,6 +40,7 @@ func NewOrderController() *OrderController {
 //	c - Gin上下文对象，包含HTTP请求和响应信息
 //
 // 处理创建订单请求，验证参数并从上下文中获取用户ID，然后调用服务层创建订单
+// 若图书价格已变动，返回409及变动明细，前端需重新确认后再下单
 func (o *OrderController) CreateOrder(c *gin.Context) {
 	var req service.CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -54,6 +65,16 @@ func (o *OrderController) CreateOrder(c *gin.Context) {
 
 	order, err := o.OrderService.CreateOrder(&req)
 	if err != nil {
+		// 价格变动时返回最新价格，由前端提示用户重新确认
+		var priceErr *service.PriceChangedError
+		if errors.As(err, &priceErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"code":    -1,
+				"message": priceErr.Error(),
+				"data":    gin.H{"changed_items": priceErr.Items},
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    -1,
 			"message": "创建订单失败",
@@ -74,7 +95,7 @@ func (o *OrderController) CreateOrder(c *gin.Context) {
 //
 //	c - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理根据ID获取订单请求，验证参数并调用服务层获取订单详情
+// 处理根据ID获取订单请求，验证参数并调用服务层获取订单详情，包含状态变更记录和物流时间线
 func (o *OrderController) GetOrderByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -85,8 +106,18 @@ func (o *OrderController) GetOrderByID(c *gin.Context) {
 		return
 	}
 
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	// 订单包含收货地址等个人信息，只允许订单所有者查看
 	order, err := o.OrderService.GetOrderByID(id)
-	if err != nil {
+	if err != nil || order.UserID != userID.(int) {
 		c.JSON(http.StatusNotFound, gin.H{
 			"code":    -1,
 			"message": "订单不存在",
@@ -148,7 +179,8 @@ func (o *OrderController) GetUserOrders(c *gin.Context) {
 //
 //	c - Gin上下文对象，包含HTTP请求和响应信息
 //
-// 处理支付订单请求，验证参数并调用服务层完成订单支付
+// 处理支付订单请求，通过支付渠道为订单发起支付并返回支付地址；
+// 订单在支付渠道回调验签通过后才会标记为已支付
 func (o *OrderController) PayOrder(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -159,19 +191,332 @@ func (o *OrderController) PayOrder(c *gin.Context) {
 		return
 	}
 
-	err = o.OrderService.PayOrder(id)
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	payment, err := o.PaymentService.CreatePayment(id, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "发起支付失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    payment,
+		"message": "发起支付成功",
+	})
+}
+
+// CancelOrderRequest 取消订单请求
+type CancelOrderRequest struct {
+	Reason string `json:"reason"` // 取消原因（可选）
+}
+
+// CancelOrder 取消订单
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理取消订单请求，只有订单所有者可以取消待支付的订单
+func (o *OrderController) CancelOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的订单ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	// 取消原因为可选参数，请求体为空时忽略
+	var req CancelOrderRequest
+	_ = c.ShouldBindJSON(&req)
+
+	err = o.OrderService.CancelOrder(id, userID.(int), req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "取消订单失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "取消订单成功",
+	})
+}
+
+// ConfirmReceipt 确认收货
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理确认收货请求，只有订单所有者可以确认已发货的订单，确认后订单完成
+func (o *OrderController) ConfirmReceipt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的订单ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	if err := o.OrderService.ConfirmReceipt(id, userID.(int)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "确认收货失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "确认收货成功",
+	})
+}
+
+// RequestRefund 申请售后
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理退款/退货申请，可指定部分订单项及数量，不指定时整单退款；
+// 申请提交后订单进入退款中状态，等待管理员审核
+func (o *OrderController) RequestRefund(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的订单ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	var req service.CreateRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	refund, err := o.RefundService.RequestRefund(id, userID.(int), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "申请售后失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    refund,
+		"message": "申请售后成功",
+	})
+}
+
+// GetUserRefunds 获取用户的售后申请列表
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取售后申请列表请求，支持分页参数
+func (o *OrderController) GetUserRefunds(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	refunds, total, err := o.RefundService.GetUserRefunds(userID.(int), page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code":    -1,
-			"message": "支付失败",
+			"message": "获取售后列表失败",
 			"error":   err.Error(),
 		})
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"refunds":     refunds,
+			"total":       total,
+			"page":        page,
+			"page_size":   pageSize,
+			"total_pages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+		"message": "获取售后列表成功",
+	})
+}
+
+// GetInvoice 获取订单发票
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取发票请求，只允许订单所有者查看已支付订单的发票，首次查看时自动开具；
+// 通过format查询参数选择html（默认，可直接打印）或pdf格式
+func (o *OrderController) GetInvoice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的订单ID",
+		})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	invoice, err := o.InvoiceService.GetUserInvoice(id, userID.(int))
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrOrderNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"code":    -1,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	writeInvoice(c, invoice)
+}
+
+// Reorder 按历史订单再次购买
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理再次购买请求，重新校验原订单中图书的状态、库存和价格，
+// 将可购买的图书加入购物车（默认）或直接创建待支付订单，并返回被跳过和价格变动的图书
+func (o *OrderController) Reorder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的订单ID",
+		})
+		return
+	}
+
+	var req service.ReorderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    -1,
+				"message": "请求参数错误",
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	result, err := o.CartService.Reorder(userID.(int), id, &req)
+	if err != nil {
+		var priceErr *service.PriceChangedError
+		switch {
+		case errors.Is(err, service.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    -1,
+				"message": err.Error(),
+			})
+		case errors.Is(err, service.ErrReorderUnavailable):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    -1,
+				"message": err.Error(),
+				"data":    result,
+			})
+		case errors.As(err, &priceErr):
+			c.JSON(http.StatusConflict, gin.H{
+				"code":    -1,
+				"message": priceErr.Error(),
+				"data":    gin.H{"changed_items": priceErr.Items},
+			})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    -1,
+				"message": "再次购买失败",
+				"error":   err.Error(),
+			})
+		}
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
-		"message": "支付成功",
+		"data":    result,
+		"message": "再次购买成功",
 	})
 }
 
@@ -207,3 +552,32 @@ func (o *OrderController) GetOrderStatistics(c *gin.Context) {
 		"message": "获取统计信息成功",
 	})
 }
+
+// writeInvoice 按请求的格式输出发票
+// 参数:
+//
+//	c - gin上下文，format查询参数为html（默认）或pdf
+//	invoice - 发票
+func writeInvoice(c *gin.Context, invoice *model.Invoice) {
+	switch c.DefaultQuery("format", "html") {
+	case "pdf":
+		c.Header("Content-Disposition", `inline; filename="`+invoice.InvoiceNo+`.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", service.RenderInvoicePDF(invoice))
+	case "html":
+		html, err := service.RenderInvoiceHTML(invoice)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    -1,
+				"message": "生成发票失败",
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", html)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "不支持的发票格式，可选html或pdf",
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"bookstore/service"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentSignatureHeader 支付回调签名所在的请求头
+const PaymentSignatureHeader = "X-Payment-Signature"
+
+// PaymentController 支付控制器
+// 负责处理支付回调、支付单查询以及模拟收银台请求
+type PaymentController struct {
+	PaymentService *service.PaymentService // 支付服务
+}
+
+// NewPaymentController 创建新的支付控制器实例
+// 返回:
+//
+//	*PaymentController - 初始化好的支付控制器
+func NewPaymentController() *PaymentController {
+	return &PaymentController{
+		PaymentService: service.NewPaymentService(),
+	}
+}
+
+// HandleCallback 处理支付渠道回调
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 读取原始报文和签名交给支付服务验签处理，验签失败或处理失败时返回非200状态码以便渠道重试
+func (p *PaymentController) HandleCallback(c *gin.Context) {
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "读取回调报文失败",
+		})
+		return
+	}
+
+	err = p.PaymentService.HandleCallback(c.Param("provider"), payload, c.GetHeader(PaymentSignatureHeader))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "处理支付回调失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// GetPayment 查询支付单
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理查询支付单请求，只能查询当前用户自己的支付单
+func (p *PaymentController) GetPayment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	payment, result, err := p.PaymentService.QueryPayment(c.Param("payment_no"), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    -1,
+			"message": "查询支付单失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"payment":         payment,
+			"provider_result": result,
+		},
+		"message": "查询支付单成功",
+	})
+}
+
+// CompleteMockPayment 模拟收银台完成支付
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 仅用于模拟支付渠道，只有支付单所属用户可以完成支付，模拟用户付款后由渠道发送签名回调
+func (p *PaymentController) CompleteMockPayment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":    -1,
+			"message": "用户未登录",
+		})
+		return
+	}
+
+	err := p.PaymentService.CompleteMockPayment(c.Param("transaction_id"), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "模拟支付失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "支付成功",
+	})
+}
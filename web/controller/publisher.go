@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"bookstore/model"
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublisherController 出版社控制器
+// 负责处理出版社详情页的HTTP请求
+type PublisherController struct {
+	PublisherService *service.PublisherService // 出版社服务
+}
+
+// NewPublisherController 创建新的出版社控制器实例
+// 返回:
+//
+//	*PublisherController - 初始化好的出版社控制器
+func NewPublisherController() *PublisherController {
+	return &PublisherController{
+		PublisherService: service.NewPublisherService(),
+	}
+}
+
+// GetPublisherDetail 获取出版社详情
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取出版社详情请求，返回出版社信息及其分页的上架图书，支持与图书列表相同的筛选和排序参数
+func (p *PublisherController) GetPublisherDetail(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的出版社ID",
+		})
+		return
+	}
+
+	var req model.BookQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Sort == model.BookSortRelevance {
+		req.Sort = "" // 没有关键词，按相关度排序无意义
+	}
+
+	detail, err := p.PublisherService.GetPublisherDetail(id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrPublisherNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    -1,
+				"message": "出版社不存在",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取出版社详情失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    detail,
+		"message": "获取出版社详情成功",
+	})
+}
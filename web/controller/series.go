@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"bookstore/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeriesController 丛书控制器
+// 负责处理丛书详情页的HTTP请求
+type SeriesController struct {
+	SeriesService *service.SeriesService // 丛书服务
+}
+
+// NewSeriesController 创建新的丛书控制器实例
+// 返回:
+//
+//	*SeriesController - 初始化好的丛书控制器
+func NewSeriesController() *SeriesController {
+	return &SeriesController{
+		SeriesService: service.NewSeriesService(),
+	}
+}
+
+// GetSeriesDetail 获取丛书详情
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取丛书详情请求，返回丛书信息及按卷号排列的全部上架图书
+func (s *SeriesController) GetSeriesDetail(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "无效的丛书ID",
+		})
+		return
+	}
+
+	detail, err := s.SeriesService.GetSeriesDetail(id)
+	if err != nil {
+		if errors.Is(err, service.ErrSeriesNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    -1,
+				"message": "丛书不存在",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取丛书详情失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    detail,
+		"message": "获取丛书详情成功",
+	})
+}
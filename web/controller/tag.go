@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"bookstore/model"
+	"bookstore/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagController 标签控制器
+// 负责处理按标签浏览图书的HTTP请求
+type TagController struct {
+	TagService *service.TagService // 标签服务
+}
+
+// NewTagController 创建新的标签控制器实例
+// 返回:
+//
+//	*TagController - 初始化好的标签控制器
+func NewTagController() *TagController {
+	return &TagController{
+		TagService: service.NewTagService(),
+	}
+}
+
+// GetTags 获取标签列表
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理获取标签列表请求，返回带有上架图书的标签及其图书数量
+func (t *TagController) GetTags(c *gin.Context) {
+	tags, err := t.TagService.GetPublicTags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取标签列表失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    tags,
+		"message": "获取标签列表成功",
+	})
+}
+
+// GetTagBooks 获取标签下的图书
+// 参数:
+//
+//	c - Gin上下文对象，包含HTTP请求和响应信息
+//
+// 处理按标签浏览图书请求，返回标签信息及其分页的上架图书，支持与图书列表相同的筛选和排序参数
+func (t *TagController) GetTagBooks(c *gin.Context) {
+	var req model.BookQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    -1,
+			"message": "请求参数错误",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Sort == model.BookSortRelevance {
+		req.Sort = "" // 没有关键词，按相关度排序无意义
+	}
+
+	detail, err := t.TagService.GetTagBooks(c.Param("slug"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    -1,
+				"message": "标签不存在",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "获取标签图书失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"data":    detail,
+		"message": "获取标签图书成功",
+	})
+}
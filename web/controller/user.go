@@ -4,6 +4,7 @@ import (
 	"bookstore/jwt"
 	"bookstore/model"
 	"bookstore/service"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -12,6 +13,7 @@ import (
 // UserController 用户控制器，处理所有用户相关的HTTP请求
 type UserController struct {
 	UserService *service.UserService // 用户服务层实例
+	CartService *service.CartService // 购物车服务，用于登录时合并访客购物车
 }
 
 // NewUserController 创建用户控制器实例
@@ -21,6 +23,7 @@ type UserController struct {
 func NewUserController() *UserController {
 	return &UserController{
 		UserService: service.NewUserService(), // 初始化用户服务
+		CartService: service.NewCartService(), // 初始化购物车服务
 	}
 }
 
@@ -41,6 +44,7 @@ type LoginRequest struct {
 	Password     string `json:"password" binding:"required"`      // 密码（必填）
 	CaptchaID    string `json:"captcha_id" binding:"required"`    // 验证码ID（必填）
 	CaptchaValue string `json:"captcha_value" binding:"required"` // 验证码值（必填）
+	CartToken    string `json:"cart_token"`                       // 访客购物车令牌（可选），登录后合并到用户购物车
 }
 
 // Register 处理用户注册请求
@@ -127,6 +131,20 @@ func (u *UserController) Login(c *gin.Context) {
 		return
 	}
 
+	// 合并访客购物车，令牌可通过请求体或X-Cart-Token请求头传入
+	// 合并失败不影响登录，访客购物车保留到过期，可在下次登录时再次合并
+	cartToken := req.CartToken
+	if cartToken == "" {
+		cartToken = c.GetHeader(cartTokenHeader)
+	}
+	if service.ValidGuestCartToken(cartToken) {
+		merge, err := u.CartService.MergeGuestCart(loginResponse.UserInfo.ID, cartToken)
+		if err != nil {
+			log.Printf("合并访客购物车失败，用户ID: %d，错误: %v", loginResponse.UserInfo.ID, err)
+		}
+		loginResponse.CartMerge = merge
+	}
+
 	// 返回成功响应（包含token等登录信息）
 	c.JSON(http.StatusOK, gin.H{
 		"code":    0,
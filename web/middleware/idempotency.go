@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"bookstore/repository"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader 客户端传入幂等键的请求头
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader 标记响应为重放结果的响应头
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength 幂等键的最大长度
+	maxIdempotencyKeyLength = 128
+	// idempotencyLockTTL 请求处理期间占用幂等键的最长时间
+	idempotencyLockTTL = time.Minute
+)
+
+// bodyRecorder 在写出响应的同时记录响应体
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 写出响应体并保留一份副本
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 写出字符串响应体并保留一份副本
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware 幂等键中间件
+// 作用：对携带Idempotency-Key请求头的非安全请求去重，防止重复提交产生重复订单或重复支付
+// 同一用户使用同一幂等键和相同请求重试时重放首次成功的响应；
+// 幂等键被用于不同的请求，或首次请求仍在处理中时返回409。
+// 首次请求失败（非2xx）时释放幂等键，客户端修正后可使用同一幂等键重试。
+// 需放在JWTAuthMiddleware之后，幂等键按用户隔离
+// 参数:
+//
+//	ttl - 首次响应的保留时间
+//
+// 返回值：gin.HandlerFunc 中间件函数
+func IdempotencyMiddleware(ttl time.Duration) gin.HandlerFunc {
+	idempotencyDAO := repository.NewIdempotencyDAO()
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		// 未携带幂等键或安全方法的请求不做处理
+		if key == "" || isSafeMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    -1,
+				"message": fmt.Sprintf("Idempotency-Key长度不能超过%d个字符", maxIdempotencyKeyLength),
+			})
+			c.Abort()
+			return
+		}
+
+		// 读取请求体计算请求指纹，再放回供后续处理函数使用
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    -1,
+				"message": "读取请求体失败",
+				"error":   err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		storeKey := fmt.Sprintf("%d:%s", c.GetInt("userID"), key)
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		acquired, err := idempotencyDAO.Acquire(storeKey, &repository.IdempotencyRecord{Fingerprint: fingerprint}, idempotencyLockTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    -1,
+				"message": "幂等键校验失败",
+				"error":   err.Error(),
+			})
+			c.Abort()
+			return
+		}
+		if !acquired {
+			replayIdempotentResponse(c, idempotencyDAO, storeKey, fingerprint)
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		// 处理函数panic时同样释放幂等键，panic继续交给Recovery中间件处理
+		completed := false
+		defer func() {
+			if !completed {
+				_ = idempotencyDAO.Release(storeKey)
+			}
+		}()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return
+		}
+		record := &repository.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := idempotencyDAO.Save(storeKey, record, ttl); err != nil {
+			// 保存失败时由defer释放幂等键，避免后续重试一直收到处理中的响应
+			log.Printf("保存幂等响应失败，幂等键: %s，错误: %v", storeKey, err)
+			return
+		}
+		completed = true
+	}
+}
+
+// replayIdempotentResponse 处理使用已占用幂等键的请求
+// 参数:
+//
+//	c - gin上下文
+//	idempotencyDAO - 幂等键数据访问对象
+//	storeKey - 按用户隔离后的幂等键
+//	fingerprint - 当前请求的指纹
+func replayIdempotentResponse(c *gin.Context, idempotencyDAO *repository.IdempotencyDAO, storeKey, fingerprint string) {
+	defer c.Abort()
+
+	record, err := idempotencyDAO.Get(storeKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    -1,
+			"message": "幂等键校验失败",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if record == nil {
+		// 首次请求恰好在两次读取之间失败并释放了幂等键
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    -1,
+			"message": "相同请求的处理状态已变化，请重试",
+		})
+		return
+	}
+	if record.Fingerprint != fingerprint {
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    -1,
+			"message": "Idempotency-Key已用于不同的请求，请为新的请求使用新的Idempotency-Key",
+		})
+		return
+	}
+	if !record.Completed {
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    -1,
+			"message": "相同请求正在处理中，请稍后重试",
+		})
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.StatusCode, record.ContentType, record.Body)
+}
+
+// requestFingerprint 计算请求指纹
+// 参数:
+//
+//	method - 请求方法
+//	path - 请求路径
+//	body - 请求体
+//
+// 返回:
+//
+//	string - 十六进制编码的SHA-256摘要
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isSafeMethod 判断请求方法是否为不会修改数据的安全方法
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -23,7 +23,7 @@ func InitAdminRouter() *gin.Engine {
 		// 允许的请求头
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 		// 暴露给客户端的响应头
-		c.Header("Access-Control-Expose-Headers", "Content-Length")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, Content-Disposition")
 		// 允许携带凭证（如cookies）
 		c.Header("Access-Control-Allow-Credentials", "true")
 
@@ -55,21 +55,106 @@ func InitAdminRouter() *gin.Engine {
 		// ----- 图书管理 ----- //
 		books := admin.Group("/books")
 		{
-			books.GET("/list", controller.NewAdminBookController().GetBookList)            // 获取图书列表
-			books.GET("/:id", controller.NewAdminBookController().GetBookByID)             // 获取图书详情
-			books.POST("/create", controller.NewAdminBookController().CreateBook)          // 创建图书
-			books.PUT("/:id", controller.NewAdminBookController().UpdateBook)              // 更新图书信息
-			books.DELETE("/:id", controller.NewAdminBookController().DeleteBook)           // 删除图书
-			books.PUT("/:id/status", controller.NewAdminBookController().UpdateBookStatus) // 更新图书状态
+			books.GET("/list", controller.NewAdminBookController().GetBookList)             // 获取图书列表
+			books.GET("/:id", controller.NewAdminBookController().GetBookByID)              // 获取图书详情
+			books.POST("/create", controller.NewAdminBookController().CreateBook)           // 创建图书
+			books.PUT("/:id", controller.NewAdminBookController().UpdateBook)               // 更新图书信息
+			books.DELETE("/:id", controller.NewAdminBookController().DeleteBook)            // 删除图书
+			books.PUT("/:id/status", controller.NewAdminBookController().UpdateBookStatus)  // 更新图书状态
+			books.PUT("/:id/grouping", controller.NewAdminBookController().SetBookGrouping) // 设置图书所属作品和丛书
 		}
 
 		// ----- 分类管理 ----- //
 		categories := admin.Group("/categories")
 		{
-			categories.GET("/list", controller.NewAdminBookController().GetCategories)     // 获取分类列表
-			categories.POST("/create", controller.NewAdminBookController().CreateCategory) // 创建分类
-			categories.PUT("/:id", controller.NewAdminBookController().UpdateCategory)     // 更新分类信息
-			categories.DELETE("/:id", controller.NewAdminBookController().DeleteCategory)  // 删除分类
+			categories.GET("/list", controller.NewAdminBookController().GetCategories)            // 获取分类列表
+			categories.POST("/create", controller.NewAdminBookController().CreateCategory)        // 创建分类
+			categories.PUT("/:id", controller.NewAdminBookController().UpdateCategory)            // 更新分类信息
+			categories.DELETE("/:id", controller.NewAdminBookController().DeleteCategory)         // 删除分类
+			categories.PUT("/:id/move", controller.NewAdminBookController().MoveCategory)         // 移动分类（含子分类）
+			categories.POST("/recount", controller.NewAdminBookController().RecountCategoryBooks) // 重新统计分类图书数量
+		}
+
+		// ----- 作者和出版社管理 ----- //
+		authors := admin.Group("/authors")
+		{
+			authors.GET("/list", controller.NewAdminContributorController().GetAuthors)     // 获取作者列表
+			authors.POST("/merge", controller.NewAdminContributorController().MergeAuthors) // 合并重复作者
+		}
+		publishers := admin.Group("/publishers")
+		{
+			publishers.GET("/list", controller.NewAdminContributorController().GetPublishers)     // 获取出版社列表
+			publishers.POST("/merge", controller.NewAdminContributorController().MergePublishers) // 合并重复出版社
+		}
+		admin.POST("/contributors/migrate", controller.NewAdminContributorController().MigrateContributors) // 按图书的署名文字关联作者和出版社
+
+		// ----- 作品和丛书管理 ----- //
+		works := admin.Group("/works")
+		{
+			works.GET("/list", controller.NewAdminSeriesController().GetWorks)      // 获取作品列表
+			works.POST("/create", controller.NewAdminSeriesController().CreateWork) // 创建作品
+			works.PUT("/:id", controller.NewAdminSeriesController().UpdateWork)     // 更新作品
+			works.DELETE("/:id", controller.NewAdminSeriesController().DeleteWork)  // 删除作品
+		}
+		series := admin.Group("/series")
+		{
+			series.GET("/list", controller.NewAdminSeriesController().GetSeriesList)   // 获取丛书列表
+			series.POST("/create", controller.NewAdminSeriesController().CreateSeries) // 创建丛书
+			series.PUT("/:id", controller.NewAdminSeriesController().UpdateSeries)     // 更新丛书
+			series.DELETE("/:id", controller.NewAdminSeriesController().DeleteSeries)  // 删除丛书
+		}
+
+		// ----- 标签管理 ----- //
+		tags := admin.Group("/tags")
+		{
+			tags.GET("/list", controller.NewAdminTagController().GetTags)             // 获取标签列表
+			tags.POST("/create", controller.NewAdminTagController().CreateTag)        // 创建标签
+			tags.PUT("/:id", controller.NewAdminTagController().UpdateTag)            // 更新标签
+			tags.DELETE("/:id", controller.NewAdminTagController().DeleteTag)         // 删除标签
+			tags.POST("/assign", controller.NewAdminTagController().AddBookTags)      // 批量为图书添加标签
+			tags.POST("/unassign", controller.NewAdminTagController().RemoveBookTags) // 批量移除图书的标签
+		}
+
+		// ----- 订单管理 ----- //
+		orders := admin.Group("/orders")
+		{
+			orders.GET("/list", controller.NewAdminOrderController().GetOrderList)                 // 获取订单列表
+			orders.GET("/export", controller.NewAdminOrderController().ExportOrders)               // 导出订单（CSV/XLSX）
+			orders.GET("/:id", controller.NewAdminOrderController().GetOrderByID)                  // 获取订单详情
+			orders.PUT("/:id/status", controller.NewAdminOrderController().UpdateOrderStatus)      // 更新订单状态
+			orders.GET("/:id/shipments", controller.NewAdminOrderController().GetOrderShipments)   // 获取发货记录
+			orders.POST("/:id/shipments", controller.NewAdminOrderController().CreateShipment)     // 订单发货
+			orders.PUT("/shipments/:id", controller.NewAdminOrderController().UpdateShipment)      // 更新发货记录
+			orders.GET("/:id/invoices", controller.NewAdminInvoiceController().GetOrderInvoices)   // 获取订单发票列表
+			orders.POST("/:id/invoices", controller.NewAdminInvoiceController().RegenerateInvoice) // 重新开具发票
+		}
+
+		// ----- 发票管理 ----- //
+		invoices := admin.Group("/invoices")
+		{
+			invoices.GET("/:id", controller.NewAdminInvoiceController().GetInvoice)       // 查看或下载发票
+			invoices.PUT("/:id/void", controller.NewAdminInvoiceController().VoidInvoice) // 作废发票
+		}
+
+		// ----- 售后管理 ----- //
+		refunds := admin.Group("/refunds")
+		{
+			refunds.GET("/list", controller.NewAdminRefundController().GetRefundList)        // 获取售后申请列表
+			refunds.GET("/:id", controller.NewAdminRefundController().GetRefundByID)         // 获取售后申请详情
+			refunds.PUT("/:id/approve", controller.NewAdminRefundController().ApproveRefund) // 同意售后申请
+			refunds.PUT("/:id/reject", controller.NewAdminRefundController().RejectRefund)   // 拒绝售后申请
+		}
+
+		// ----- 优惠券管理 ----- //
+		coupons := admin.Group("/coupons")
+		{
+			coupons.GET("/list", controller.NewAdminCouponController().GetCouponList)             // 获取优惠券列表
+			coupons.GET("/:id", controller.NewAdminCouponController().GetCouponByID)              // 获取优惠券详情
+			coupons.POST("/create", controller.NewAdminCouponController().CreateCoupon)           // 创建优惠券
+			coupons.PUT("/:id", controller.NewAdminCouponController().UpdateCoupon)               // 更新优惠券
+			coupons.DELETE("/:id", controller.NewAdminCouponController().DeleteCoupon)            // 删除优惠券
+			coupons.GET("/:id/stats", controller.NewAdminCouponController().GetCouponStats)       // 获取核销统计
+			coupons.GET("/:id/redemptions", controller.NewAdminCouponController().GetRedemptions) // 获取使用记录
 		}
 
 		// ----- 用户管理 ----- //
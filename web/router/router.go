@@ -1,6 +1,7 @@
 package router
 
 import (
+	"bookstore/config"
 	"bookstore/web/controller"
 	"bookstore/web/middleware"
 
@@ -21,9 +22,9 @@ func InitRouter() *gin.Engine {
 		// 允许的HTTP方法
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		// 允许的请求头
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Cart-Token, Idempotency-Key")
 		// 暴露给客户端的响应头
-		c.Header("Access-Control-Expose-Headers", "Content-Length")
+		c.Header("Access-Control-Expose-Headers", "Content-Length, Idempotent-Replayed")
 		// 允许携带凭证（如cookies）
 		c.Header("Access-Control-Allow-Credentials", "true")
 
@@ -38,13 +39,23 @@ func InitRouter() *gin.Engine {
 	// ========== 初始化依赖组件 ========== //
 
 	// 创建Controller控制器实例
-	userController := controller.NewUserController()         // 用户控制器
-	captchaController := controller.NewCaptchaController()   // 验证码控制器
-	bookController := controller.NewBookController()         // 书籍控制器
-	categoryController := controller.NewCategoryController() // 分类控制器
-	orderController := controller.NewOrderController()       // 订单控制器
-	favoriteController := controller.NewFavoriteController() // 收藏控制器（注入服务）
-	carouselController := controller.NewCarouselController() // 轮播图控制器（注入服务）
+	userController := controller.NewUserController()           // 用户控制器
+	captchaController := controller.NewCaptchaController()     // 验证码控制器
+	bookController := controller.NewBookController()           // 书籍控制器
+	categoryController := controller.NewCategoryController()   // 分类控制器
+	authorController := controller.NewAuthorController()       // 作者控制器
+	publisherController := controller.NewPublisherController() // 出版社控制器
+	seriesController := controller.NewSeriesController()       // 丛书控制器
+	tagController := controller.NewTagController()             // 标签控制器
+	orderController := controller.NewOrderController()         // 订单控制器
+	favoriteController := controller.NewFavoriteController()   // 收藏控制器（注入服务）
+	carouselController := controller.NewCarouselController()   // 轮播图控制器（注入服务）
+	paymentController := controller.NewPaymentController()     // 支付控制器
+	addressController := controller.NewAddressController()     // 收货地址控制器
+	cartController := controller.NewCartController()           // 购物车控制器
+
+	// 订单和购物车的非安全请求支持Idempotency-Key去重，防止重复下单和重复支付
+	idempotency := middleware.IdempotencyMiddleware(config.AppConfig.Idempotency.TTL)
 
 	// ========== 路由注册 ========== //
 
@@ -66,6 +77,14 @@ func InitRouter() *gin.Engine {
 				auth.PUT("/profile", userController.UpdateUserProfile) // 更新用户资料
 				auth.PUT("/password", userController.ChangePassword)   // 修改密码
 				auth.DELETE("/logout", userController.Logout)          // 用户登出
+
+				// 收货地址簿
+				auth.GET("/addresses", addressController.GetAddresses)                  // 获取收货地址列表
+				auth.GET("/addresses/:id", addressController.GetAddress)                // 获取收货地址详情
+				auth.POST("/addresses", addressController.CreateAddress)                // 添加收货地址
+				auth.PUT("/addresses/:id", addressController.UpdateAddress)             // 更新收货地址
+				auth.DELETE("/addresses/:id", addressController.DeleteAddress)          // 删除收货地址
+				auth.PUT("/addresses/:id/default", addressController.SetDefaultAddress) // 设置默认收货地址
 			}
 		}
 
@@ -76,14 +95,39 @@ func InitRouter() *gin.Engine {
 			book.GET("/new", bookController.GetNewBooks)                       // 获取新书上架
 			book.GET("/list", bookController.GetBookList)                      // 获取书籍列表
 			book.GET("/search", bookController.SearchBooks)                    // 搜索书籍
+			book.GET("/suggest", bookController.SuggestBooks)                  // 搜索建议
 			book.GET("/detail/:id", bookController.GetBookDetail)              // 获取书籍详情
 			book.GET("/category/:category", bookController.GetBooksByCategory) // 按分类获取书籍
 		}
 
+		// ----- 作者和出版社相关路由 ----- //
+		author := v1.Group("/author")
+		{
+			author.GET("/:id", authorController.GetAuthorDetail) // 获取作者详情及其图书
+		}
+		publisher := v1.Group("/publisher")
+		{
+			publisher.GET("/:id", publisherController.GetPublisherDetail) // 获取出版社详情及其图书
+		}
+
+		// ----- 丛书相关路由 ----- //
+		series := v1.Group("/series")
+		{
+			series.GET("/:id", seriesController.GetSeriesDetail) // 获取丛书详情及按卷号排列的图书
+		}
+
+		// ----- 标签相关路由 ----- //
+		tag := v1.Group("/tag")
+		{
+			tag.GET("/list", tagController.GetTags)            // 获取标签列表
+			tag.GET("/:slug/books", tagController.GetTagBooks) // 获取标签下的图书
+		}
+
 		// ----- 分类相关路由 ----- //
 		category := v1.Group("/category")
 		{
 			category.GET("/list", categoryController.GetCategories)     // 获取全部分类
+			category.GET("/tree", categoryController.GetCategoryTree)   // 获取分类树
 			category.GET("/:id", categoryController.GetCategoryByID)    // 获取分类详情
 			category.POST("/create", categoryController.CreateCategory) // 创建分类
 			category.PUT("/:id", categoryController.UpdateCategory)     // 更新分类
@@ -92,15 +136,55 @@ func InitRouter() *gin.Engine {
 
 		// ----- 订单相关路由 ----- //
 		order := v1.Group("/order")
-		order.Use(middleware.JWTAuthMiddleware()) // 需要登录
+		order.Use(middleware.JWTAuthMiddleware(), idempotency) // 需要登录，支持幂等键
 		{
 			order.POST("/create", orderController.CreateOrder)           // 创建订单
 			order.GET("/:id", orderController.GetOrderByID)              // 获取订单详情
 			order.GET("/list", orderController.GetUserOrders)            // 获取用户订单列表
 			order.POST("/:id/pay", orderController.PayOrder)             // 支付订单
+			order.POST("/:id/cancel", orderController.CancelOrder)       // 取消订单
+			order.POST("/:id/confirm", orderController.ConfirmReceipt)   // 确认收货
+			order.POST("/:id/refund", orderController.RequestRefund)     // 申请售后
+			order.GET("/refunds", orderController.GetUserRefunds)        // 获取售后申请列表
+			order.GET("/:id/invoice", orderController.GetInvoice)        // 获取订单发票（HTML或PDF）
+			order.POST("/:id/reorder", orderController.Reorder)          // 再次购买
 			order.GET("/statistics", orderController.GetOrderStatistics) // 订单统计
 		}
 
+		// ----- 购物车相关路由 ----- //
+		cart := v1.Group("/cart")
+		cart.Use(middleware.JWTAuthMiddleware(), idempotency) // 需要登录，支持幂等键
+		{
+			cart.GET("", cartController.GetCart)                      // 获取购物车
+			cart.DELETE("", cartController.ClearCart)                 // 清空购物车
+			cart.POST("/items", cartController.AddItem)               // 添加图书到购物车
+			cart.PUT("/items/:book_id", cartController.UpdateItem)    // 修改购物车中图书的数量
+			cart.DELETE("/items/:book_id", cartController.RemoveItem) // 从购物车移除图书
+			cart.POST("/checkout", cartController.Checkout)           // 结算购物车
+		}
+		guestCart := v1.Group("/cart/guest") // 访客购物车，无需登录，通过X-Cart-Token请求头识别
+		{
+			guestCart.GET("", cartController.GetGuestCart)                      // 获取访客购物车
+			guestCart.DELETE("", cartController.ClearGuestCart)                 // 清空访客购物车
+			guestCart.POST("/items", cartController.AddGuestItem)               // 添加图书到访客购物车
+			guestCart.PUT("/items/:book_id", cartController.UpdateGuestItem)    // 修改访客购物车中图书的数量
+			guestCart.DELETE("/items/:book_id", cartController.RemoveGuestItem) // 从访客购物车移除图书
+		}
+
+		// ----- 支付相关路由 ----- //
+		payment := v1.Group("/payment")
+		{
+			// 支付渠道回调，通过签名校验而非JWT认证
+			payment.POST("/callback/:provider", paymentController.HandleCallback) // 支付回调
+
+			auth := payment.Group("")
+			auth.Use(middleware.JWTAuthMiddleware()) // 需要登录
+			{
+				auth.GET("/:payment_no", paymentController.GetPayment)                             // 查询支付单
+				auth.POST("/mock/:transaction_id/complete", paymentController.CompleteMockPayment) // 模拟收银台完成支付（仅支付单所属用户）
+			}
+		}
+
 		// ----- 收藏相关路由 ----- //
 		favorite := v1.Group("/favorite")
 		favorite.Use(middleware.JWTAuthMiddleware()) // 需要登录